@@ -0,0 +1,229 @@
+package main
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/AccumulateNetwork/accumulate/config"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/spf13/cobra"
+)
+
+var cmdDb = &cobra.Command{
+	Use:   "db",
+	Short: "Inspect and repair the application database",
+	Long:  "Inspect and repair the application database. The node must be stopped.",
+	Run:   printUsageAndExit1,
+}
+
+var cmdDbDump = &cobra.Command{
+	Use:   "dump [file]",
+	Short: "Export every record of the database",
+	Long: `Export every record of the database to the file, or to stdout. Records are
+labelled with what they are, such as a state entry, a transaction, or an
+element of a Merkle chain. The JSON format writes one record per line. The dump
+fails if the database holds records that cannot be identified.`,
+	Args: cobra.MaximumNArgs(1),
+	Run:  dbDump,
+}
+
+var cmdDbRestore = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Rebuild the database from a dump",
+	Long: `Rebuild the database from a dump, then verify it. The database must be empty.
+If the restore fails, the database must be deleted before trying again.`,
+	Args: cobra.ExactArgs(1),
+	Run:  dbRestore,
+}
+
+var cmdDbVerify = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the consistency of the database",
+	Long: `Recompute the root of every Merkle chain and the BPT from the records of the
+database and compare them with the stored roots and the root hash of the last
+block. Exits with a non-zero status if any problem is found.`,
+	Args: cobra.NoArgs,
+	Run:  dbVerify,
+}
+
+var cmdDbVerifyChains = &cobra.Command{
+	Use:   "verify-chains",
+	Short: "Check every Merkle chain against its stored states",
+	Long: `Replay every element of every Merkle chain and check the element index, the
+state stored at each mark, and the head of the chain. Reports the first
+inconsistent index of each chain, and exits with a non-zero status if any
+chain is inconsistent.`,
+	Args: cobra.NoArgs,
+	Run:  dbVerifyChains,
+}
+
+var flagDb struct {
+	Node   int
+	Format string
+}
+
+func init() {
+	cmdMain.AddCommand(cmdDb)
+	cmdDb.AddCommand(cmdDbDump, cmdDbRestore, cmdDbVerify, cmdDbVerifyChains)
+
+	cmdDb.PersistentFlags().IntVarP(&flagDb.Node, "node", "n", -1, "Which node are we? [0, n)")
+	cmdDbDump.Flags().StringVarP(&flagDb.Format, "format", "f", "json", "Format of the dump: json or binary")
+	cmdDbRestore.Flags().StringVarP(&flagDb.Format, "format", "f", "json", "Format of the dump: json or binary")
+}
+
+// dumpRecordJSON is the JSON format of a dump record, with hex-encoded bytes
+type dumpRecordJSON struct {
+	Kind  string        `json:"kind"`
+	Chain types.Bytes   `json:"chain,omitempty"`
+	Index int64         `json:"index,omitempty"`
+	Key   types.Bytes32 `json:"key"`
+	Value types.Bytes   `json:"value"`
+}
+
+func openNodeDB(cmd *cobra.Command) *state.StateDB {
+	workDir := flagMain.WorkDir
+	if cmd.Flag("node").Changed {
+		workDir = filepath.Join(workDir, fmt.Sprintf("Node%d", flagDb.Node))
+	}
+
+	cfg, err := config.Load(workDir)
+	checkf(err, "reading config file")
+
+	db, err := openDB(cfg, false)
+	check(err)
+	return db
+}
+
+func dbDump(cmd *cobra.Command, args []string) {
+	db := openNodeDB(cmd)
+	defer func() { _ = db.GetDB().Close() }()
+
+	out := os.Stdout
+	if len(args) > 0 {
+		f, err := os.Create(args[0])
+		checkf(err, "failed to create %s", args[0])
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+
+	var write func(*state.DumpRecord) error
+	switch flagDb.Format {
+	case "json":
+		enc := json.NewEncoder(w)
+		write = func(rec *state.DumpRecord) error {
+			return enc.Encode(&dumpRecordJSON{rec.Kind, rec.Chain, rec.Index, rec.Key, rec.Value})
+		}
+	case "binary":
+		write = func(rec *state.DumpRecord) error {
+			b, err := rec.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			var size [binary.MaxVarintLen64]byte
+			_, err = w.Write(size[:binary.PutUvarint(size[:], uint64(len(b)))])
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(b)
+			return err
+		}
+	default:
+		fatalf("unknown format %q", flagDb.Format)
+	}
+
+	check(db.Dump(write))
+	check(w.Flush())
+}
+
+func dbRestore(cmd *cobra.Command, args []string) {
+	f, err := os.Open(args[0])
+	checkf(err, "failed to open %s", args[0])
+	defer f.Close()
+	r := bufio.NewReader(f)
+
+	var next func() (*state.DumpRecord, error)
+	switch flagDb.Format {
+	case "json":
+		dec := json.NewDecoder(r)
+		next = func() (*state.DumpRecord, error) {
+			rec := new(dumpRecordJSON)
+			err := dec.Decode(rec)
+			if err != nil {
+				return nil, err
+			}
+			return &state.DumpRecord{Kind: rec.Kind, Chain: rec.Chain, Index: rec.Index, Key: rec.Key, Value: rec.Value}, nil
+		}
+	case "binary":
+		next = func() (*state.DumpRecord, error) {
+			size, err := binary.ReadUvarint(r)
+			if err != nil {
+				return nil, err
+			}
+			b := make([]byte, size)
+			_, err = io.ReadFull(r, b)
+			if err != nil {
+				return nil, fmt.Errorf("truncated record: %v", err)
+			}
+			rec := new(state.DumpRecord)
+			err = rec.UnmarshalBinary(b)
+			if err != nil {
+				return nil, fmt.Errorf("invalid record: %v", err)
+			}
+			return rec, nil
+		}
+	default:
+		fatalf("unknown format %q", flagDb.Format)
+	}
+
+	db := openNodeDB(cmd)
+	defer func() { _ = db.GetDB().Close() }()
+
+	checkf(db.Restore(next), "failed to restore %s", args[0])
+	height, err := db.BlockIndex()
+	checkf(err, "failed to load the block index")
+	fmt.Printf("Restored height %d, root hash %X\n", height, db.RootHash())
+
+	verify(db)
+}
+
+func dbVerify(cmd *cobra.Command, _ []string) {
+	db := openNodeDB(cmd)
+	defer func() { _ = db.GetDB().Close() }()
+	verify(db)
+}
+
+func verify(db *state.StateDB) {
+	var problems int
+	err := db.Verify(func(problem error) {
+		problems++
+		fmt.Fprintln(os.Stderr, problem)
+	})
+	check(err)
+
+	if problems > 0 {
+		fatalf("found %d problems", problems)
+	}
+	fmt.Println("The database is consistent")
+}
+
+func dbVerifyChains(cmd *cobra.Command, _ []string) {
+	db := openNodeDB(cmd)
+	defer func() { _ = db.GetDB().Close() }()
+
+	var problems int
+	db.VerifyChains(func(problem error) {
+		problems++
+		fmt.Fprintln(os.Stderr, problem)
+	})
+	if problems > 0 {
+		fatalf("found %d inconsistent chains", problems)
+	}
+	fmt.Println("Every chain is consistent")
+}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/AccumulateNetwork/accumulate/config"
 	cfg "github.com/AccumulateNetwork/accumulate/config"
@@ -44,9 +45,11 @@ var cmdInitDevnet = &cobra.Command{
 }
 
 var flagInit struct {
-	Net           string
-	NoEmptyBlocks bool
-	NoWebsite     bool
+	Net                string
+	NoEmptyBlocks      bool
+	NoWebsite          bool
+	MajorBlockInterval int64
+	MajorBlockPeriod   time.Duration
 }
 
 var flagInitFollower struct {
@@ -70,6 +73,8 @@ func init() {
 	cmdInit.PersistentFlags().StringVarP(&flagInit.Net, "network", "n", "", "Node to build configs for")
 	cmdInit.PersistentFlags().BoolVar(&flagInit.NoEmptyBlocks, "no-empty-blocks", false, "Do not create empty blocks")
 	cmdInit.PersistentFlags().BoolVar(&flagInit.NoWebsite, "no-website", false, "Disable website")
+	cmdInit.PersistentFlags().Int64Var(&flagInit.MajorBlockInterval, "major-block-interval", 0, "Number of blocks in a major block, zero for no limit (written to the genesis document)")
+	cmdInit.PersistentFlags().DurationVar(&flagInit.MajorBlockPeriod, "major-block-period", time.Hour, "Length of time a major block covers, zero for no limit (written to the genesis document)")
 	cmdInit.MarkFlagRequired("network")
 
 	cmdInitFollower.Flags().StringVar(&flagInitFollower.GenesisDoc, "genesis-doc", "", "Genesis doc for the target network")
@@ -145,6 +150,9 @@ func initNode(*cobra.Command, []string) {
 		Config:    config,
 		RemoteIP:  remoteIP,
 		ListenIP:  listenIP,
+
+		MajorBlockInterval: flagInit.MajorBlockInterval,
+		MajorBlockPeriod:   flagInit.MajorBlockPeriod,
 	}))
 }
 
@@ -282,6 +290,9 @@ func initDevNet(cmd *cobra.Command, args []string) {
 		Config:    config[:flagInitDevnet.NumDirNodes],
 		RemoteIP:  IPs[:flagInitDevnet.NumDirNodes],
 		ListenIP:  IPs[:flagInitDevnet.NumDirNodes],
+
+		MajorBlockInterval: flagInit.MajorBlockInterval,
+		MajorBlockPeriod:   flagInit.MajorBlockPeriod,
 	}))
 	check(node.Init(node.InitOptions{
 		WorkDir:   filepath.Join(flagMain.WorkDir, "bvn"),
@@ -291,5 +302,8 @@ func initDevNet(cmd *cobra.Command, args []string) {
 		Config:    config[flagInitDevnet.NumDirNodes:],
 		RemoteIP:  IPs[flagInitDevnet.NumDirNodes:],
 		ListenIP:  IPs[flagInitDevnet.NumDirNodes:],
+
+		MajorBlockInterval: flagInit.MajorBlockInterval,
+		MajorBlockPeriod:   flagInit.MajorBlockPeriod,
 	}))
 }
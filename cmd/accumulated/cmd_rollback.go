@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/AccumulateNetwork/accumulate/config"
+	"github.com/spf13/cobra"
+)
+
+var cmdRollback = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back the application state to a previous height",
+	Long: `Roll back the application state to a previous height. The node must be stopped.
+The state can only be rolled back by as many blocks as the node's undo retention.
+Tendermint's state must be rolled back to the same height separately.`,
+	Run: rollback,
+}
+
+var flagRollback struct {
+	Node   int
+	Height int64
+}
+
+func init() {
+	cmdMain.AddCommand(cmdRollback)
+
+	cmdRollback.Flags().IntVarP(&flagRollback.Node, "node", "n", -1, "Which node are we? [0, n)")
+	cmdRollback.Flags().Int64Var(&flagRollback.Height, "height", 0, "Height to roll back to")
+	_ = cmdRollback.MarkFlagRequired("height")
+}
+
+func rollback(cmd *cobra.Command, _ []string) {
+	workDir := flagMain.WorkDir
+	if cmd.Flag("node").Changed {
+		workDir = filepath.Join(workDir, fmt.Sprintf("Node%d", flagRollback.Node))
+	}
+
+	cfg, err := config.Load(workDir)
+	checkf(err, "reading config file")
+
+	db, err := openDB(cfg, false)
+	check(err)
+	defer func() { _ = db.GetDB().Close() }()
+
+	from, err := db.BlockIndex()
+	checkf(err, "failed to load the block index")
+
+	err = db.Rollback(flagRollback.Height)
+	checkf(err, "failed to roll back")
+
+	fmt.Printf("Rolled back from height %d to %d, root hash %X\n", from, flagRollback.Height, db.RootHash())
+}
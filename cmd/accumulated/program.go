@@ -23,9 +23,11 @@ import (
 	"github.com/AccumulateNetwork/accumulate/internal/node"
 	"github.com/AccumulateNetwork/accumulate/internal/relay"
 	"github.com/AccumulateNetwork/accumulate/networks"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 	"github.com/getsentry/sentry-go"
 	"github.com/kardianos/service"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 	"github.com/tendermint/tendermint/privval"
@@ -33,9 +35,33 @@ import (
 	"github.com/tendermint/tendermint/rpc/client/local"
 )
 
+// openDB opens the state database with the storage backend selected in the
+// configuration.
+func openDB(cfg *config.Config, debug bool) (*state.StateDB, error) {
+	s := cfg.Accumulate.Storage
+	path := s.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(cfg.RootDir, path)
+	}
+
+	db := new(state.StateDB)
+	err := db.Open(s.Type, path, storage.Options{
+		CacheSize:        s.CacheSize,
+		SyncWrites:       s.SyncWrites,
+		ValueLogFileSize: s.ValueLogFileSize,
+	}, debug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s database %s: %v", s.Type, path, err)
+	}
+	db.SetBPTCacheSize(s.BPTCacheSize)
+	return db, nil
+}
+
 type Program struct {
 	cmd   *cobra.Command
 	db    *state.StateDB
+	exec  *chain.Executor
+	app   *abci.Accumulator
 	node  *node.Node
 	relay *relay.Relay
 	api   *http.Server
@@ -94,12 +120,26 @@ func (p *Program) Start(s service.Service) error {
 		defer sentry.Flush(2 * time.Second)
 	}
 
-	dbPath := filepath.Join(cfg.RootDir, "valacc.db")
 	//ToDo: FIX:::  bvcId := sha256.Sum256([]byte(config.Instrumentation.Namespace))
-	p.db = new(state.StateDB)
-	err = p.db.Open(dbPath, false, true)
+	p.db, err = openDB(cfg, true)
 	if err != nil {
-		return fmt.Errorf("failed to open database %s: %v", dbPath, err)
+		return err
+	}
+	p.db.SetPendingRetention(cfg.Accumulate.PendingRetention)
+	p.db.SetUndoRetention(cfg.Accumulate.UndoRetention)
+	p.db.SetHistoryRetention(cfg.Accumulate.HistoryRetention)
+	if cfg.Accumulate.Storage.VerifyChainsOnStart {
+		var problems []error
+		p.db.VerifyChains(func(problem error) { problems = append(problems, problem) })
+		if len(problems) > 0 {
+			return fmt.Errorf("%d Merkle chains are inconsistent, including %v", len(problems), problems[0])
+		}
+	}
+	if cfg.Instrumentation.Prometheus {
+		err = p.db.RegisterMetrics(prometheus.DefaultRegisterer, "accumulate")
+		if err != nil {
+			return fmt.Errorf("failed to register database metrics: %v", err)
+		}
 	}
 
 	// read private validator
@@ -130,6 +170,18 @@ func (p *Program) Start(s service.Service) error {
 		return fmt.Errorf("failed to initialize chain executor: %v", err)
 	}
 
+	if anchoring := cfg.Accumulate.Anchoring; anchoring.Publisher != "" {
+		target := anchoring.Target
+		if anchoring.Publisher == "file" && !filepath.IsAbs(target) {
+			target = filepath.Join(cfg.RootDir, target)
+		}
+		publisher, err := chain.NewAnchorPublisher(anchoring.Publisher, target)
+		if err != nil {
+			return fmt.Errorf("failed to initialize anchor publisher: %v", err)
+		}
+		exec.EnableAnchoring(publisher, anchoring.Account, anchoring.EveryBlock)
+	}
+
 	var logWriter io.Writer
 	if service.Interactive() {
 		logWriter, err = logging.NewConsoleWriter(cfg.LogFormat)
@@ -147,11 +199,15 @@ func (p *Program) Start(s service.Service) error {
 		os.Exit(1)
 	}
 	p.db.SetLogger(logger)
+	exec.SetLogger(logger)
+	p.exec = exec
 
 	app, err := abci.NewAccumulator(p.db, pv.Key.PubKey.Address(), exec, logger)
 	if err != nil {
 		return fmt.Errorf("failed to initialize ACBI app: %v", err)
 	}
+	app.EnableSnapshots(filepath.Join(cfg.RootDir, "snapshots"), cfg.Accumulate.SnapshotInterval, cfg.Accumulate.SnapshotKeep)
+	p.app = app
 
 	// Create node
 	p.node, err = node.New(cfg, app, logger)
@@ -247,6 +303,8 @@ func (p *Program) Stop(service.Service) error {
 
 	var errs []error
 	errs = append(errs, p.node.Stop())
+	p.exec.StopAnchoring()
+	p.app.WaitForSnapshots()
 	if p.node.Config.Accumulate.API.EnableSubscribeTX {
 		errs = append(errs, p.relay.Stop())
 	}
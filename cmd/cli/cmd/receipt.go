@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/spf13/cobra"
+)
+
+var receiptCmd = &cobra.Command{
+	Use:   "receipt",
+	Short: "Verify receipts",
+	Run: func(cmd *cobra.Command, args []string) {
+		var out string
+		var err error
+		if len(args) > 0 {
+			switch arg := args[0]; arg {
+			case "verify":
+				if len(args) > 2 {
+					out, err = VerifyReceipt(args[1], args[2])
+				} else {
+					fmt.Println("Usage:")
+					PrintReceiptVerify()
+				}
+			default:
+				fmt.Println("Usage:")
+				PrintReceipt()
+			}
+		} else {
+			fmt.Println("Usage:")
+			PrintReceipt()
+		}
+		printOutput(cmd, out, err)
+	},
+}
+
+func PrintReceiptVerify() {
+	fmt.Println("  accumulate receipt verify [file] [anchor]	Verify the receipt in the file leads to the trusted anchor, given in hex")
+}
+
+func PrintReceipt() {
+	PrintReceiptVerify()
+}
+
+// readReceipt reads a receipt from a file. The file may hold a receipt or the
+// result of query-receipt, in JSON or binary.
+func readReceipt(file string) (*protocol.Receipt, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt := new(protocol.Receipt)
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		err = receipt.UnmarshalBinary(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid receipt: %v", err)
+		}
+		return receipt, nil
+	}
+
+	// A query-receipt result holds the receipt in its receipt field
+	var result struct {
+		Receipt json.RawMessage `json:"receipt"`
+	}
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt: %v", err)
+	}
+	if result.Receipt != nil {
+		data = result.Receipt
+	}
+
+	err = json.Unmarshal(data, receipt)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+// VerifyReceipt checks, without contacting the network, that the receipt in
+// the file is valid and leads to the trusted anchor
+func VerifyReceipt(file, anchor string) (string, error) {
+	trusted, err := hex.DecodeString(anchor)
+	if err != nil {
+		return "", fmt.Errorf("invalid anchor: %v", err)
+	}
+	if len(trusted) != 32 {
+		return "", fmt.Errorf("invalid anchor: want 32 bytes, got %d", len(trusted))
+	}
+
+	receipt, err := readReceipt(file)
+	if err != nil {
+		return "", err
+	}
+
+	if !receipt.Validate() {
+		return "", fmt.Errorf("receipt is not valid: its nodes do not lead from %X to %X", receipt.Element, receipt.Anchor)
+	}
+	if !bytes.Equal(receipt.Anchor[:], trusted) {
+		return "", fmt.Errorf("receipt leads to %X, not the trusted anchor", receipt.Anchor)
+	}
+
+	if WantJsonOutput {
+		out, err := json.Marshal(map[string]interface{}{
+			"valid":   true,
+			"element": hex.EncodeToString(receipt.Element[:]),
+			"anchor":  hex.EncodeToString(receipt.Anchor[:]),
+		})
+		return string(out), err
+	}
+	return fmt.Sprintf("Receipt is valid: %X is proven by anchor %X", receipt.Element, receipt.Anchor), nil
+}
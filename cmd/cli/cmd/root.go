@@ -56,6 +56,7 @@ func InitRootCmd(database db.DB) *cobra.Command {
 	cmd.AddCommand(getCmd)
 	cmd.AddCommand(keyCmd)
 	cmd.AddCommand(pageCmd)
+	cmd.AddCommand(receiptCmd)
 	cmd.AddCommand(txCmd)
 	cmd.AddCommand(versionCmd)
 	//cmd.AddCommand(tokenCmd)
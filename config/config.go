@@ -31,6 +31,12 @@ func Default(net NetworkType, node NodeType) *Config {
 	c.Accumulate.API.PrometheusServer = "http://18.119.26.7:9090"
 	c.Accumulate.SentryDSN = "https://glet_78c3bf45d009794a4d9b0c990a1f1ed5@gitlab.com/api/v4/error_tracking/collector/29762666"
 	c.Accumulate.WebsiteEnabled = true
+	c.Accumulate.PendingRetention = 2 * 7 * 24 * 60 * 60 // About two weeks of one second blocks
+	c.Accumulate.UndoRetention = 100
+	c.Accumulate.HistoryRetention = 2 * 7 * 24 * 60 * 60 // About two weeks of one second blocks
+	c.Accumulate.SnapshotInterval = 1000
+	c.Accumulate.SnapshotKeep = 2
+	c.Accumulate.Storage = DefaultStorage()
 	switch node {
 	case Validator:
 		c.Config = *tm.DefaultValidatorConfig()
@@ -55,6 +61,88 @@ type Accumulate struct {
 	WebsiteEnabled       bool   `toml:"website-enabled" mapstructure:"website-enabled"`
 	WebsiteListenAddress string `toml:"website-listen-address" mapstructure:"website-listen-address"`
 	SentryDSN            string `toml:"sentry-dsn" mapstructure:"sentry-dsn"`
+
+	// PendingRetention is the number of blocks pending transactions are kept
+	// for before they are pruned. Zero keeps them forever.
+	PendingRetention int64 `toml:"pending-retention" mapstructure:"pending-retention"`
+
+	// UndoRetention is the number of blocks the state can be rolled back by.
+	// Zero disables rollback.
+	UndoRetention int64 `toml:"undo-retention" mapstructure:"undo-retention"`
+
+	// HistoryRetention is the number of blocks the past versions of accounts
+	// are kept for, so they can be queried by height. Zero keeps them forever.
+	HistoryRetention int64 `toml:"history-retention" mapstructure:"history-retention"`
+
+	// SnapshotInterval is the number of blocks between state sync snapshots.
+	// Zero disables snapshots. SnapshotKeep is the number of snapshots that
+	// are kept.
+	SnapshotInterval int64 `toml:"snapshot-interval" mapstructure:"snapshot-interval"`
+	SnapshotKeep     int   `toml:"snapshot-keep" mapstructure:"snapshot-keep"`
+
+	Storage   Storage   `toml:"storage" mapstructure:"storage"`
+	Anchoring Anchoring `toml:"anchoring" mapstructure:"anchoring"`
+}
+
+// Storage selects and tunes the database backend of the node's state.
+type Storage struct {
+	// Type is the storage backend: badger, bolt, or memory.
+	Type string `toml:"type" mapstructure:"type"`
+
+	// Path is the location of the database, relative to the node's root
+	// directory. Badger stores the database in a directory, Bolt in a file.
+	Path string `toml:"path" mapstructure:"path"`
+
+	// CacheSize is the number of bytes the backend may use to cache data.
+	// Zero uses the backend's default. Bolt uses it as the initial size of its
+	// memory map. Badger has no bounded cache and ignores it.
+	CacheSize int64 `toml:"cache-size" mapstructure:"cache-size"`
+
+	// SyncWrites syncs every write to disk. Disabling it is faster but the
+	// last blocks may be lost if the machine crashes.
+	SyncWrites bool `toml:"sync-writes" mapstructure:"sync-writes"`
+
+	// ValueLogFileSize is the size of Badger's value log files. Zero uses
+	// Badger's default.
+	ValueLogFileSize int64 `toml:"value-log-file-size" mapstructure:"value-log-file-size"`
+
+	// BPTCacheSize is the number of BPT byte blocks kept in memory. The least
+	// recently used are unloaded after each block. Zero keeps every byte
+	// block that has been loaded.
+	BPTCacheSize int `toml:"bpt-cache-size" mapstructure:"bpt-cache-size"`
+
+	// VerifyChainsOnStart replays every Merkle chain when the node starts and
+	// refuses to start if any chain is inconsistent. This reads the entire
+	// database, so it can take a long time.
+	VerifyChainsOnStart bool `toml:"verify-chains-on-start" mapstructure:"verify-chains-on-start"`
+}
+
+// Anchoring configures anchoring the root of the network into an external
+// ledger. The leader of a block publishes its root, and records the reference
+// the ledger returns in a data account.
+type Anchoring struct {
+	// Publisher is the type of ledger: file or http. Empty disables
+	// anchoring.
+	Publisher string `toml:"publisher" mapstructure:"publisher"`
+
+	// Target is the file the anchors are appended to, relative to the node's
+	// root directory, or the URL they are posted to.
+	Target string `toml:"target" mapstructure:"target"`
+
+	// Account is the URL of the data account the anchors are recorded in.
+	Account string `toml:"account" mapstructure:"account"`
+
+	// EveryBlock anchors every block instead of every major block.
+	EveryBlock bool `toml:"every-block" mapstructure:"every-block"`
+}
+
+func DefaultStorage() Storage {
+	return Storage{
+		Type:         "badger",
+		Path:         "valacc.db",
+		SyncWrites:   true,
+		BPTCacheSize: 1 << 16,
+	}
 }
 
 type RPC struct {
@@ -120,7 +208,10 @@ func loadTendermint(dir, file string) (*tm.Config, error) {
 }
 
 func loadAccumulate(dir, file string) (*Accumulate, error) {
+	// Configuration files written before the storage section was added use
+	// the default storage
 	config := new(Accumulate)
+	config.Storage = DefaultStorage()
 	err := load(dir, file, config)
 	if err != nil {
 		return nil, err
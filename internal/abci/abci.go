@@ -17,6 +17,7 @@ import (
 	"github.com/AccumulateNetwork/accumulate/protocol"
 	apiQuery "github.com/AccumulateNetwork/accumulate/types/api/query"
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 //go:generate go run github.com/golang/mock/mockgen -source abci.go -destination ../mock/abci/abci.go
@@ -53,4 +54,12 @@ type State interface {
 
 	// RootHash returns the root hash of the chain
 	RootHash() []byte
+
+	// SnapshotView takes a view of the committed state, from which a snapshot
+	// can be written for state sync while the state changes
+	SnapshotView() (*state.SnapshotView, error)
+
+	// RestoreSnapshot loads a serialized state, verifying it against the app
+	// hash
+	RestoreSnapshot(data, appHash []byte) error
 }
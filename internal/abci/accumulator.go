@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AccumulateNetwork/accumulate"
@@ -38,6 +40,12 @@ type Accumulator struct {
 	chain    Chain
 	logger   log.Logger
 	didPanic bool
+
+	snapshots        *snapshotStore
+	snapshotInterval int64
+	snapshotWG       sync.WaitGroup // snapshots that are being written
+	snapshotBusy     int32          // set while a snapshot is being written
+	restore          *snapshotRestore
 }
 
 // NewAccumulator returns a new Accumulator.
@@ -92,7 +100,8 @@ func (app *Accumulator) recover(code *uint32) {
 func (app *Accumulator) Info(req abci.RequestInfo) abci.ResponseInfo {
 	defer app.recover(nil)
 
-	//todo: load up the merkle databases to the same state we're at...  We will need to rewind.
+	// If Tendermint is behind the application, the state must be rewound with
+	// `accumulated rollback --height` before the node can start.
 
 	if app.chain == nil {
 		panic("Chain Validator Node not set!")
@@ -415,6 +424,8 @@ func (app *Accumulator) DeliverTx(req abci.RequestDeliverTx) (rdt abci.ResponseD
 func (app *Accumulator) EndBlock(req abci.RequestEndBlock) (resp abci.ResponseEndBlock) {
 	defer app.recover(nil)
 
+	app.chain.EndBlock(EndBlockRequest{})
+
 	// Select our leader who will initiate consensus on dbvc chain.
 	//resp.ConsensusParamUpdates
 	//for _, ev := range req.ByzantineValidators {
@@ -454,6 +465,8 @@ func (app *Accumulator) Commit() (resp abci.ResponseCommit) {
 		return
 	}
 
+	app.takeSnapshot()
+
 	//this will truncate what tendermint stores since we only care about current state
 	//todo: uncomment the next line when we have smt state syncing complete. For now, we are retaining everything for test net
 	// if app.RetainBlocks > 0 && app.Height >= app.RetainBlocks {
@@ -466,35 +479,167 @@ func (app *Accumulator) Commit() (resp abci.ResponseCommit) {
 	return resp
 }
 
+// EnableSnapshots enables taking a snapshot of the state every interval
+// blocks. Snapshots are stored in dir, and all but the latest keep snapshots
+// are removed.
+func (app *Accumulator) EnableSnapshots(dir string, interval int64, keep int) {
+	app.snapshots = &snapshotStore{dir: dir, keep: keep}
+	app.snapshotInterval = interval
+}
+
+// takeSnapshot takes a snapshot of the state if snapshots are enabled and the
+// height is a multiple of the snapshot interval. The snapshot is written in the
+// background from a view of the state, so the next block is not held up while
+// the state is serialized. A snapshot is skipped if the previous one is still
+// being written.
+func (app *Accumulator) takeSnapshot() {
+	if app.snapshots == nil || app.snapshotInterval <= 0 {
+		return
+	}
+
+	height, err := app.state.BlockIndex()
+	if err != nil || height <= 0 || height%app.snapshotInterval != 0 {
+		return
+	}
+
+	if !atomic.CompareAndSwapInt32(&app.snapshotBusy, 0, 1) {
+		app.logger.Info("Skipping snapshot, the previous snapshot is still being written", "height", height)
+		return
+	}
+
+	view, err := app.state.SnapshotView()
+	if err != nil {
+		atomic.StoreInt32(&app.snapshotBusy, 0)
+		sentry.CaptureException(err)
+		app.logger.Error("Failed to take snapshot", "height", height, "error", err)
+		return
+	}
+
+	app.snapshotWG.Add(1)
+	go func() {
+		defer app.snapshotWG.Done()
+		defer atomic.StoreInt32(&app.snapshotBusy, 0)
+		defer view.Close()
+
+		size, err := app.snapshots.save(uint64(height), view)
+		if err != nil {
+			sentry.CaptureException(err)
+			app.logger.Error("Failed to take snapshot", "height", height, "error", err)
+			return
+		}
+
+		app.logger.Info("Took snapshot", "height", height, "size", size)
+	}()
+}
+
+// WaitForSnapshots waits for the snapshot that is being written, if any.
+func (app *Accumulator) WaitForSnapshots() {
+	app.snapshotWG.Wait()
+}
+
 // ListSnapshots implements github.com/tendermint/tendermint/abci/types.Application.
 func (app *Accumulator) ListSnapshots(
 	req abci.RequestListSnapshots) abci.ResponseListSnapshots {
+	defer app.recover(nil)
+
+	if app.snapshots == nil {
+		return abci.ResponseListSnapshots{}
+	}
 
-	req.ProtoMessage()
-	return abci.ResponseListSnapshots{}
+	snapshots, err := app.snapshots.list()
+	if err != nil {
+		sentry.CaptureException(err)
+		app.logger.Error("Failed to list snapshots", "error", err)
+	}
+	return abci.ResponseListSnapshots{Snapshots: snapshots}
 }
 
 // OfferSnapshot implements github.com/tendermint/tendermint/abci/types.Application.
+//
+// A snapshot is only accepted if the node has no state.
 func (app *Accumulator) OfferSnapshot(
 	req abci.RequestOfferSnapshot) abci.ResponseOfferSnapshot {
-	return abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ABORT}
+	defer app.recover(nil)
+
+	_, err := app.state.BlockIndex()
+	if !errors.Is(err, storage.ErrNotFound) {
+		app.logger.Error("Refusing snapshot, the node already has state")
+		return abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ABORT}
+	}
+
+	if req.Snapshot.Format != snapshotFormat {
+		return abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_REJECT_FORMAT}
+	}
+
+	app.restore, err = newSnapshotRestore(req.Snapshot, req.AppHash)
+	if err != nil {
+		app.logger.Info("Rejecting snapshot", "height", req.Snapshot.Height, "error", err)
+		return abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_REJECT}
+	}
+
+	app.logger.Info("Accepted snapshot", "height", req.Snapshot.Height, "chunks", req.Snapshot.Chunks)
+	return abci.ResponseOfferSnapshot{Result: abci.ResponseOfferSnapshot_ACCEPT}
 }
 
 // LoadSnapshotChunk implements github.com/tendermint/tendermint/abci/types.Application.
 func (app *Accumulator) LoadSnapshotChunk(
 	req abci.RequestLoadSnapshotChunk) abci.ResponseLoadSnapshotChunk {
+	defer app.recover(nil)
+
+	if app.snapshots == nil {
+		return abci.ResponseLoadSnapshotChunk{}
+	}
 
-	//req.Height
-	//resp := abcitypes.ResponseLoadSnapshotChunk{}
-	//need to get a block of data between markers.
-	//resp.Chunk = app.mm.GetState(req.Height)
-	return abci.ResponseLoadSnapshotChunk{}
+	chunk, err := app.snapshots.loadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		app.logger.Error("Failed to load snapshot chunk", "height", req.Height, "chunk", req.Chunk, "error", err)
+		return abci.ResponseLoadSnapshotChunk{}
+	}
+	return abci.ResponseLoadSnapshotChunk{Chunk: chunk}
 }
 
 // ApplySnapshotChunk implements github.com/tendermint/tendermint/abci/types.Application.
+//
+// Each chunk is verified against the snapshot metadata as it is received. Once
+// all of the chunks have been received, the state is restored and verified
+// against the app hash.
 func (app *Accumulator) ApplySnapshotChunk(
 	req abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk {
-	return abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ABORT}
+	defer app.recover(nil)
+
+	if app.restore == nil {
+		return abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ABORT}
+	}
+
+	done, err := app.restore.add(req.Index, req.Chunk)
+	if err != nil {
+		app.logger.Info("Rejecting snapshot chunk", "chunk", req.Index, "sender", req.Sender, "error", err)
+		return abci.ResponseApplySnapshotChunk{
+			Result:        abci.ResponseApplySnapshotChunk_RETRY,
+			RefetchChunks: []uint32{req.Index},
+			RejectSenders: []string{req.Sender},
+		}
+	}
+	if !done {
+		return abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}
+	}
+
+	restore := app.restore
+	app.restore = nil
+	err = app.state.RestoreSnapshot(restore.data(), restore.appHash)
+	if err != nil {
+		sentry.CaptureException(err)
+		app.logger.Error("Failed to restore snapshot", "height", restore.snapshot.Height, "error", err)
+		return abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_REJECT_SNAPSHOT}
+	}
+
+	app.subnetID, err = app.state.SubnetID()
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		sentry.CaptureException(err)
+	}
+
+	app.logger.Info("Restored snapshot", "height", restore.snapshot.Height)
+	return abci.ResponseApplySnapshotChunk{Result: abci.ResponseApplySnapshotChunk_ACCEPT}
 }
 
 //updateValidator add, update, or remove a validator
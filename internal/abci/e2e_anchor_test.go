@@ -0,0 +1,158 @@
+package abci_test
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/internal/chain"
+	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto"
+)
+
+func TestExternalAnchor(t *testing.T) {
+	// The directory is created first so it is removed after the node stops
+	// anchoring
+	path := filepath.Join(t.TempDir(), "anchors.log")
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+
+	// The node signs the anchors it records, so its key must be on a key page
+	// of the anchor account
+	fooKey := n.key
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	// The key is also used for other transactions, so its nonce is greater
+	// than the height of any block and anchors must follow it
+	page := new(protocol.SigSpec)
+	n.GetChainAs("foo/sigspec0", page)
+	page.Keys[0].Nonce = 1000
+	dbTx = n.db.Begin()
+	require.NoError(t, acctesting.WriteStates(dbTx, page))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		for i, name := range []string{"foo/anchors", "foo/data"} {
+			cda := new(protocol.CreateDataAccount)
+			cda.Url = name
+			tx, err := transactions.New("foo", edSigner(fooKey, uint64(i+1001)), cda)
+			require.NoError(t, err)
+			send(tx)
+		}
+	})
+
+	n.exec.EnableAnchoring(chain.NewFileAnchorPublisher(path), "foo/anchors", true)
+
+	// Anchors are published after the block is committed and recorded in a
+	// later block
+	account := new(protocol.DataAccount)
+	for i := 0; i < 10 && len(account.Data) == 0; i++ {
+		n.Batch(func(send func(*transactions.GenTransaction)) {
+			wd := new(protocol.WriteData)
+			wd.Data = []byte{byte(i)}
+			tx, err := transactions.New("foo/data", edSigner(fooKey, uint64(i+1003)), wd)
+			require.NoError(t, err)
+			send(tx)
+		})
+		n.GetChainAs("foo/anchors", account)
+	}
+	require.NotEmpty(t, account.Data, "no anchor was recorded")
+
+	anchor := new(protocol.ExternalAnchor)
+	require.NoError(t, anchor.UnmarshalBinary(account.Data))
+	require.Equal(t, "file:"+path, anchor.Ledger)
+
+	// The reference is the offset of the anchor in the file
+	offset, err := strconv.ParseInt(string(anchor.Reference), 10, 64)
+	require.NoError(t, err)
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	_, err = f.Seek(offset, 0)
+	require.NoError(t, err)
+	line, err := bufio.NewReader(f).ReadBytes('\n')
+	require.NoError(t, err)
+
+	published := new(protocol.ExternalAnchor)
+	require.NoError(t, published.UnmarshalJSON(line))
+	require.Equal(t, anchor.Height, published.Height)
+	require.Equal(t, anchor.Root, published.Root)
+	require.Empty(t, published.Reference)
+}
+
+func TestSyntheticWriteDataRejected(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey, otherKey := generateKey(), generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		cda := new(protocol.CreateDataAccount)
+		cda.Url = "foo/anchors"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), cda)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	// A synthetic write signed by anyone cannot overwrite the account
+	body, err := (&protocol.SyntheticWriteData{Data: []byte("forged")}).MarshalBinary()
+	require.NoError(t, err)
+	tx := new(transactions.GenTransaction)
+	tx.SigInfo = &transactions.SignatureInfo{URL: "foo/anchors", Nonce: 1}
+	tx.Transaction = body
+	sig, err := edSigner(otherKey, 1)(tx.TransactionHash())
+	require.NoError(t, err)
+	tx.Signature = append(tx.Signature, sig)
+	require.NotZero(t, n.exec.CheckTx(tx).Code)
+
+	account := new(protocol.DataAccount)
+	n.GetChainAs("foo/anchors", account)
+	require.Empty(t, account.Data)
+}
+
+// blockingPublisher blocks every publish until its context is canceled
+type blockingPublisher struct {
+	started, stopped chan struct{}
+}
+
+func (p *blockingPublisher) Ledger() string { return "blocking" }
+
+func (p *blockingPublisher) Publish(ctx context.Context, anchor *protocol.ExternalAnchor) ([]byte, error) {
+	p.started <- struct{}{}
+	<-ctx.Done()
+	p.stopped <- struct{}{}
+	return nil, ctx.Err()
+}
+
+func TestStopAnchoring(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey := generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	publisher := &blockingPublisher{started: make(chan struct{}, 10), stopped: make(chan struct{}, 10)}
+	n.exec.EnableAnchoring(publisher, "foo/anchors", true)
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		cda := new(protocol.CreateDataAccount)
+		cda.Url = "foo/anchors"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), cda)
+		require.NoError(t, err)
+		send(tx)
+	})
+	<-publisher.started
+
+	// Stopping cancels the publish and waits for it to return
+	n.exec.StopAnchoring()
+	require.Len(t, publisher.stopped, len(publisher.started)+1)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
 	"github.com/stretchr/testify/require"
 	"github.com/tendermint/tendermint/crypto"
+	tmtypes "github.com/tendermint/tendermint/types"
 )
 
 func TestProofADI(t *testing.T) {
@@ -52,5 +53,43 @@ func TestProofADI(t *testing.T) {
 	require.Equal(t, types.ChainTypeIdentity, n.GetADI("RoadRunner").Type)
 	require.Equal(t, types.ChainTypeTokenAccount, n.GetTokenAccount("RoadRunner/Baz").Type)
 
-	// TODO Verify proofs
+	// Prove that the transaction that created the token account is part of the
+	// state of the last block
+	chainId := types.Bytes(n.ParseUrl("RoadRunner/Baz").ResourceChain()).AsBytes32()
+	txids, _, err := n.db.GetTxRange(&chainId, 0, 1)
+	require.NoError(t, err)
+	require.Len(t, txids, 1)
+	p, err := n.db.GetProof(chainId[:], txids[0][:])
+	require.NoError(t, err)
+	proof := protocol.NewStateProof(p)
+	require.Equal(t, txids[0][:], proof.Entry.Element[:])
+
+	header := new(tmtypes.Header)
+	header.Height = proof.Height + 1
+	header.AppHash = n.db.RootHash()
+	require.NoError(t, proof.Verify(header))
+
+	// The proof survives encoding
+	data, err := proof.MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(protocol.StateProof)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.NoError(t, decoded.Verify(header))
+
+	// The proof does not verify against a different block
+	header.Height++
+	require.Error(t, proof.Verify(header))
+	header.Height--
+	header.AppHash = make([]byte, 32)
+	require.Error(t, proof.Verify(header))
+	header.AppHash = n.db.RootHash()
+
+	// Or if any step is tampered with
+	proof.Entry.Element[0]++
+	require.Error(t, proof.Verify(header))
+	proof.Entry.Element[0]--
+	proof.AnchorHead[len(proof.AnchorHead)-1]++
+	require.Error(t, proof.Verify(header))
+	proof.AnchorHead[len(proof.AnchorHead)-1]--
+	require.NoError(t, proof.Verify(header))
 }
@@ -5,9 +5,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/AccumulateNetwork/accumulate/internal/abci"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/smt/storage/badger"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 	"github.com/stretchr/testify/require"
+	abcitypes "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
 )
 
@@ -42,3 +45,84 @@ func TestStateDBConsistency(t *testing.T) {
 	n = createApp(t, sdb, crypto.Address{}, "error", false)
 	n.testAnonTx(10)
 }
+
+func TestStateSync(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	n.app.(*abci.Accumulator).EnableSnapshots(t.TempDir(), 1, 1)
+	_, balances := n.testAnonTx(10)
+
+	// Snapshots are written in the background
+	n.app.(*abci.Accumulator).WaitForSnapshots()
+
+	// Only the latest snapshot is kept
+	list := n.app.ListSnapshots(abcitypes.RequestListSnapshots{})
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+	height, err := n.db.BlockIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(height), snapshot.Height)
+
+	// Restore the snapshot on a new node
+	n2 := createAppWithMemDB(t, crypto.Address{}, "error", false)
+	offer := n2.app.OfferSnapshot(abcitypes.RequestOfferSnapshot{Snapshot: snapshot, AppHash: n.db.RootHash()})
+	require.Equal(t, abcitypes.ResponseOfferSnapshot_ACCEPT, offer.Result)
+
+	for i := uint32(0); i < snapshot.Chunks; i++ {
+		chunk := n.app.LoadSnapshotChunk(abcitypes.RequestLoadSnapshotChunk{Height: snapshot.Height, Format: snapshot.Format, Chunk: i})
+		require.NotEmpty(t, chunk.Chunk)
+
+		// A corrupted chunk is refetched
+		bad := append([]byte{}, chunk.Chunk...)
+		bad[0]++
+		apply := n2.app.ApplySnapshotChunk(abcitypes.RequestApplySnapshotChunk{Index: i, Chunk: bad, Sender: "bad"})
+		require.Equal(t, abcitypes.ResponseApplySnapshotChunk_RETRY, apply.Result)
+		require.Equal(t, []string{"bad"}, apply.RejectSenders)
+
+		apply = n2.app.ApplySnapshotChunk(abcitypes.RequestApplySnapshotChunk{Index: i, Chunk: chunk.Chunk})
+		require.Equal(t, abcitypes.ResponseApplySnapshotChunk_ACCEPT, apply.Result)
+	}
+
+	info := n2.app.Info(abcitypes.RequestInfo{})
+	require.Equal(t, height, info.LastBlockHeight)
+	require.Equal(t, n.db.RootHash(), info.LastBlockAppHash)
+
+	for addr, bal := range balances {
+		require.Equal(t, bal, n2.GetAnonTokenAccount(addr).Balance.Int64())
+	}
+
+	// A node with state refuses snapshots
+	offer = n2.app.OfferSnapshot(abcitypes.RequestOfferSnapshot{Snapshot: snapshot, AppHash: n.db.RootHash()})
+	require.Equal(t, abcitypes.ResponseOfferSnapshot_ABORT, offer.Result)
+
+	// The new node can continue from the snapshot
+	n2.height = height
+	n2.testAnonTx(1)
+}
+
+func TestGenesisMajorBlockSchedule(t *testing.T) {
+	db := new(state.StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	// InitChain writes the app state of the genesis document to the database
+	// as is
+	schedule, err := (&state.MajorBlockSchedule{Blocks: 2}).MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, db.GetDB().Key(state.RecordStorageKey(state.MajorBlockScheduleKey())).Put(schedule))
+
+	n := createApp(t, db, crypto.Address{}, "error", true)
+	n.testAnonTx(1)
+
+	_, err = db.GetMajorAnchor(0)
+	require.NoError(t, err)
+
+	// The genesis transaction commits the schedule to the BPT, so it is part
+	// of a snapshot
+	data, err := db.Snapshot()
+	require.NoError(t, err)
+	dst := new(state.StateDB)
+	require.NoError(t, dst.Open("memory", "", storage.Options{}, false))
+	require.NoError(t, dst.RestoreSnapshot(data, db.RootHash()))
+	b, err := dst.ReadRecord(state.MajorBlockScheduleKey())
+	require.NoError(t, err)
+	require.Equal(t, schedule, b)
+}
@@ -3,10 +3,14 @@ package abci_test
 import (
 	"crypto/ed25519"
 	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	accapi "github.com/AccumulateNetwork/accumulate/internal/api"
+	apiv2 "github.com/AccumulateNetwork/accumulate/internal/api/v2"
 	"github.com/AccumulateNetwork/accumulate/internal/genesis"
 	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
 	"github.com/AccumulateNetwork/accumulate/internal/testing/e2e"
@@ -146,6 +150,49 @@ func TestFaucet(t *testing.T) {
 	require.Equal(t, int64(10*protocol.AcmePrecision), n.GetAnonTokenAccount(aliceUrl).Balance.Int64())
 }
 
+func TestQueryAtHeight(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	alice := generateKey()
+	aliceUrl := anon.GenerateAcmeAddress(alice.PubKey().Bytes())
+
+	faucet := func() int64 {
+		n.Batch(func(send func(*transactions.GenTransaction)) {
+			body := new(protocol.AcmeFaucet)
+			body.Url = aliceUrl
+			tx, err := transactions.New(genesis.FaucetUrl.String(), func(hash []byte) (*transactions.ED25519Sig, error) {
+				return genesis.FaucetWallet.Sign(hash), nil
+			}, body)
+			require.NoError(t, err)
+			send(tx)
+		})
+
+		height, err := n.db.BlockIndex()
+		require.NoError(t, err)
+		return height
+	}
+	first := faucet()
+	faucet()
+
+	q := apiv2.NewQueryDirect(n.client)
+	balance := func(res *apiv2.QueryResponse, err error) int64 {
+		require.NoError(t, err)
+		account := new(protocol.AnonTokenAccount)
+		require.NoError(t, json.Unmarshal(*res.Data.(*json.RawMessage), account))
+		return account.Balance.Int64()
+	}
+
+	require.Equal(t, int64(10*protocol.AcmePrecision), balance(q.QueryUrl(aliceUrl, first)))
+	require.Equal(t, int64(20*protocol.AcmePrecision), balance(q.QueryUrl(aliceUrl, 0)))
+
+	chainId := n.ParseUrl(aliceUrl).ResourceChain()
+	require.Equal(t, int64(10*protocol.AcmePrecision), balance(q.QueryChain(chainId, first)))
+	require.Equal(t, int64(20*protocol.AcmePrecision), balance(q.QueryChain(chainId, 0)))
+
+	// The account did not exist before the faucet funded it
+	_, err := q.QueryUrl(aliceUrl, first-1)
+	require.Error(t, err)
+}
+
 func TestAnchorChain(t *testing.T) {
 	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
 	anonAccount := generateKey()
@@ -243,6 +290,254 @@ func TestCreateADI(t *testing.T) {
 	require.Equal(t, keyHash[:], ks.Keys[0].PublicKey)
 }
 
+func TestCreateSubADI(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey, engKey := generateKey(), generateKey()
+	engKeyHash := sha256.Sum256(engKey.PubKey().Bytes())
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	// Sub-ADIs are created by the parent, with or without their own key book
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		adi := new(protocol.IdentityCreate)
+		adi.Url = "foo/eng"
+		adi.PublicKey = engKeyHash[:]
+		adi.KeyBookName = "book"
+		adi.KeyPageName = "page"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), adi)
+		require.NoError(t, err)
+		send(tx)
+
+		adi = new(protocol.IdentityCreate)
+		adi.Url = "foo/ops"
+		tx, err = transactions.New("foo", edSigner(fooKey, 2), adi)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	// The sub-ADI's own key book can create accounts in the sub-ADI
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		tac := new(protocol.TokenAccountCreate)
+		tac.Url = "foo/eng/tokens"
+		tac.TokenUrl = protocol.AcmeUrl().String()
+		tx, err := transactions.New("foo/eng", edSigner(engKey, 1), tac)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	require.Equal(t, n.ParseUrl("foo").Routing(), n.ParseUrl("foo/eng").Routing())
+
+	eng := n.GetADI("foo/eng")
+	require.Equal(t, types.String("acc://foo/eng"), eng.ChainUrl)
+	require.Equal(t, types.Bytes(n.ParseUrl("foo/eng/book").ResourceChain()).AsBytes32(), eng.SigSpecId)
+	require.Equal(t, engKeyHash[:], n.GetSigSpec("foo/eng/page").Keys[0].PublicKey)
+	require.Equal(t, eng.SigSpecId, n.GetTokenAccount("foo/eng/tokens").SigSpecId)
+
+	// A sub-ADI created without a key inherits the parent's key book
+	require.Equal(t, n.GetADI("foo").SigSpecId, n.GetADI("foo/ops").SigSpecId)
+
+	require.Equal(t, []string{
+		n.ParseUrl("foo/ssg0").String(),
+		n.ParseUrl("foo/sigspec0").String(),
+		n.ParseUrl("foo/eng").String(),
+		n.ParseUrl("foo/ops").String(),
+	}, n.GetDirectory("foo"))
+	require.Equal(t, []string{
+		n.ParseUrl("foo/eng/book").String(),
+		n.ParseUrl("foo/eng/page").String(),
+		n.ParseUrl("foo/eng/tokens").String(),
+	}, n.GetDirectory("foo/eng"))
+}
+
+func TestAccountMetadata(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey := generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		cda := new(protocol.CreateDataAccount)
+		cda.Url = "foo/meta"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), cda)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	doc := &protocol.AccountMetadata{
+		Name:     "Foo Corp",
+		LogoHash: make([]byte, 32),
+		Website:  "https://foo.example.com",
+	}
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		wd := new(protocol.WriteData)
+		var err error
+		wd.Data, err = json.Marshal(doc)
+		require.NoError(t, err)
+		tx, err := transactions.New("foo/meta", edSigner(fooKey, 1), wd)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	// Metadata can be attached to ADIs, accounts, and token issuers
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		adi := new(protocol.IdentityCreate)
+		adi.Url = "foo/eng"
+		adi.Metadata = "foo/meta"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), adi)
+		require.NoError(t, err)
+		send(tx)
+
+		tac := new(protocol.TokenAccountCreate)
+		tac.Url = "foo/tokens"
+		tac.TokenUrl = protocol.AcmeUrl().String()
+		tac.Metadata = "foo/meta"
+		tx, err = transactions.New("foo", edSigner(fooKey, 2), tac)
+		require.NoError(t, err)
+		send(tx)
+
+		ct := new(protocol.CreateToken)
+		ct.Url = "foo/coin"
+		ct.Symbol = "FOO"
+		ct.Precision = 8
+		ct.Properties = "foo/meta"
+		tx, err = transactions.New("foo", edSigner(fooKey, 3), ct)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	q := apiv2.NewQueryDirect(n.client)
+	for _, s := range []string{"foo/eng", "foo/tokens", "foo/coin"} {
+		res, err := q.QueryUrl(s, 0)
+		require.NoError(t, err)
+		require.NotNil(t, res.Metadata, s)
+		require.Equal(t, n.ParseUrl("foo/meta").String(), res.Metadata.Url)
+		require.Empty(t, res.Metadata.Error)
+		require.Equal(t, doc, res.Metadata.Document)
+	}
+
+	// Chains without metadata do not report any
+	res, err := q.QueryUrl("foo", 0)
+	require.NoError(t, err)
+	require.Nil(t, res.Metadata)
+}
+
+func TestWriteData(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey := generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		cda := new(protocol.CreateDataAccount)
+		cda.Url = "foo/data"
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), cda)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	// Each write appends an entry
+	entries := [][]byte{[]byte("foo"), []byte("bar")}
+	for i, data := range entries {
+		n.Batch(func(send func(*transactions.GenTransaction)) {
+			wd := new(protocol.WriteData)
+			wd.Data = data
+			tx, err := transactions.New("foo/data", edSigner(fooKey, uint64(i+1)), wd)
+			require.NoError(t, err)
+			send(tx)
+		})
+	}
+	require.Equal(t, entries, n.GetDataEntries("foo/data"))
+
+	// The account holds the latest entry
+	account := new(protocol.DataAccount)
+	n.GetChainAs("foo/data", account)
+	require.Equal(t, []byte("bar"), account.Data)
+}
+
+func TestSwap(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey, barKey := generateKey(), generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateADI(dbTx, barKey, "bar"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/coins", "foo/coin", 10, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/bucks", "bar/buck", 0, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "bar/bucks", "bar/buck", 20, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "bar/coins", "foo/coin", 0, false))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	// foo offers 4 coins for 15 bucks
+	offer := new(protocol.SwapOffer)
+	offer.Amount.SetInt64(4 * acctesting.TokenMx)
+	offer.RequestTokenUrl = "bar/buck"
+	offer.RequestAmount.SetInt64(15 * acctesting.TokenMx)
+	offer.Recipient = "foo/bucks"
+	offer.Counterparty = "bar/bucks"
+	offer.Expires = time.Now().Add(time.Hour)
+	offerTx, err := transactions.New("foo/coins", edSigner(fooKey, 1), offer)
+	require.NoError(t, err)
+	n.Batch(func(send func(*transactions.GenTransaction)) { send(offerTx) })
+
+	// The offered coins are held in escrow
+	require.Equal(t, int64(6*acctesting.TokenMx), n.GetTokenAccount("foo/coins").Balance.Int64())
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		accept := new(protocol.SwapAccept)
+		accept.Maker = "foo/coins"
+		accept.Offer = types.Bytes(offerTx.TransactionHash()).AsBytes32()
+		accept.Recipient = "bar/coins"
+		tx, err := transactions.New("bar/bucks", edSigner(barKey, 1), accept)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	require.Equal(t, int64(6*acctesting.TokenMx), n.GetTokenAccount("foo/coins").Balance.Int64())
+	require.Equal(t, int64(15*acctesting.TokenMx), n.GetTokenAccount("foo/bucks").Balance.Int64())
+	require.Equal(t, int64(5*acctesting.TokenMx), n.GetTokenAccount("bar/bucks").Balance.Int64())
+	require.Equal(t, int64(4*acctesting.TokenMx), n.GetTokenAccount("bar/coins").Balance.Int64())
+}
+
+func TestSwapExpiry(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey, barKey := generateKey(), generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateADI(dbTx, barKey, "bar"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/coins", "foo/coin", 10, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/bucks", "bar/buck", 0, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "bar/bucks", "bar/buck", 20, false))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	offer := new(protocol.SwapOffer)
+	offer.Amount.SetInt64(4 * acctesting.TokenMx)
+	offer.RequestTokenUrl = "bar/buck"
+	offer.RequestAmount.SetInt64(15 * acctesting.TokenMx)
+	offer.Recipient = "foo/bucks"
+	offer.Expires = time.Now().Add(2 * time.Second)
+	offerTx, err := transactions.New("foo/coins", edSigner(fooKey, 1), offer)
+	require.NoError(t, err)
+	n.Batch(func(send func(*transactions.GenTransaction)) { send(offerTx) })
+	require.Equal(t, int64(6*acctesting.TokenMx), n.GetTokenAccount("foo/coins").Balance.Int64())
+
+	// The escrow is released by the first block after the offer expires
+	time.Sleep(time.Until(offer.Expires))
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		tokenTx := api.NewTokenTx("bar/bucks")
+		tokenTx.AddToAccount("foo/bucks", 1)
+		tx, err := transactions.New("bar/bucks", edSigner(barKey, 1), tokenTx)
+		require.NoError(t, err)
+		send(tx)
+	})
+	require.Equal(t, int64(10*acctesting.TokenMx), n.GetTokenAccount("foo/coins").Balance.Int64())
+
+	swap := new(protocol.PendingSwap)
+	n.GetChainAs(fmt.Sprintf("foo/coins/swap/%x", offerTx.TransactionHash()), swap)
+	require.True(t, swap.Closed)
+}
+
 func TestCreateAdiTokenAccount(t *testing.T) {
 	t.Run("Default Key Book", func(t *testing.T) {
 		n := createAppWithMemDB(t, crypto.Address{}, "error", true)
@@ -361,6 +656,81 @@ func TestAdiAccountTx(t *testing.T) {
 	require.Equal(t, int64(68), n.GetTokenAccount("bar/tokens").Balance.Int64())
 }
 
+func TestTransferHistory(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey, barKey := generateKey(), generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/tokens", protocol.AcmeUrl().String(), 1, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/savings", protocol.AcmeUrl().String(), 0, false))
+	require.NoError(t, acctesting.CreateADI(dbTx, barKey, "bar"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "bar/tokens", protocol.AcmeUrl().String(), 0, false))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*transactions.GenTransaction)) {
+		tokenTx := api.NewTokenTx("foo/tokens")
+		tokenTx.AddToAccount("bar/tokens", 68)
+		tokenTx.AddToAccount("foo/savings", 10)
+
+		tx, err := transactions.New("foo/tokens", edSigner(fooKey, 1), tokenTx)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	n.client.Wait()
+
+	q := apiv2.NewQueryDirect(n.client)
+	history := func(url string, start, count int64) (uint64, []*protocol.TransferRecord) {
+		res, err := q.QueryTransferHistory(url, start, count)
+		require.NoError(t, err)
+		var records []*protocol.TransferRecord
+		for _, item := range res.Items {
+			require.Equal(t, "transfer", item.Type)
+			records = append(records, item.Data.(*protocol.TransferRecord))
+		}
+		return res.Total, records
+	}
+
+	type transfer struct {
+		Account, Counterparty string
+		Amount                int64
+		Incoming              bool
+	}
+	summarize := func(records []*protocol.TransferRecord) []transfer {
+		var s []transfer
+		for _, r := range records {
+			require.Equal(t, protocol.AcmeUrl().String(), r.Token)
+			s = append(s, transfer{r.Account, r.Counterparty, r.Amount.Int64(), r.Incoming})
+		}
+		return s
+	}
+
+	sent := []transfer{
+		{"acc://foo/tokens", "acc://bar/tokens", 68, false},
+		{"acc://foo/tokens", "acc://foo/savings", 10, false},
+	}
+	_, records := history("foo/tokens", 0, 10)
+	require.Equal(t, sent, summarize(records))
+
+	// The deposit is recorded as incoming
+	_, records = history("bar/tokens", 0, 10)
+	require.Equal(t, []transfer{{"acc://bar/tokens", "acc://foo/tokens", 68, true}}, summarize(records))
+
+	// The ADI's history includes the transfers of all of its accounts
+	total, records := history("foo", 0, 10)
+	require.Equal(t, uint64(3), total)
+	require.Equal(t, append(sent, transfer{"acc://foo/savings", "acc://foo/tokens", 10, true}), summarize(records))
+
+	total, records = history("foo", 1, 1)
+	require.Equal(t, uint64(3), total)
+	require.Equal(t, sent[1:], summarize(records))
+
+	// Oversized pages are capped rather than rejected
+	total, records = history("foo", 1, math.MaxInt64)
+	require.Equal(t, uint64(3), total)
+	require.Len(t, records, 2)
+}
+
 func TestSendCreditsFromAdiAccountToMultiSig(t *testing.T) {
 	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
 	fooKey := generateKey()
@@ -569,6 +939,86 @@ func TestRemoveKey(t *testing.T) {
 	require.Equal(t, testKey2.PubKey().Bytes(), spec.Keys[0].PublicKey)
 }
 
+func TestKeyIndex(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	liteKey, adiKey, pageKey, newKey := generateKey(), generateKey(), generateKey(), generateKey()
+
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateAnonTokenAccount(dbTx, liteKey, 5e4))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	n.Batch(func(send func(*Tx)) {
+		adi := new(protocol.IdentityCreate)
+		adi.Url = "RoadRunner"
+		adi.PublicKey = adiKey.PubKey().Bytes()
+		adi.KeyBookName = "book"
+		adi.KeyPageName = "page0"
+
+		tx, err := transactions.New(anon.GenerateAcmeAddress(liteKey.PubKey().Bytes()), edSigner(liteKey, 1), adi)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	n.Batch(func(send func(*Tx)) {
+		cms := new(protocol.CreateSigSpec)
+		cms.Url = "RoadRunner/page1"
+		cms.Keys = append(cms.Keys, &protocol.KeySpecParams{
+			PublicKey: pageKey.PubKey().Bytes(),
+		})
+
+		tx, err := transactions.New("RoadRunner/book", edSigner(adiKey, 1), cms)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	q := apiv2.NewQueryDirect(n.client)
+	keyIndex := func(key crypto.PubKey) *protocol.KeyIndexQueryResult {
+		res, err := q.QueryKeyIndex(key.Bytes())
+		require.NoError(t, err)
+		require.Equal(t, "key-index", res.Type)
+		return res.Data.(*protocol.KeyIndexQueryResult)
+	}
+
+	ki := keyIndex(adiKey.PubKey())
+	require.Equal(t, []*protocol.KeyIndexEntry{{KeyPage: "acc://RoadRunner/page0", KeyBook: "acc://RoadRunner/book", Index: 0}}, ki.Entries)
+	require.Empty(t, ki.LiteAccount)
+
+	ki = keyIndex(pageKey.PubKey())
+	require.Equal(t, []*protocol.KeyIndexEntry{{KeyPage: "acc://RoadRunner/page1", KeyBook: "acc://RoadRunner/book", Index: 1}}, ki.Entries)
+
+	ki = keyIndex(liteKey.PubKey())
+	require.Empty(t, ki.Entries)
+	require.Equal(t, anon.GenerateAcmeAddress(liteKey.PubKey().Bytes()), ki.LiteAccount)
+
+	// Replacing a key moves the page to the index of the new key
+	n.Batch(func(send func(*Tx)) {
+		body := new(protocol.UpdateKeyPage)
+		body.Operation = protocol.UpdateKey
+		body.Key = pageKey.PubKey().Bytes()
+		body.NewKey = newKey.PubKey().Bytes()
+
+		info := &transactions.SignatureInfo{URL: "RoadRunner/page1", PriorityIdx: 1}
+		tx, err := transactions.NewWith(info, edSigner(pageKey, 1), body)
+		require.NoError(t, err)
+		send(tx)
+	})
+
+	ki = keyIndex(newKey.PubKey())
+	require.Equal(t, []*protocol.KeyIndexEntry{{KeyPage: "acc://RoadRunner/page1", KeyBook: "acc://RoadRunner/book", Index: 1}}, ki.Entries)
+
+	_, err := q.QueryKeyIndex(pageKey.PubKey().Bytes())
+	require.Error(t, err)
+
+	// Entries for pages that do not exist on this BVC are skipped
+	index := &protocol.KeyPageIndex{KeyPages: []string{"acc://RoadRunner/missing", "acc://RoadRunner/page1"}}
+	b, err := index.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, n.db.GetDB().Key(state.IndexKey(state.KeyIndex, newKey.PubKey().Bytes(), "Pages")).Put(b))
+
+	ki = keyIndex(newKey.PubKey())
+	require.Equal(t, []*protocol.KeyIndexEntry{{KeyPage: "acc://RoadRunner/page1", KeyBook: "acc://RoadRunner/book", Index: 1}}, ki.Entries)
+}
+
 func TestSignatorHeight(t *testing.T) {
 	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
 	liteKey, fooKey := generateKey(), generateKey()
@@ -619,3 +1069,61 @@ func TestSignatorHeight(t *testing.T) {
 
 	require.Equal(t, keyPageHeight, getHeight(keyPageUrl), "Key page height changed")
 }
+
+func TestReceipt(t *testing.T) {
+	n := createAppWithMemDB(t, crypto.Address{}, "error", true)
+	fooKey := generateKey()
+	dbTx := n.db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbTx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/tokens", protocol.AcmeUrl().String(), 1, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbTx, "foo/savings", protocol.AcmeUrl().String(), 0, false))
+	dbTx.Commit(n.NextHeight(), time.Unix(0, 0))
+
+	var txids [][]byte
+	for i := 0; i < 3; i++ {
+		n.Batch(func(send func(*transactions.GenTransaction)) {
+			tokenTx := api.NewTokenTx("foo/tokens")
+			tokenTx.AddToAccount("foo/savings", uint64(10+i))
+
+			tx, err := transactions.New("foo/tokens", edSigner(fooKey, 1), tokenTx)
+			require.NoError(t, err)
+			send(tx)
+			txids = append(txids, tx.TransactionHash())
+		})
+	}
+
+	n.client.Wait()
+
+	q := apiv2.NewQueryDirect(n.client)
+	receipt := func(hash, anchor []byte) *protocol.ReceiptQueryResult {
+		res, err := q.QueryReceipt("foo/tokens", hash, anchor)
+		require.NoError(t, err)
+		require.Equal(t, "receipt", res.Type)
+		r := res.Data.(*protocol.ReceiptQueryResult)
+		require.Equal(t, hash, r.Receipt.Element[:])
+		require.True(t, r.Receipt.Validate())
+		return r
+	}
+
+	// Every receipt to the head of the chain leads to the same root
+	head := receipt(txids[2], nil)
+	require.Equal(t, head.ElementIndex, head.AnchorIndex)
+	for _, txid := range txids[:2] {
+		r := receipt(txid, nil)
+		require.Less(t, r.ElementIndex, r.AnchorIndex)
+		require.Equal(t, head.AnchorIndex, r.AnchorIndex)
+		require.Equal(t, head.Receipt.Anchor, r.Receipt.Anchor)
+	}
+
+	// A receipt to an earlier anchor leads to the root as of that anchor
+	r := receipt(txids[0], txids[1])
+	require.Less(t, r.AnchorIndex, head.AnchorIndex)
+	require.NotEqual(t, head.Receipt.Anchor, r.Receipt.Anchor)
+
+	_, err := q.QueryReceipt("foo/tokens", txids[1], txids[0])
+	require.Error(t, err, "the anchor precedes the element")
+
+	missing := sha256.Sum256([]byte("not an element"))
+	_, err = q.QueryReceipt("foo/tokens", missing[:], nil)
+	require.Error(t, err, "the hash is not an element of the chain")
+}
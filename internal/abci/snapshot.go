@@ -0,0 +1,314 @@
+package abci
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+)
+
+const (
+	// snapshotFormat is the version of the snapshot format. Snapshots are the
+	// serialized state of types/state.StateDB.Snapshot, split into chunks.
+	snapshotFormat uint32 = 1
+
+	// snapshotChunkSize is the maximum size of a snapshot chunk.
+	snapshotChunkSize = 4 << 20
+
+	snapshotMetadataFile = "snapshot.json"
+)
+
+// snapshotStore keeps snapshots on disk, in a directory per height.
+type snapshotStore struct {
+	dir  string
+	keep int
+}
+
+func (s *snapshotStore) path(height uint64, elem ...string) string {
+	return filepath.Join(append([]string{s.dir, strconv.FormatUint(height, 10)}, elem...)...)
+}
+
+// save writes a snapshot to disk, split into chunks as it is written, then
+// removes the oldest snapshots if there are more than the store keeps. save
+// returns the size of the snapshot.
+//
+// The metadata of a snapshot is the list of the hashes of its chunks, so that
+// each chunk can be verified as it is received, and the hash of the snapshot
+// is the hash of its metadata.
+func (s *snapshotStore) save(height uint64, snapshot io.WriterTo) (int64, error) {
+	tmp := s.path(height) + ".tmp"
+	err := os.RemoveAll(tmp)
+	if err != nil {
+		return 0, err
+	}
+	err = os.MkdirAll(tmp, 0700)
+	if err != nil {
+		return 0, err
+	}
+
+	w := &chunkWriter{dir: tmp}
+	size, err := snapshot.WriteTo(w)
+	if err == nil {
+		err = w.finish()
+	}
+	if err != nil {
+		w.abort()
+		return 0, err
+	}
+
+	hash := sha256.Sum256(w.metadata)
+	b, err := json.Marshal(&abci.Snapshot{
+		Height:   height,
+		Format:   snapshotFormat,
+		Chunks:   w.chunks,
+		Hash:     hash[:],
+		Metadata: w.metadata,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	err = ioutil.WriteFile(filepath.Join(tmp, snapshotMetadataFile), b, 0600)
+	if err != nil {
+		return 0, err
+	}
+
+	// Rename the directory last, so a snapshot is never listed until it is
+	// complete
+	err = os.RemoveAll(s.path(height))
+	if err != nil {
+		return 0, err
+	}
+	err = os.Rename(tmp, s.path(height))
+	if err != nil {
+		return 0, err
+	}
+
+	return size, s.prune()
+}
+
+// chunkWriter writes a snapshot to a file per chunk of snapshotChunkSize
+// bytes, and records the hash of each chunk.
+type chunkWriter struct {
+	dir      string
+	file     *os.File
+	buf      *bufio.Writer
+	hash     hash.Hash
+	size     int
+	chunks   uint32
+	metadata []byte
+}
+
+func (w *chunkWriter) Write(b []byte) (int, error) {
+	var n int
+	for len(b) > 0 {
+		if w.file == nil {
+			err := w.open()
+			if err != nil {
+				return n, err
+			}
+		}
+
+		m := snapshotChunkSize - w.size
+		if m > len(b) {
+			m = len(b)
+		}
+		_, err := w.buf.Write(b[:m])
+		if err != nil {
+			return n, err
+		}
+		w.hash.Write(b[:m])
+		w.size += m
+		n += m
+		b = b[m:]
+
+		if w.size == snapshotChunkSize {
+			err = w.close()
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	return n, nil
+}
+
+func (w *chunkWriter) open() error {
+	f, err := os.Create(filepath.Join(w.dir, strconv.FormatUint(uint64(w.chunks), 10)))
+	if err != nil {
+		return err
+	}
+	w.file = f
+	w.buf = bufio.NewWriter(f)
+	w.hash = sha256.New()
+	w.size = 0
+	return nil
+}
+
+func (w *chunkWriter) close() error {
+	err := w.buf.Flush()
+	if err == nil {
+		err = w.file.Close()
+	} else {
+		w.file.Close()
+	}
+	w.file = nil
+	if err != nil {
+		return err
+	}
+
+	w.metadata = append(w.metadata, w.hash.Sum(nil)...)
+	w.chunks++
+	return nil
+}
+
+// finish writes the last chunk. A snapshot always has at least one chunk,
+// even if it is empty.
+func (w *chunkWriter) finish() error {
+	if w.file == nil && w.chunks > 0 {
+		return nil
+	}
+	if w.file == nil {
+		err := w.open()
+		if err != nil {
+			return err
+		}
+	}
+	return w.close()
+}
+
+// abort closes the chunk that is being written and removes the snapshot.
+func (w *chunkWriter) abort() {
+	if w.file != nil {
+		w.file.Close()
+	}
+	os.RemoveAll(w.dir)
+}
+
+// prune removes all but the latest snapshots.
+func (s *snapshotStore) prune() error {
+	if s.keep <= 0 {
+		return nil
+	}
+
+	snapshots, err := s.list()
+	if err != nil {
+		return err
+	}
+
+	for len(snapshots) > s.keep {
+		err = os.RemoveAll(s.path(snapshots[0].Height))
+		if err != nil {
+			return err
+		}
+		snapshots = snapshots[1:]
+	}
+	return nil
+}
+
+// list returns the snapshots in the store, oldest first.
+func (s *snapshotStore) list() ([]*abci.Snapshot, error) {
+	entries, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var snapshots []*abci.Snapshot
+	for _, entry := range entries {
+		height, err := strconv.ParseUint(entry.Name(), 10, 64)
+		if !entry.IsDir() || err != nil {
+			continue
+		}
+
+		b, err := ioutil.ReadFile(s.path(height, snapshotMetadataFile))
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot := new(abci.Snapshot)
+		err = json.Unmarshal(b, snapshot)
+		if err != nil {
+			return nil, fmt.Errorf("invalid snapshot %d: %v", height, err)
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Height < snapshots[j].Height
+	})
+	return snapshots, nil
+}
+
+// loadChunk reads a chunk of a snapshot.
+func (s *snapshotStore) loadChunk(height uint64, format, chunk uint32) ([]byte, error) {
+	if format != snapshotFormat {
+		return nil, fmt.Errorf("unknown snapshot format %d", format)
+	}
+	return ioutil.ReadFile(s.path(height, strconv.FormatUint(uint64(chunk), 10)))
+}
+
+// snapshotRestore tracks the chunks of a snapshot that is being restored.
+type snapshotRestore struct {
+	snapshot *abci.Snapshot
+	appHash  []byte
+	chunks   [][]byte
+	received uint32
+}
+
+func newSnapshotRestore(snapshot *abci.Snapshot, appHash []byte) (*snapshotRestore, error) {
+	if snapshot.Format != snapshotFormat {
+		return nil, fmt.Errorf("unknown snapshot format %d", snapshot.Format)
+	}
+	if snapshot.Chunks == 0 || len(snapshot.Metadata) != 32*int(snapshot.Chunks) {
+		return nil, fmt.Errorf("snapshot metadata does not list the hash of each chunk")
+	}
+	hash := sha256.Sum256(snapshot.Metadata)
+	if !bytes.Equal(hash[:], snapshot.Hash) {
+		return nil, fmt.Errorf("snapshot hash does not match its metadata")
+	}
+
+	r := new(snapshotRestore)
+	r.snapshot = snapshot
+	r.appHash = appHash
+	r.chunks = make([][]byte, snapshot.Chunks)
+	return r, nil
+}
+
+// add verifies a chunk against the snapshot metadata and records it. add
+// returns true once every chunk has been received.
+func (r *snapshotRestore) add(index uint32, chunk []byte) (bool, error) {
+	if index >= r.snapshot.Chunks {
+		return false, fmt.Errorf("snapshot has %d chunks, got chunk %d", r.snapshot.Chunks, index)
+	}
+
+	hash := sha256.Sum256(chunk)
+	if !bytes.Equal(hash[:], r.snapshot.Metadata[32*index:32*(index+1)]) {
+		return false, fmt.Errorf("chunk %d does not match the snapshot metadata", index)
+	}
+
+	if r.chunks[index] == nil {
+		r.chunks[index] = chunk
+		r.received++
+	}
+	return r.received == r.snapshot.Chunks, nil
+}
+
+// data returns the reassembled snapshot.
+func (r *snapshotRestore) data() []byte {
+	var data []byte
+	for _, chunk := range r.chunks {
+		data = append(data, chunk...)
+	}
+	return data
+}
@@ -36,7 +36,7 @@ import (
 
 func createAppWithMemDB(t testing.TB, addr crypto.Address, logLevel string, doGenesis bool) *fakeNode {
 	db := new(state.StateDB)
-	err := db.Open("memory", true, true)
+	err := db.Open("memory", "", storage.Options{}, true)
 	require.NoError(t, err)
 
 	return createApp(t, db, addr, logLevel, doGenesis)
@@ -93,6 +93,10 @@ func createApp(t testing.TB, db *state.StateDB, addr crypto.Address, logLevel st
 
 	mgr, err := chain.NewBlockValidatorExecutor(n.query, db, bvcKey)
 	require.NoError(t, err)
+	n.exec = mgr
+	mgr.SetLogger(logger)
+	t.Cleanup(mgr.StopAnchoring)
+	n.key = tmed25519.PrivKey(bvcKey)
 
 	n.app, err = abci.NewAccumulator(db, addr, mgr, logger)
 	require.NoError(t, err)
@@ -117,6 +121,8 @@ type fakeNode struct {
 	app    abcitypes.Application
 	client *acctesting.ABCIApplicationClient
 	query  *accapi.Query
+	exec   *chain.Executor
+	key    tmed25519.PrivKey
 	height int64
 }
 
@@ -179,7 +185,7 @@ func generateKey() tmed25519.PrivKey {
 func edSigner(key tmed25519.PrivKey, nonce uint64) func(hash []byte) (*transactions.ED25519Sig, error) {
 	return func(hash []byte) (*transactions.ED25519Sig, error) {
 		sig := new(transactions.ED25519Sig)
-		return sig, sig.Sign(1, key, hash)
+		return sig, sig.Sign(nonce, key, hash)
 	}
 }
 
@@ -191,10 +197,9 @@ func (n *fakeNode) ParseUrl(s string) *url.URL {
 
 func (n *fakeNode) GetDirectory(adi string) []string {
 	u := n.ParseUrl(adi)
-	require.True(n.t, u.Identity().Equal(u))
 
 	md := new(protocol.DirectoryIndexMetadata)
-	idc := u.IdentityChain()
+	idc := u.ResourceChain()
 	b, err := n.db.GetIndex(state.DirectoryIndex, idc, "Metadata")
 	if errors.Is(err, storage.ErrNotFound) {
 		return nil
@@ -211,6 +216,26 @@ func (n *fakeNode) GetDirectory(adi string) []string {
 	return chains
 }
 
+func (n *fakeNode) GetDataEntries(account string) [][]byte {
+	u := n.ParseUrl(account)
+
+	md := new(protocol.DataIndexMetadata)
+	chain := u.ResourceChain()
+	b, err := n.db.GetIndex(state.DataIndex, chain, "Metadata")
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	require.NoError(n.t, err)
+	require.NoError(n.t, md.UnmarshalBinary(b))
+
+	entries := make([][]byte, md.Count)
+	for i := range entries {
+		entries[i], err = n.db.GetIndex(state.DataIndex, chain, uint64(i))
+		require.NoError(n.t, err)
+	}
+	return entries
+}
+
 func (n *fakeNode) GetChainAs(url string, obj encoding.BinaryUnmarshaler) {
 	r, err := n.query.QueryByUrl(url)
 	require.NoError(n.t, err)
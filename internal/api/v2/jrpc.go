@@ -88,11 +88,14 @@ func NewJrpc(opts JrpcOptions) (*JrpcMethods, error) {
 		"faucet":  m.Faucet,
 
 		// Query
-		"query":            m.Query,
-		"query-directory":  m.QueryDirectory,
-		"query-chain":      m.QueryChain,
-		"query-tx":         m.QueryTx,
-		"query-tx-history": m.QueryTxHistory,
+		"query":                  m.Query,
+		"query-directory":        m.QueryDirectory,
+		"query-chain":            m.QueryChain,
+		"query-tx":               m.QueryTx,
+		"query-tx-history":       m.QueryTxHistory,
+		"query-key-index":        m.QueryKeyIndex,
+		"query-transfer-history": m.QueryTransferHistory,
+		"query-receipt":          m.QueryReceipt,
 
 		// Execute
 		"execute":              m.Execute,
@@ -104,6 +107,9 @@ func NewJrpc(opts JrpcOptions) (*JrpcMethods, error) {
 		"send-tokens":          m.ExecuteWith(func() PL { return new(api.TokenTx) }, "From", "To"),
 		"add-credits":          m.ExecuteWith(func() PL { return new(protocol.AddCredits) }),
 		"update-key-page":      m.ExecuteWith(func() PL { return new(protocol.UpdateKeyPage) }),
+		"swap-offer":           m.ExecuteWith(func() PL { return new(protocol.SwapOffer) }),
+		"swap-accept":          m.ExecuteWith(func() PL { return new(protocol.SwapAccept) }),
+		"swap-cancel":          m.ExecuteWith(func() PL { return new(protocol.SwapCancel) }),
 	}
 
 	return m, nil
@@ -13,7 +13,7 @@ func (m *JrpcMethods) Query(_ context.Context, params json.RawMessage) interface
 		return err
 	}
 
-	return jrpcFormatQuery(m.opts.Query.QueryUrl(req.Url))
+	return jrpcFormatQuery(m.opts.Query.QueryUrl(req.Url, int64(req.Height)))
 }
 
 func (m *JrpcMethods) QueryDirectory(_ context.Context, params json.RawMessage) interface{} {
@@ -33,7 +33,7 @@ func (m *JrpcMethods) QueryChain(_ context.Context, params json.RawMessage) inte
 		return err
 	}
 
-	return jrpcFormatQuery(m.opts.Query.QueryChain(req.ChainId))
+	return jrpcFormatQuery(m.opts.Query.QueryChain(req.ChainId, int64(req.Height)))
 }
 
 func (m *JrpcMethods) QueryTx(_ context.Context, params json.RawMessage) interface{} {
@@ -65,3 +65,50 @@ func (m *JrpcMethods) QueryTxHistory(_ context.Context, params json.RawMessage)
 
 	return res
 }
+
+func (m *JrpcMethods) QueryKeyIndex(_ context.Context, params json.RawMessage) interface{} {
+	req := new(KeyIndexQuery)
+	err := m.parse(params, req)
+	if err != nil {
+		return err
+	}
+
+	return jrpcFormatQuery(m.opts.Query.QueryKeyIndex(req.Key))
+}
+
+func (m *JrpcMethods) QueryTransferHistory(_ context.Context, params json.RawMessage) interface{} {
+	req := new(UrlQuery)
+	err := m.parse(params, req)
+	if err != nil {
+		return err
+	}
+
+	// If the user wants nothing, give them nothing
+	if req.Count == 0 {
+		return validatorError(errors.New("count must be greater than 0"))
+	}
+
+	res, err := m.opts.Query.QueryTransferHistory(req.Url, int64(req.Start), int64(req.Count))
+	if err != nil {
+		return accumulateError(err)
+	}
+
+	return res
+}
+
+func (m *JrpcMethods) QueryReceipt(_ context.Context, params json.RawMessage) interface{} {
+	req := new(ReceiptQuery)
+	err := m.parse(params, req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Hash) != 32 {
+		return validatorError(errors.New("hash must be 32 bytes"))
+	}
+	if req.Anchor != nil && len(req.Anchor) != 32 {
+		return validatorError(errors.New("anchor must be 32 bytes"))
+	}
+
+	return jrpcFormatQuery(m.opts.Query.QueryReceipt(req.Url, req.Hash, req.Anchor))
+}
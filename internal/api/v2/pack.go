@@ -3,15 +3,28 @@ package api
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 
 	"github.com/AccumulateNetwork/accumulate/protocol"
 	"github.com/AccumulateNetwork/accumulate/types/api"
+	"github.com/AccumulateNetwork/accumulate/types/api/query"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 	"github.com/AccumulateNetwork/accumulate/types/synthetic"
 )
 
-func packStateResponse(obj *state.Object, chain state.Chain) (*QueryResponse, error) {
-	b, err := json.Marshal(chain)
+func packStateResponse(obj *query.ResponseByChainId, chain state.Chain) (*QueryResponse, error) {
+	var data interface{} = chain
+	if acct, ok := chain.(*state.TokenAccount); ok && acct.Vesting != nil {
+		// Report the vested portion as of the block the state is from, which
+		// is the clock debits are checked against
+		data = &struct {
+			*state.TokenAccount
+			LockedBalance    *big.Int `json:"lockedBalance"`
+			SpendableBalance *big.Int `json:"spendableBalance"`
+		}{acct, acct.LockedBalance(obj.BlockTime), acct.SpendableBalance(obj.BlockTime)}
+	}
+
+	b, err := json.Marshal(data)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal state: %v", err)
 	}
@@ -71,7 +71,7 @@ func (q queryDirect) queryType(typ types.QueryType, content queryRequest) (strin
 	return string(res.Response.Key), res.Response.Value, nil
 }
 
-func (q queryDirect) QueryUrl(s string) (*QueryResponse, error) {
+func (q queryDirect) QueryUrl(s string, height int64) (*QueryResponse, error) {
 	u, err := url.Parse(s)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidUrl, err)
@@ -79,6 +79,7 @@ func (q queryDirect) QueryUrl(s string) (*QueryResponse, error) {
 
 	req := new(query.RequestByUrl)
 	req.Url = types.String(u.String())
+	req.Height = height
 	k, v, err := q.query(req)
 	if err != nil {
 		return nil, err
@@ -91,7 +92,16 @@ func (q queryDirect) QueryUrl(s string) (*QueryResponse, error) {
 			return nil, err
 		}
 
-		return packStateResponse(obj, chain)
+		res, err := packStateResponse(obj, chain)
+		if err != nil {
+			return nil, err
+		}
+
+		res.Metadata, err = q.queryMetadata(u)
+		if err != nil {
+			return nil, err
+		}
+		return res, nil
 
 	case "tx":
 		res := new(query.ResponseByTxId)
@@ -140,13 +150,14 @@ func (q queryDirect) QueryDirectory(s string) (*QueryResponse, error) {
 	return res, nil
 }
 
-func (q queryDirect) QueryChain(id []byte) (*QueryResponse, error) {
+func (q queryDirect) QueryChain(id []byte, height int64) (*QueryResponse, error) {
 	if len(id) != 32 {
 		return nil, fmt.Errorf("invalid chain ID: wanted 32 bytes, got %d", len(id))
 	}
 
 	req := new(query.RequestByChainId)
 	copy(req.ChainId[:], id)
+	req.Height = height
 	k, v, err := q.query(req)
 	if err != nil {
 		return nil, err
@@ -160,7 +171,56 @@ func (q queryDirect) QueryChain(id []byte) (*QueryResponse, error) {
 		return nil, err
 	}
 
-	return packStateResponse(obj, chain)
+	res, err := packStateResponse(obj, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := chain.Header().ParseUrl()
+	if err != nil {
+		return nil, fmt.Errorf("invalid chain URL: %v", err)
+	}
+
+	res.Metadata, err = q.queryMetadata(u)
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// queryMetadata resolves the metadata document of a chain. Returns nil if the
+// chain does not have metadata.
+func (q queryDirect) queryMetadata(u *url.URL) (*MetadataResponse, error) {
+	req := new(query.RequestByUrl)
+	req.Url = types.String(u.String())
+	k, v, err := q.queryType(types.QueryTypeMetadataUrl, req)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if k != "metadata" {
+		return nil, fmt.Errorf("unknown response type: want metadata, got %q", k)
+	}
+
+	md := new(protocol.MetadataQueryResult)
+	err = md.UnmarshalBinary(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+
+	res := new(MetadataResponse)
+	res.Url = md.Url
+	if len(md.Data) == 0 {
+		res.Error = "metadata account is empty"
+		return res, nil
+	}
+
+	res.Document, err = protocol.ParseAccountMetadata(md.Data)
+	if err != nil {
+		res.Error = err.Error()
+	}
+	return res, nil
 }
 
 func (q queryDirect) QueryTx(id []byte) (*QueryResponse, error) {
@@ -235,3 +295,101 @@ func (q queryDirect) QueryTxHistory(s string, start, count int64) (*QueryMultiRe
 
 	return res, nil
 }
+
+func (q queryDirect) QueryKeyIndex(key []byte) (*QueryResponse, error) {
+	req := new(query.RequestKeyIndex)
+	req.Key = key
+	k, v, err := q.query(req)
+	if err != nil {
+		return nil, err
+	}
+	if k != "key-index" {
+		return nil, fmt.Errorf("unknown response type: want key-index, got %q", k)
+	}
+
+	ki := new(protocol.KeyIndexQueryResult)
+	err = ki.UnmarshalBinary(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+
+	res := new(QueryResponse)
+	res.Type = "key-index"
+	res.Data = ki
+	return res, nil
+}
+
+// QueryTransferHistory returns the transfers of a token account or, for an
+// ADI, of every account of the ADI and its sub-ADIs.
+func (q queryDirect) QueryTransferHistory(s string, start, count int64) (*QueryMultiResponse, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUrl, err)
+	}
+
+	req := new(query.RequestTxHistory)
+	copy(req.ChainId[:], u.ResourceChain())
+	req.Start = start
+	req.Limit = count
+	k, v, err := q.queryType(types.QueryTypeTransferHistory, req)
+	if err != nil {
+		return nil, err
+	}
+	if k != "transfer-history" {
+		return nil, fmt.Errorf("unknown response type: want transfer-history, got %q", k)
+	}
+
+	history := new(protocol.TransferHistoryQueryResult)
+	err = history.UnmarshalBinary(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+
+	res := new(QueryMultiResponse)
+	res.Items = make([]*QueryResponse, len(history.Records))
+	res.Start = uint64(start)
+	res.Count = uint64(count)
+	res.Total = history.Total
+	for i, transfer := range history.Records {
+		res.Items[i] = new(QueryResponse)
+		res.Items[i].Type = "transfer"
+		res.Items[i].Data = transfer
+	}
+	return res, nil
+}
+
+// QueryReceipt returns a receipt that proves that the hash is an element of the
+// Merkle chain of the account. The receipt leads to the root of the chain as of
+// the anchor, or to the current root if the anchor is nil.
+func (q queryDirect) QueryReceipt(s string, hash, anchor []byte) (*QueryResponse, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUrl, err)
+	}
+
+	req := new(query.RequestReceipt)
+	copy(req.ChainId[:], u.ResourceChain())
+	copy(req.Element[:], hash)
+	if anchor != nil {
+		req.Anchor = new(types.Bytes32)
+		copy(req.Anchor[:], anchor)
+	}
+	k, v, err := q.query(req)
+	if err != nil {
+		return nil, err
+	}
+	if k != "receipt" {
+		return nil, fmt.Errorf("unknown response type: want receipt, got %q", k)
+	}
+
+	receipt := new(protocol.ReceiptQueryResult)
+	err = receipt.UnmarshalBinary(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+
+	res := new(QueryResponse)
+	res.Type = "receipt"
+	res.Data = receipt
+	return res, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
 )
 
@@ -44,18 +45,18 @@ func (q queryDispatch) queryAll(query func(queryDirect) (*QueryResponse, error))
 	return res, nil
 }
 
-func (q queryDispatch) QueryUrl(url string) (*QueryResponse, error) {
+func (q queryDispatch) QueryUrl(url string, height int64) (*QueryResponse, error) {
 	r, err := q.routing(url)
 	if err != nil {
 		return nil, err
 	}
 
-	return q.direct(r).QueryUrl(url)
+	return q.direct(r).QueryUrl(url, height)
 }
 
-func (q queryDispatch) QueryChain(id []byte) (*QueryResponse, error) {
+func (q queryDispatch) QueryChain(id []byte, height int64) (*QueryResponse, error) {
 	res, err := q.queryAll(func(q queryDirect) (*QueryResponse, error) {
-		return q.QueryChain(id)
+		return q.QueryChain(id, height)
 	})
 	if err != nil {
 		return nil, err
@@ -100,3 +101,44 @@ func (q queryDispatch) QueryTxHistory(url string, start, count int64) (*QueryMul
 
 	return q.direct(r).QueryTxHistory(url, start, count)
 }
+
+// QueryKeyIndex queries every network, since the pages of a key can belong to
+// ADIs on any of them, and merges the results.
+func (q queryDispatch) QueryKeyIndex(key []byte) (*QueryResponse, error) {
+	res, err := q.queryAll(func(q queryDirect) (*QueryResponse, error) {
+		return q.QueryKeyIndex(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	merged := new(protocol.KeyIndexQueryResult)
+	for _, r := range res {
+		ki := r.Data.(*protocol.KeyIndexQueryResult)
+		merged.Entries = append(merged.Entries, ki.Entries...)
+		if ki.LiteAccount != "" {
+			merged.LiteAccount = ki.LiteAccount
+		}
+	}
+
+	res[0].Data = merged
+	return res[0], nil
+}
+
+func (q queryDispatch) QueryTransferHistory(url string, start, count int64) (*QueryMultiResponse, error) {
+	r, err := q.routing(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.direct(r).QueryTransferHistory(url, start, count)
+}
+
+func (q queryDispatch) QueryReceipt(url string, hash, anchor []byte) (*QueryResponse, error) {
+	r, err := q.routing(url)
+	if err != nil {
+		return nil, err
+	}
+
+	return q.direct(r).QueryReceipt(url, hash, anchor)
+}
@@ -11,12 +11,17 @@ import (
 //go:generate go run ../../cmd/gentypes --package api types.yml
 //go:generate go run github.com/golang/mock/mockgen -source types.go -destination ../../mock/api/types.go
 
+// Querier queries the state of the network. QueryUrl and QueryChain return the
+// state as of the given block height, or the latest state if the height is 0.
 type Querier interface {
-	QueryUrl(url string) (*QueryResponse, error)
+	QueryUrl(url string, height int64) (*QueryResponse, error)
 	QueryDirectory(url string) (*QueryResponse, error)
-	QueryChain(id []byte) (*QueryResponse, error)
+	QueryChain(id []byte, height int64) (*QueryResponse, error)
 	QueryTx(id []byte) (*QueryResponse, error)
 	QueryTxHistory(url string, start, count int64) (*QueryMultiResponse, error)
+	QueryKeyIndex(key []byte) (*QueryResponse, error)
+	QueryTransferHistory(url string, start, count int64) (*QueryMultiResponse, error)
+	QueryReceipt(url string, hash, anchor []byte) (*QueryResponse, error)
 }
 
 // ABCIQueryClient is a subset of from TM/rpc/client.ABCIClient for sending
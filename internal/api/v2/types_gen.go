@@ -9,10 +9,16 @@ import (
 	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/encoding"
+	"github.com/AccumulateNetwork/accumulate/protocol"
 )
 
 type ChainIdQuery struct {
 	ChainId []byte `json:"chainId,omitempty" form:"chainId" query:"chainId" validate:"required"`
+	Height  uint64 `json:"height,omitempty" form:"height" query:"height"`
+}
+
+type KeyIndexQuery struct {
+	Key []byte `json:"key,omitempty" form:"key" query:"key" validate:"required"`
 }
 
 type KeyPage struct {
@@ -25,6 +31,12 @@ type MerkleState struct {
 	Roots [][]byte `json:"roots,omitempty" form:"roots" query:"roots" validate:"required"`
 }
 
+type MetadataResponse struct {
+	Url      string                    `json:"url,omitempty" form:"url" query:"url" validate:"required"`
+	Document *protocol.AccountMetadata `json:"document,omitempty" form:"document" query:"document"`
+	Error    string                    `json:"error,omitempty" form:"error" query:"error"`
+}
+
 type MetricsQuery struct {
 	Metric   string        `json:"metric,omitempty" form:"metric" query:"metric" validate:"required"`
 	Duration time.Duration `json:"duration,omitempty" form:"duration" query:"duration" validate:"required"`
@@ -42,15 +54,22 @@ type QueryMultiResponse struct {
 }
 
 type QueryResponse struct {
-	Type        string       `json:"type,omitempty" form:"type" query:"type" validate:"required"`
-	MerkleState *MerkleState `json:"merkleState,omitempty" form:"merkleState" query:"merkleState" validate:"required"`
-	Data        interface{}  `json:"data,omitempty" form:"data" query:"data" validate:"required"`
-	Sponsor     string       `json:"sponsor,omitempty" form:"sponsor" query:"sponsor" validate:"required"`
-	KeyPage     KeyPage      `json:"keyPage,omitempty" form:"keyPage" query:"keyPage" validate:"required"`
-	Txid        []byte       `json:"txid,omitempty" form:"txid" query:"txid" validate:"required"`
-	Signer      Signer       `json:"signer,omitempty" form:"signer" query:"signer" validate:"required"`
-	Sig         []byte       `json:"sig,omitempty" form:"sig" query:"sig" validate:"required"`
-	Status      interface{}  `json:"status,omitempty" form:"status" query:"status" validate:"required"`
+	Type        string            `json:"type,omitempty" form:"type" query:"type" validate:"required"`
+	MerkleState *MerkleState      `json:"merkleState,omitempty" form:"merkleState" query:"merkleState" validate:"required"`
+	Data        interface{}       `json:"data,omitempty" form:"data" query:"data" validate:"required"`
+	Sponsor     string            `json:"sponsor,omitempty" form:"sponsor" query:"sponsor" validate:"required"`
+	KeyPage     KeyPage           `json:"keyPage,omitempty" form:"keyPage" query:"keyPage" validate:"required"`
+	Txid        []byte            `json:"txid,omitempty" form:"txid" query:"txid" validate:"required"`
+	Signer      Signer            `json:"signer,omitempty" form:"signer" query:"signer" validate:"required"`
+	Sig         []byte            `json:"sig,omitempty" form:"sig" query:"sig" validate:"required"`
+	Status      interface{}       `json:"status,omitempty" form:"status" query:"status" validate:"required"`
+	Metadata    *MetadataResponse `json:"metadata,omitempty" form:"metadata" query:"metadata"`
+}
+
+type ReceiptQuery struct {
+	Url    string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
+	Hash   []byte `json:"hash,omitempty" form:"hash" query:"hash" validate:"required"`
+	Anchor []byte `json:"anchor,omitempty" form:"anchor" query:"anchor"`
 }
 
 type Signer struct {
@@ -91,9 +110,10 @@ type TxResponse struct {
 }
 
 type UrlQuery struct {
-	Url   string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
-	Start uint64 `json:"start,omitempty" form:"start" query:"start"`
-	Count uint64 `json:"count,omitempty" form:"count" query:"count"`
+	Url    string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
+	Start  uint64 `json:"start,omitempty" form:"start" query:"start"`
+	Count  uint64 `json:"count,omitempty" form:"count" query:"count"`
+	Height uint64 `json:"height,omitempty" form:"height" query:"height"`
 }
 
 func (v *MetricsQuery) BinarySize() int {
@@ -137,8 +157,18 @@ func (v *MetricsQuery) UnmarshalBinary(data []byte) error {
 func (v *ChainIdQuery) MarshalJSON() ([]byte, error) {
 	u := struct {
 		ChainId *string `json:"chainId,omitempty"`
+		Height  uint64  `json:"height,omitempty"`
 	}{}
 	u.ChainId = encoding.BytesToJSON(v.ChainId)
+	u.Height = v.Height
+	return json.Marshal(&u)
+}
+
+func (v *KeyIndexQuery) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Key *string `json:"key,omitempty"`
+	}{}
+	u.Key = encoding.BytesToJSON(v.Key)
 	return json.Marshal(&u)
 }
 
@@ -167,15 +197,16 @@ func (v *MetricsQuery) MarshalJSON() ([]byte, error) {
 
 func (v *QueryResponse) MarshalJSON() ([]byte, error) {
 	u := struct {
-		Type        string       `json:"type,omitempty"`
-		MerkleState *MerkleState `json:"merkleState,omitempty"`
-		Data        interface{}  `json:"data,omitempty"`
-		Sponsor     string       `json:"sponsor,omitempty"`
-		KeyPage     KeyPage      `json:"keyPage,omitempty"`
-		Txid        *string      `json:"txid,omitempty"`
-		Signer      Signer       `json:"signer,omitempty"`
-		Sig         *string      `json:"sig,omitempty"`
-		Status      interface{}  `json:"status,omitempty"`
+		Type        string            `json:"type,omitempty"`
+		MerkleState *MerkleState      `json:"merkleState,omitempty"`
+		Data        interface{}       `json:"data,omitempty"`
+		Sponsor     string            `json:"sponsor,omitempty"`
+		KeyPage     KeyPage           `json:"keyPage,omitempty"`
+		Txid        *string           `json:"txid,omitempty"`
+		Signer      Signer            `json:"signer,omitempty"`
+		Sig         *string           `json:"sig,omitempty"`
+		Status      interface{}       `json:"status,omitempty"`
+		Metadata    *MetadataResponse `json:"metadata,omitempty"`
 	}{}
 	u.Type = v.Type
 	u.MerkleState = v.MerkleState
@@ -186,6 +217,19 @@ func (v *QueryResponse) MarshalJSON() ([]byte, error) {
 	u.Signer = v.Signer
 	u.Sig = encoding.BytesToJSON(v.Sig)
 	u.Status = v.Status
+	u.Metadata = v.Metadata
+	return json.Marshal(&u)
+}
+
+func (v *ReceiptQuery) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Url    string  `json:"url,omitempty"`
+		Hash   *string `json:"hash,omitempty"`
+		Anchor *string `json:"anchor,omitempty"`
+	}{}
+	u.Url = v.Url
+	u.Hash = encoding.BytesToJSON(v.Hash)
+	u.Anchor = encoding.BytesToJSON(v.Anchor)
 	return json.Marshal(&u)
 }
 
@@ -256,8 +300,10 @@ func (v *TxResponse) MarshalJSON() ([]byte, error) {
 func (v *ChainIdQuery) UnmarshalJSON(data []byte) error {
 	u := struct {
 		ChainId *string `json:"chainId,omitempty"`
+		Height  uint64  `json:"height,omitempty"`
 	}{}
 	u.ChainId = encoding.BytesToJSON(v.ChainId)
+	u.Height = v.Height
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -266,6 +312,23 @@ func (v *ChainIdQuery) UnmarshalJSON(data []byte) error {
 	} else {
 		v.ChainId = x
 	}
+	v.Height = u.Height
+	return nil
+}
+
+func (v *KeyIndexQuery) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Key *string `json:"key,omitempty"`
+	}{}
+	u.Key = encoding.BytesToJSON(v.Key)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.BytesFromJSON(u.Key); err != nil {
+		return fmt.Errorf("error decoding Key: %w", err)
+	} else {
+		v.Key = x
+	}
 	return nil
 }
 
@@ -315,15 +378,16 @@ func (v *MetricsQuery) UnmarshalJSON(data []byte) error {
 
 func (v *QueryResponse) UnmarshalJSON(data []byte) error {
 	u := struct {
-		Type        string       `json:"type,omitempty"`
-		MerkleState *MerkleState `json:"merkleState,omitempty"`
-		Data        interface{}  `json:"data,omitempty"`
-		Sponsor     string       `json:"sponsor,omitempty"`
-		KeyPage     KeyPage      `json:"keyPage,omitempty"`
-		Txid        *string      `json:"txid,omitempty"`
-		Signer      Signer       `json:"signer,omitempty"`
-		Sig         *string      `json:"sig,omitempty"`
-		Status      interface{}  `json:"status,omitempty"`
+		Type        string            `json:"type,omitempty"`
+		MerkleState *MerkleState      `json:"merkleState,omitempty"`
+		Data        interface{}       `json:"data,omitempty"`
+		Sponsor     string            `json:"sponsor,omitempty"`
+		KeyPage     KeyPage           `json:"keyPage,omitempty"`
+		Txid        *string           `json:"txid,omitempty"`
+		Signer      Signer            `json:"signer,omitempty"`
+		Sig         *string           `json:"sig,omitempty"`
+		Status      interface{}       `json:"status,omitempty"`
+		Metadata    *MetadataResponse `json:"metadata,omitempty"`
 	}{}
 	u.Type = v.Type
 	u.MerkleState = v.MerkleState
@@ -334,6 +398,7 @@ func (v *QueryResponse) UnmarshalJSON(data []byte) error {
 	u.Signer = v.Signer
 	u.Sig = encoding.BytesToJSON(v.Sig)
 	u.Status = v.Status
+	u.Metadata = v.Metadata
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -354,6 +419,33 @@ func (v *QueryResponse) UnmarshalJSON(data []byte) error {
 		v.Sig = x
 	}
 	v.Status = u.Status
+	v.Metadata = u.Metadata
+	return nil
+}
+
+func (v *ReceiptQuery) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Url    string  `json:"url,omitempty"`
+		Hash   *string `json:"hash,omitempty"`
+		Anchor *string `json:"anchor,omitempty"`
+	}{}
+	u.Url = v.Url
+	u.Hash = encoding.BytesToJSON(v.Hash)
+	u.Anchor = encoding.BytesToJSON(v.Anchor)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Url = u.Url
+	if x, err := encoding.BytesFromJSON(u.Hash); err != nil {
+		return fmt.Errorf("error decoding Hash: %w", err)
+	} else {
+		v.Hash = x
+	}
+	if x, err := encoding.BytesFromJSON(u.Anchor); err != nil {
+		return fmt.Errorf("error decoding Anchor: %w", err)
+	} else {
+		v.Anchor = x
+	}
 	return nil
 }
 
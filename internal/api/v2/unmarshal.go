@@ -7,12 +7,13 @@ import (
 	"github.com/AccumulateNetwork/accumulate/smt/common"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/api"
+	"github.com/AccumulateNetwork/accumulate/types/api/query"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 	"github.com/AccumulateNetwork/accumulate/types/synthetic"
 )
 
-func unmarshalState(b []byte) (*state.Object, state.Chain, error) {
-	var obj state.Object
+func unmarshalState(b []byte) (*query.ResponseByChainId, state.Chain, error) {
+	var obj query.ResponseByChainId
 	var header state.ChainHeader
 	var chain state.Chain
 
@@ -39,6 +40,10 @@ func unmarshalState(b []byte) (*state.Object, state.Chain, error) {
 		chain = new(protocol.SigSpec)
 	case types.ChainTypeKeyBook:
 		chain = new(protocol.SigSpecGroup)
+	case types.ChainTypeDataAccount:
+		chain = new(protocol.DataAccount)
+	case types.ChainTypePendingSwap:
+		chain = new(protocol.PendingSwap)
 	case types.ChainTypeTransaction:
 		chain = new(state.Transaction)
 	default:
@@ -77,6 +82,12 @@ func unmarshalTxPayload(b []byte) (protocol.TransactionPayload, error) {
 		payload = new(protocol.AddCredits)
 	case types.TxTypeUpdateKeyPage:
 		payload = new(protocol.UpdateKeyPage)
+	case types.TxTypeSwapOffer:
+		payload = new(protocol.SwapOffer)
+	case types.TxTypeSwapAccept:
+		payload = new(protocol.SwapAccept)
+	case types.TxTypeSwapCancel:
+		payload = new(protocol.SwapCancel)
 	case types.TxTypeSyntheticCreateChain:
 		payload = new(protocol.SyntheticCreateChain)
 	case types.TxTypeSyntheticDepositCredits:
@@ -82,15 +82,25 @@ func (AddCredits) Validate(st *StateManager, tx *transactions.GenTransaction) er
 		return fmt.Errorf("%q tokens cannot be converted into credits", tokenUrl.String())
 	}
 
-	if !account.CanDebitTokens(&amount.Int) {
+	if !account.CanDebitTokens(&amount.Int, st.BlockTime) {
 		return fmt.Errorf("insufficient balance")
 	}
 
-	if !account.DebitTokens(&amount.Int) {
+	err = checkSpendLimits(st, tx, tokenUrl, &amount.Int)
+	if err != nil {
+		return err
+	}
+
+	if !account.DebitTokens(&amount.Int, st.BlockTime) {
 		return fmt.Errorf("failed to debit %v", tx.SigInfo.URL)
 	}
 	st.Update(account)
 
+	err = recordTransfer(st, st.SponsorUrl, recvUrl.String(), tokenUrl, &amount.Int, false)
+	if err != nil {
+		return err
+	}
+
 	// Create the synthetic transaction
 	sdc := new(protocol.SyntheticDepositCredits)
 	sdc.Cause = types.Bytes(tx.TransactionHash()).AsBytes32()
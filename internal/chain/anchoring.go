@@ -0,0 +1,320 @@
+package chain
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+)
+
+// AnchorPublisher anchors the root of the network into an external ledger.
+type AnchorPublisher interface {
+	// Ledger names the external ledger.
+	Ledger() string
+
+	// Publish anchors the root of a block and returns a reference to the
+	// anchor in the ledger, such as a transaction ID. Publish is called from
+	// its own goroutine and may take as long as the ledger needs, but should
+	// give up once the context is canceled.
+	Publish(ctx context.Context, anchor *protocol.ExternalAnchor) ([]byte, error)
+}
+
+// NewAnchorPublisher creates a publisher of the given type: file appends the
+// anchors to the file at target, http posts them to the URL at target.
+func NewAnchorPublisher(typ, target string) (AnchorPublisher, error) {
+	switch typ {
+	case "file":
+		return NewFileAnchorPublisher(target), nil
+	case "http":
+		return NewHTTPAnchorPublisher(target), nil
+	default:
+		return nil, fmt.Errorf("%q is not a valid anchor publisher type", typ)
+	}
+}
+
+// FileAnchorPublisher appends each anchor to a local file as a line of JSON.
+// The reference of an anchor is its offset in the file. It stands in for an
+// external ledger during development and testing.
+type FileAnchorPublisher struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileAnchorPublisher(path string) *FileAnchorPublisher {
+	return &FileAnchorPublisher{path: path}
+}
+
+func (p *FileAnchorPublisher) Ledger() string { return "file:" + p.path }
+
+func (p *FileAnchorPublisher) Publish(ctx context.Context, anchor *protocol.ExternalAnchor) ([]byte, error) {
+	line, err := json.Marshal(anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	f, err := os.OpenFile(p.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		return nil, err
+	}
+
+	err = f.Sync()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strconv.FormatInt(info.Size(), 10)), nil
+}
+
+// HTTPAnchorPublisher posts each anchor as JSON to a URL, which responds with
+// the reference of the anchor. It stands in for an external ledger that is
+// reached through a service.
+type HTTPAnchorPublisher struct {
+	url    string
+	client *http.Client
+}
+
+func NewHTTPAnchorPublisher(url string) *HTTPAnchorPublisher {
+	return &HTTPAnchorPublisher{url: url, client: &http.Client{Timeout: time.Minute}}
+}
+
+func (p *HTTPAnchorPublisher) Ledger() string { return p.url }
+
+func (p *HTTPAnchorPublisher) Publish(ctx context.Context, anchor *protocol.ExternalAnchor) ([]byte, error) {
+	body, err := json.Marshal(anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	ref, err := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("%s responded with %s", p.url, resp.Status)
+	}
+
+	ref = bytes.TrimSpace(ref)
+	if len(ref) == 0 {
+		return nil, fmt.Errorf("%s did not respond with a reference", p.url)
+	}
+	return ref, nil
+}
+
+// EnableAnchoring publishes the root of the network with the publisher every
+// time a block closes a major block, or after every block if everyBlock is
+// set. Only the leader of a block publishes its root. The anchor, with the
+// reference returned by the publisher, is then written to the data account
+// with a WriteData signed by the node, so the node's key must be on a key page
+// of the account.
+func (m *Executor) EnableAnchoring(publisher AnchorPublisher, account string, everyBlock bool) {
+	m.anchorPublisher = publisher
+	m.anchorAccount = account
+	m.anchorEveryBlock = everyBlock
+	m.anchorCtx, m.anchorCancel = context.WithCancel(context.Background())
+}
+
+// StopAnchoring cancels the anchors that are being published and waits for
+// their goroutines to return. No more anchors are published once it has been
+// called.
+func (m *Executor) StopAnchoring() {
+	if m.anchorCancel == nil {
+		return
+	}
+	m.anchorCancel()
+	m.anchorWG.Wait()
+}
+
+// publishAnchor starts publishing the root of the block that was just
+// committed, if anchoring is enabled and the block is due to be anchored.
+func (m *Executor) publishAnchor(root []byte) {
+	if m.anchorPublisher == nil || !m.leader || m.anchorCtx.Err() != nil {
+		return
+	}
+
+	major := m.dbTx.MajorAnchor()
+	if major == nil && !m.anchorEveryBlock {
+		return
+	}
+
+	// Blocks without changes are not recorded
+	index, err := m.db.BlockIndex()
+	if err != nil || index != m.height {
+		return
+	}
+
+	anchor := new(protocol.ExternalAnchor)
+	anchor.Height = m.height
+	copy(anchor.Root[:], root)
+	anchor.Timestamp = m.time
+	anchor.Major = major != nil
+	anchor.Ledger = m.anchorPublisher.Ledger()
+
+	m.anchorWG.Add(1)
+	go func() {
+		defer m.anchorWG.Done()
+		ref, err := m.anchorPublisher.Publish(m.anchorCtx, anchor)
+		if err != nil {
+			m.logError("Failed to publish anchor", "height", anchor.Height, "error", err)
+			return
+		}
+		anchor.Reference = ref
+
+		m.anchorMu.Lock()
+		m.anchors = append(m.anchors, anchor)
+		m.anchorMu.Unlock()
+	}()
+}
+
+// submitAnchors writes the anchors that have been published since the last
+// block to the anchor data account. Anchors are published concurrently and
+// finish in any order, so they are recorded in order of height.
+func (m *Executor) submitAnchors() {
+	m.anchorMu.Lock()
+	anchors := m.anchors
+	m.anchors = nil
+	m.anchorMu.Unlock()
+
+	sort.Slice(anchors, func(i, j int) bool {
+		return anchors[i].Height < anchors[j].Height
+	})
+
+	for _, anchor := range anchors {
+		err := m.submitAnchor(anchor)
+		if err != nil {
+			m.logError("Failed to record anchor", "height", anchor.Height, "error", err)
+		}
+	}
+}
+
+func (m *Executor) submitAnchor(anchor *protocol.ExternalAnchor) error {
+	data, err := anchor.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	body, err := (&protocol.WriteData{Data: data}).MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	priority, key, err := m.anchorKeyPage()
+	if err != nil {
+		return err
+	}
+
+	// The nonce must be greater than the nonce of the key, which is also used
+	// for other transactions. Anchors that were submitted earlier may not have
+	// been executed yet, so it must also be greater than the last anchor's.
+	nonce := key.Nonce
+	if m.anchorNonce > nonce {
+		nonce = m.anchorNonce
+	}
+	nonce++
+
+	tx := new(transactions.GenTransaction)
+	tx.SigInfo = new(transactions.SignatureInfo)
+	tx.SigInfo.URL = m.anchorAccount
+	tx.SigInfo.PriorityIdx = priority
+	tx.SigInfo.MSHeight = uint64(m.height)
+	tx.SigInfo.Nonce = nonce
+	tx.Transaction = body
+
+	ed := new(transactions.ED25519Sig)
+	ed.PublicKey = m.key[32:]
+	err = ed.Sign(tx.SigInfo.Nonce, m.key, tx.TransactionHash())
+	if err != nil {
+		return err
+	}
+	tx.Signature = append(tx.Signature, ed)
+
+	_, err = m.query.BroadcastTx(tx, nil)
+	if err != nil {
+		return err
+	}
+
+	m.anchorNonce = nonce
+	return nil
+}
+
+// anchorKeyPage returns the priority of the key page of the anchor account
+// that holds the node's key, and the node's key on that page.
+func (m *Executor) anchorKeyPage() (uint64, *protocol.KeySpec, error) {
+	u, err := url.Parse(m.anchorAccount)
+	if err != nil {
+		return 0, nil, fmt.Errorf("invalid anchor account %q: %v", m.anchorAccount, err)
+	}
+
+	account := new(protocol.DataAccount)
+	obj, err := m.db.GetPersistentEntry(u.ResourceChain(), false)
+	if err == nil {
+		err = obj.As(account)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load anchor account %q: %v", u, err)
+	}
+
+	book := new(protocol.SigSpecGroup)
+	obj, err = m.db.GetPersistentEntry(account.SigSpecId[:], false)
+	if err == nil {
+		err = obj.As(book)
+	}
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to load the key book of %q: %v", u, err)
+	}
+
+	for i, id := range book.SigSpecs {
+		page := new(protocol.SigSpec)
+		obj, err = m.db.GetPersistentEntry(id[:], false)
+		if err == nil {
+			err = obj.As(page)
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to load key page %d of %q: %v", i, book.ChainUrl, err)
+		}
+		if key := page.FindKey(m.key[32:]); key != nil {
+			return uint64(i), key, nil
+		}
+	}
+	return 0, nil, fmt.Errorf("the node's key is not on a key page of %q", u)
+}
@@ -0,0 +1,39 @@
+package chain_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/AccumulateNetwork/accumulate/internal/chain"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPAnchorPublisher(t *testing.T) {
+	var posted []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted, _ = io.ReadAll(r.Body)
+		if r.URL.Path == "/fail" {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("txid-1\n"))
+	}))
+	defer srv.Close()
+
+	anchor := &protocol.ExternalAnchor{Height: 10, Root: [32]byte{1}}
+	ref, err := NewHTTPAnchorPublisher(srv.URL).Publish(context.Background(), anchor)
+	require.NoError(t, err)
+	require.Equal(t, "txid-1", string(ref))
+
+	received := new(protocol.ExternalAnchor)
+	require.NoError(t, received.UnmarshalJSON(posted))
+	require.Equal(t, anchor.Height, received.Height)
+	require.Equal(t, anchor.Root, received.Root)
+
+	_, err = NewHTTPAnchorPublisher(srv.URL+"/fail").Publish(context.Background(), anchor)
+	require.Error(t, err)
+}
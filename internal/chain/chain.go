@@ -3,6 +3,7 @@ package chain
 import (
 	"crypto/ed25519"
 	"math/big"
+	"time"
 
 	accapi "github.com/AccumulateNetwork/accumulate/internal/api"
 	"github.com/AccumulateNetwork/accumulate/internal/url"
@@ -20,6 +21,12 @@ func NewBlockValidatorExecutor(query *accapi.Query, db *state.StateDB, key ed255
 		CreateKeyPage{},
 		CreateKeyBook{},
 		UpdateKeyPage{},
+		CreateToken{},
+		CreateDataAccount{},
+		WriteData{},
+		SwapOffer{},
+		SwapAccept{},
+		SwapCancel{},
 		SyntheticGenesis{},
 		SyntheticCreateChain{},
 		SyntheticTokenDeposit{},
@@ -55,6 +62,6 @@ type tokenChain interface {
 	NextTx() uint64
 	ParseTokenUrl() (*url.URL, error)
 	CreditTokens(amount *big.Int) bool
-	CanDebitTokens(amount *big.Int) bool
-	DebitTokens(amount *big.Int) bool
+	CanDebitTokens(amount *big.Int, now time.Time) bool
+	DebitTokens(amount *big.Int, now time.Time) bool
 }
@@ -1,12 +1,13 @@
 package chain
 
 import (
-	"errors"
 	"fmt"
 
+	"github.com/AccumulateNetwork/accumulate/internal/url"
 	"github.com/AccumulateNetwork/accumulate/protocol"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 type CreateDataAccount struct{}
@@ -14,11 +15,28 @@ type CreateDataAccount struct{}
 func (CreateDataAccount) Type() types.TxType { return types.TxTypeCreateDataAccount }
 
 func (CreateDataAccount) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	if _, ok := st.Sponsor.(*state.AdiState); !ok {
+		return fmt.Errorf("invalid sponsor: want chain type %v, got %v", types.ChainTypeIdentity, st.Sponsor.Header().Type)
+	}
+
 	body := new(protocol.CreateDataAccount)
 	err := tx.As(body)
 	if err != nil {
 		return fmt.Errorf("invalid payload: %v", err)
 	}
 
-	return errors.New("not implemented") // TODO
+	dataUrl, err := url.Parse(body.Url)
+	if err != nil {
+		return fmt.Errorf("invalid data account URL: %v", err)
+	}
+
+	if parent, _ := dataUrl.Parent(); !parent.Equal(st.SponsorUrl) {
+		return fmt.Errorf("%q cannot sponsor %q", st.SponsorUrl, dataUrl)
+	}
+
+	account := protocol.NewDataAccount()
+	account.ChainUrl = types.String(dataUrl.String())
+	account.SigSpecId = st.Sponsor.Header().SigSpecId
+	st.Create(account)
+	return nil
 }
@@ -32,13 +32,28 @@ func (CreateIdentity) Validate(st *StateManager, tx *transactions.GenTransaction
 		return fmt.Errorf("invalid URL: %v", err)
 	}
 
+	parentUrl, isSubAdi := identityUrl.Parent()
 	switch st.Sponsor.(type) {
-	case *protocol.AnonTokenAccount, *state.AdiState:
-		// OK
+	case *protocol.AnonTokenAccount:
+		if isSubAdi {
+			return fmt.Errorf("%q cannot sponsor %q, sub-ADIs must be sponsored by their parent", st.SponsorUrl, identityUrl)
+		}
+	case *state.AdiState:
+		if isSubAdi && !parentUrl.Equal(st.SponsorUrl) {
+			return fmt.Errorf("%q cannot sponsor %q, sub-ADIs must be sponsored by their parent", st.SponsorUrl, identityUrl)
+		}
 	default:
 		return fmt.Errorf("chain type %d cannot sponsor ADIs", st.Sponsor.Header().Type)
 	}
 
+	// A sub-ADI created without a key inherits its parent's key book
+	if isSubAdi && len(body.PublicKey) == 0 {
+		identity := state.NewADI(types.String(identityUrl.String()), state.KeyTypeSha256, nil)
+		identity.SigSpecId = st.Sponsor.Header().SigSpecId
+		st.Create(identity)
+		return setMetadata(st, identity, body.Metadata)
+	}
+
 	var sigSpecUrl, ssgUrl *url.URL
 	if body.KeyPageName == "" {
 		sigSpecUrl = identityUrl.JoinPath("sigspec0")
@@ -67,5 +82,5 @@ func (CreateIdentity) Validate(st *StateManager, tx *transactions.GenTransaction
 	identity.SigSpecId = types.Bytes(ssgUrl.ResourceChain()).AsBytes32()
 
 	st.Create(identity, group, sigSpec)
-	return nil
+	return setMetadata(st, identity, body.Metadata)
 }
@@ -34,7 +34,7 @@ func (CreateKeyBook) Validate(st *StateManager, tx *transactions.GenTransaction)
 		return fmt.Errorf("invalid target URL: %v", err)
 	}
 
-	if !sgUrl.Identity().Equal(st.SponsorUrl) {
+	if parent, _ := sgUrl.Parent(); !parent.Equal(st.SponsorUrl) {
 		return fmt.Errorf("%q does not belong to %q", sgUrl, st.SponsorUrl)
 	}
 
@@ -52,7 +52,7 @@ func (CreateKeyBook) Validate(st *StateManager, tx *transactions.GenTransaction)
 			return fmt.Errorf("invalid sig spec state: bad URL: %v", err)
 		}
 
-		if !u.Identity().Equal(st.SponsorUrl) {
+		if parent, _ := u.Parent(); !parent.Equal(st.SponsorUrl) {
 			return fmt.Errorf("%q does not belong to %q", u, st.SponsorUrl)
 		}
 
@@ -40,7 +40,13 @@ func (CreateKeyPage) Validate(st *StateManager, tx *transactions.GenTransaction)
 		return fmt.Errorf("invalid target URL: %v", err)
 	}
 
-	if !msUrl.Identity().Equal(st.SponsorUrl.Identity()) {
+	// The sponsor is either the ADI or one of its key books
+	adiUrl := st.SponsorUrl
+	if group != nil {
+		adiUrl, _ = st.SponsorUrl.Parent()
+	}
+
+	if parent, _ := msUrl.Parent(); !parent.Equal(adiUrl) {
 		return fmt.Errorf("%q does not belong to %q", msUrl, st.SponsorUrl)
 	}
 
@@ -71,6 +77,10 @@ func (CreateKeyPage) Validate(st *StateManager, tx *transactions.GenTransaction)
 	for _, sig := range body.Keys {
 		ss := new(protocol.KeySpec)
 		ss.PublicKey = sig.PublicKey
+		ss.Limits, err = newSpendLimits(sig.Limits)
+		if err != nil {
+			return err
+		}
 		spec.Keys = append(spec.Keys, ss)
 	}
 
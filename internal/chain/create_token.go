@@ -1,12 +1,13 @@
 package chain
 
 import (
-	"errors"
 	"fmt"
 
+	"github.com/AccumulateNetwork/accumulate/internal/url"
 	"github.com/AccumulateNetwork/accumulate/protocol"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 type CreateToken struct{}
@@ -14,11 +15,37 @@ type CreateToken struct{}
 func (CreateToken) Type() types.TxType { return types.TxTypeCreateToken }
 
 func (CreateToken) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	if _, ok := st.Sponsor.(*state.AdiState); !ok {
+		return fmt.Errorf("invalid sponsor: want chain type %v, got %v", types.ChainTypeIdentity, st.Sponsor.Header().Type)
+	}
+
 	body := new(protocol.CreateToken)
 	err := tx.As(body)
 	if err != nil {
 		return fmt.Errorf("invalid payload: %v", err)
 	}
 
-	return errors.New("not implemented") // TODO
+	tokenUrl, err := url.Parse(body.Url)
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+
+	if parent, _ := tokenUrl.Parent(); !parent.Equal(st.SponsorUrl) {
+		return fmt.Errorf("%q cannot sponsor %q", st.SponsorUrl, tokenUrl)
+	}
+
+	if body.Symbol == "" {
+		return fmt.Errorf("missing token symbol")
+	}
+
+	issuer := protocol.NewTokenIssuer()
+	issuer.ChainUrl = types.String(tokenUrl.String())
+	issuer.SigSpecId = st.Sponsor.Header().SigSpecId
+	issuer.Symbol = body.Symbol
+	issuer.Precision = body.Precision
+	issuer.Properties = body.Properties
+
+	// The token properties are the issuer's metadata
+	st.Create(issuer)
+	return setMetadata(st, issuer, body.Properties)
 }
@@ -2,6 +2,7 @@ package chain
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/url"
 	"github.com/AccumulateNetwork/accumulate/protocol"
@@ -32,7 +33,7 @@ func (CreateTokenAccount) Validate(st *StateManager, tx *transactions.GenTransac
 	}
 	// TODO Make sure tokenUrl is a real kind of token
 
-	if !accountUrl.Identity().Equal(st.SponsorUrl) {
+	if parent, _ := accountUrl.Parent(); !parent.Equal(st.SponsorUrl) {
 		return fmt.Errorf("%q cannot sponsor %q", st.SponsorUrl, accountUrl)
 	}
 
@@ -54,6 +55,34 @@ func (CreateTokenAccount) Validate(st *StateManager, tx *transactions.GenTransac
 		copy(account.SigSpecId[:], keyBookUrl.ResourceChain())
 	}
 
+	if body.Vesting.Locked.Sign() != 0 {
+		account.Vesting, err = newVestingSchedule(&body.Vesting, st.BlockTime)
+		if err != nil {
+			return err
+		}
+	}
+
 	st.Create(account)
-	return nil
+	return setMetadata(st, account, body.Metadata)
+}
+
+func newVestingSchedule(params *state.VestingSchedule, now time.Time) (*state.VestingSchedule, error) {
+	if params.Locked.Sign() < 0 {
+		return nil, fmt.Errorf("invalid vesting schedule: locked amount is negative")
+	}
+	if params.Cliff < 0 || params.Duration < params.Cliff {
+		return nil, fmt.Errorf("invalid vesting schedule: want 0 <= cliff <= duration, got cliff %v and duration %v", params.Cliff, params.Duration)
+	}
+
+	vesting := new(state.VestingSchedule)
+	vesting.Locked.Set(&params.Locked)
+	vesting.Start = params.Start
+	vesting.Cliff = params.Cliff
+	vesting.Duration = params.Duration
+
+	// Vesting starts when the account is created unless otherwise specified
+	if vesting.Start.IsZero() {
+		vesting.Start = now
+	}
+	return vesting, nil
 }
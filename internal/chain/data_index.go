@@ -0,0 +1,40 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+)
+
+// writeDataEntry appends an entry to the data index of a data account and
+// makes it the latest entry of the account. Earlier entries are kept in the
+// index, so writing to an account never discards what was written before.
+func writeDataEntry(st *StateManager, u *url.URL, account *protocol.DataAccount, data []byte) error {
+	md := new(protocol.DataIndexMetadata)
+	chain := u.ResourceChain()
+	b, err := st.GetIndex(state.DataIndex, chain, "Metadata")
+	if err == nil {
+		err = md.UnmarshalBinary(b)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load the data index of %q: %v", u, err)
+	}
+
+	c := md.Count
+	md.Count++
+	b, err = md.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %v", err)
+	}
+
+	st.WriteIndex(state.DataIndex, chain, "Metadata", b)
+	st.WriteIndex(state.DataIndex, chain, c, data)
+
+	account.Data = data
+	st.Update(account)
+	return nil
+}
@@ -2,6 +2,7 @@ package chain
 
 import (
 	"bytes"
+	"context"
 	"crypto/ed25519"
 	"crypto/sha256"
 	"encoding/json"
@@ -19,6 +20,7 @@ import (
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
 	"github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/tendermint/tendermint/libs/log"
 )
 
 const chainWGSize = 4
@@ -36,6 +38,18 @@ type Executor struct {
 	height  int64
 	dbTx    *state.DBTransaction
 	time    time.Time
+
+	anchorPublisher  AnchorPublisher
+	anchorAccount    string
+	anchorEveryBlock bool
+	anchorMu         sync.Mutex
+	anchors          []*protocol.ExternalAnchor // published anchors that have not been recorded
+	anchorCtx        context.Context
+	anchorCancel     context.CancelFunc
+	anchorWG         sync.WaitGroup // anchors that are being published
+	anchorNonce      uint64         // nonce of the last anchor that was recorded
+
+	logger log.Logger
 }
 
 var _ abci.Chain = (*Executor)(nil)
@@ -67,6 +81,20 @@ func NewExecutor(query *accapi.Query, db *state.StateDB, key ed25519.PrivateKey,
 	return m, nil
 }
 
+// SetLogger sets the logger the executor reports errors to
+func (m *Executor) SetLogger(logger log.Logger) {
+	if logger != nil {
+		logger = logger.With("module", "executor")
+	}
+	m.logger = logger
+}
+
+func (m *Executor) logError(msg string, keyVals ...interface{}) {
+	if m.logger != nil {
+		m.logger.Error(msg, keyVals...)
+	}
+}
+
 func (m *Executor) InitChain(state []byte) error {
 	src := new(memory.DB)
 	_ = src.InitDB("")
@@ -109,6 +137,9 @@ func (m *Executor) check(tx *transactions.GenTransaction) (*StateManager, error)
 	txt := tx.TransactionType()
 
 	st, err := NewStateManager(m.dbTx, tx)
+	if st != nil {
+		st.BlockTime = m.time
+	}
 	if errors.Is(err, storage.ErrNotFound) {
 		switch txt {
 		case types.TxTypeSyntheticCreateChain, types.TxTypeSyntheticDepositTokens:
@@ -129,7 +160,7 @@ func (m *Executor) check(tx *transactions.GenTransaction) (*StateManager, error)
 	case *protocol.AnonTokenAccount:
 		return st, m.checkAnonymous(st, tx, sponsor)
 
-	case *state.AdiState, *state.TokenAccount, *protocol.SigSpec:
+	case *state.AdiState, *state.TokenAccount, *protocol.SigSpec, *protocol.DataAccount:
 		if (sponsor.Header().SigSpecId == types.Bytes32{}) {
 			return nil, fmt.Errorf("sponsor has not been assigned to an SSG")
 		}
@@ -354,7 +385,12 @@ func (m *Executor) DeliverTx(tx *transactions.GenTransaction) (*protocol.TxResul
 }
 
 // EndBlock implements ./abci.Chain
-func (m *Executor) EndBlock(req abci.EndBlockRequest) {}
+func (m *Executor) EndBlock(req abci.EndBlockRequest) {
+	err := m.releaseExpiredSwaps()
+	if err != nil {
+		m.logError("Failed to release expired swap offers", "error", err)
+	}
+}
 
 // Commit implements ./abci.Chain
 func (m *Executor) Commit() ([]byte, error) {
@@ -382,6 +418,8 @@ func (m *Executor) Commit() ([]byte, error) {
 	// 	//m.processValidatedSubmissionRequest(&dbvc)
 	// }
 
+	m.publishAnchor(mdRoot)
+	m.submitAnchors()
 	m.query.BatchSend()
 
 	fmt.Printf("DB time %f\n", m.db.TimeBucket)
@@ -389,9 +427,16 @@ func (m *Executor) Commit() ([]byte, error) {
 	return mdRoot, nil
 }
 
+// nextSynthCount increments the number of synthetic transactions produced by
+// the node. The count determines the nonce of synthetic transactions, so it is
+// kept in a record, which is covered by the app hash. Databases created before
+// the count was a record have it outside of the BPT under the same key.
 func (m *Executor) nextSynthCount() (uint64, error) {
-	k := storage.ComputeKey("SyntheticTransactionCount")
-	b, err := m.dbTx.Read(k)
+	k := state.SyntheticTransactionCountKey()
+	b, err := m.dbTx.ReadRecord(k)
+	if errors.Is(err, storage.ErrNotFound) {
+		b, err = m.dbTx.Read(k)
+	}
 	if err != nil && !errors.Is(err, storage.ErrNotFound) {
 		return 0, err
 	}
@@ -400,7 +445,7 @@ func (m *Executor) nextSynthCount() (uint64, error) {
 	if len(b) > 0 {
 		n, _ = common.BytesUint64(b)
 	}
-	m.dbTx.Write(k, common.Uint64Bytes(n+1))
+	m.dbTx.WriteRecord(k, common.Uint64Bytes(n+1))
 	return n, nil
 }
 
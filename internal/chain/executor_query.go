@@ -1,6 +1,7 @@
 package chain
 
 import (
+	"bytes"
 	"encoding"
 	"encoding/hex"
 	"errors"
@@ -14,7 +15,7 @@ import (
 	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
-func (m *Executor) queryByUrl(u *url.URL) ([]byte, encoding.BinaryMarshaler, error) {
+func (m *Executor) queryByUrl(u *url.URL, height int64) ([]byte, encoding.BinaryMarshaler, error) {
 	qv := u.QueryValues()
 	switch {
 	case qv.Get("txid") != "":
@@ -29,20 +30,28 @@ func (m *Executor) queryByUrl(u *url.URL) ([]byte, encoding.BinaryMarshaler, err
 
 	default:
 		// Query by chain URL
-		v, err := m.queryByChainId(u.ResourceChain())
+		v, err := m.queryByChainId(u.ResourceChain(), height)
 		return []byte("chain"), v, err
 	}
 }
 
-func (m *Executor) queryByChainId(chainId []byte) (*query.ResponseByChainId, error) {
+func (m *Executor) queryByChainId(chainId []byte, height int64) (*query.ResponseByChainId, error) {
 	qr := query.ResponseByChainId{}
 
-	// This intentionally uses GetPersistentEntry instead of GetCurrentEntry.
-	// Callers should never see uncommitted values.
-	obj, err := m.db.GetPersistentEntry(chainId, false)
-	// Or a transaction
-	if errors.Is(err, storage.ErrNotFound) {
-		obj, err = m.db.GetTransaction(chainId)
+	var obj *state.Object
+	var err error
+	if height > 0 {
+		// Load the state as of the given block. Transactions are not versioned,
+		// so they are only found by queries of the latest state.
+		obj, err = m.db.GetPersistentEntryAt(chainId, height)
+	} else {
+		// This intentionally uses GetPersistentEntry instead of GetCurrentEntry.
+		// Callers should never see uncommitted values.
+		obj, err = m.db.GetPersistentEntry(chainId, false)
+		// Or a transaction
+		if errors.Is(err, storage.ErrNotFound) {
+			obj, err = m.db.GetTransaction(chainId)
+		}
 	}
 	// Not a state entry or a transaction
 	if errors.Is(err, storage.ErrNotFound) {
@@ -58,6 +67,13 @@ func (m *Executor) queryByChainId(chainId []byte) (*query.ResponseByChainId, err
 		return nil, fmt.Errorf("unable to extract chain header for chain id %x: %v", chainId, err)
 	}
 
+	// Report the time of the block the state is as of, so the caller can
+	// evaluate it against the same clock as transactions
+	qr.BlockTime, err = m.db.BlockTime(height)
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load the time of block %d: %v", height, err)
+	}
+
 	qr.Object = *obj
 	return &qr, nil
 }
@@ -86,6 +102,182 @@ func (m *Executor) queryDirectoryByChainId(chainId []byte) (*protocol.DirectoryQ
 	return resp, nil
 }
 
+func (m *Executor) queryMetadataByChainId(chainId []byte) (*protocol.MetadataQueryResult, error) {
+	b, err := m.db.GetIndex(state.MetadataIndex, chainId, "Url")
+	if err != nil {
+		return nil, err
+	}
+
+	dataUrl, err := url.Parse(string(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata URL: %v", err)
+	}
+
+	resp := new(protocol.MetadataQueryResult)
+	resp.Url = dataUrl.String()
+
+	// The data account may have been emptied, or it may not be stored on this
+	// BVC; either way the caller still gets the URL
+	obj, err := m.db.GetPersistentEntry(dataUrl.ResourceChain(), false)
+	switch {
+	case err == nil:
+		account := new(protocol.DataAccount)
+		err = obj.As(account)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata account %q: %v", dataUrl, err)
+		}
+		resp.Data = account.Data
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("failed to load metadata account %q: %v", dataUrl, err)
+	}
+
+	return resp, nil
+}
+
+func (m *Executor) queryKeyIndex(key []byte) (*protocol.KeyIndexQueryResult, error) {
+	resp := new(protocol.KeyIndexQueryResult)
+
+	b, err := m.db.GetIndex(state.KeyIndex, key, "Pages")
+	switch {
+	case err == nil:
+		index := new(protocol.KeyPageIndex)
+		err = index.UnmarshalBinary(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key index: %v", err)
+		}
+
+		// The key book and priority of a page are resolved here rather than
+		// indexed, because they change when the page is added to a book. An
+		// entry whose page or book is not on this BVC is stale, so it is
+		// skipped rather than failing the query.
+		for _, pageUrl := range index.KeyPages {
+			entry, err := m.resolveKeyPage(pageUrl)
+			if errors.Is(err, storage.ErrNotFound) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			resp.Entries = append(resp.Entries, entry)
+		}
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("failed to load key index: %v", err)
+	}
+
+	liteUrl, err := protocol.AnonymousAddress(key, protocol.ACME)
+	if err != nil {
+		return nil, err
+	}
+	_, err = m.db.GetPersistentEntry(liteUrl.ResourceChain(), false)
+	switch {
+	case err == nil:
+		resp.LiteAccount = liteUrl.String()
+	case !errors.Is(err, storage.ErrNotFound):
+		return nil, fmt.Errorf("failed to load %q: %v", liteUrl, err)
+	}
+
+	if len(resp.Entries) == 0 && resp.LiteAccount == "" {
+		return nil, fmt.Errorf("%w: no key page or lite account found for key %X", storage.ErrNotFound, key)
+	}
+	return resp, nil
+}
+
+// resolveKeyPage finds the key book of a key page and the page's priority
+// within the book
+func (m *Executor) resolveKeyPage(pageUrl string) (*protocol.KeyIndexEntry, error) {
+	u, err := url.Parse(pageUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key page URL %q in key index: %v", pageUrl, err)
+	}
+
+	page := new(protocol.SigSpec)
+	obj, err := m.db.GetPersistentEntry(u.ResourceChain(), false)
+	if err == nil {
+		err = obj.As(page)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load key page %q: %w", u, err)
+	}
+
+	entry := new(protocol.KeyIndexEntry)
+	entry.KeyPage = u.String()
+	if page.SigSpecId == (types.Bytes32{}) {
+		return entry, nil
+	}
+
+	book := new(protocol.SigSpecGroup)
+	obj, err = m.db.GetPersistentEntry(page.SigSpecId[:], false)
+	if err == nil {
+		err = obj.As(book)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the key book of %q: %w", u, err)
+	}
+
+	entry.KeyBook = string(book.ChainUrl)
+	for i, id := range book.SigSpecs {
+		if bytes.Equal(id[:], u.ResourceChain()) {
+			entry.Index = uint64(i)
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot find %q in key book %q", u, book.ChainUrl)
+}
+
+func (m *Executor) queryTransferHistory(chainId []byte, start, limit int64) (*protocol.TransferHistoryQueryResult, error) {
+	resp := new(protocol.TransferHistoryQueryResult)
+
+	md := new(protocol.TransferIndexMetadata)
+	b, err := m.db.GetIndex(state.TransferIndex, chainId, "Metadata")
+	if errors.Is(err, storage.ErrNotFound) {
+		// The account has no transfers
+		return resp, nil
+	}
+	if err == nil {
+		err = md.UnmarshalBinary(b)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load metadata: %v", err)
+	}
+
+	resp.Total = md.Count
+	end := uint64(start + limit)
+	if end > md.Count {
+		end = md.Count
+	}
+	for i := uint64(start); i < end; i++ {
+		b, err := m.db.GetIndex(state.TransferIndex, chainId, i)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transfer %d: %v", i, err)
+		}
+
+		transfer := new(protocol.TransferRecord)
+		err = transfer.UnmarshalBinary(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid transfer %d: %v", i, err)
+		}
+		resp.Records = append(resp.Records, transfer)
+	}
+	return resp, nil
+}
+
+func (m *Executor) queryReceipt(req *query.RequestReceipt) (*protocol.ReceiptQueryResult, error) {
+	var anchor []byte
+	if req.Anchor != nil {
+		anchor = req.Anchor[:]
+	}
+
+	receipt, elementIndex, anchorIndex, err := m.db.GetReceipt(req.ChainId[:], req.Element[:], anchor)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := new(protocol.ReceiptQueryResult)
+	resp.ElementIndex = uint64(elementIndex)
+	resp.AnchorIndex = uint64(anchorIndex)
+	resp.Receipt = *protocol.NewReceipt(receipt)
+	return resp, nil
+}
+
 func (m *Executor) queryByTxId(txid []byte) (*query.ResponseByTxId, error) {
 	var err error
 
@@ -113,6 +305,11 @@ func (m *Executor) queryByTxId(txid []byte) (*query.ResponseByTxId, error) {
 	return &qr, nil
 }
 
+// maxTxHistory is the most records a transaction or transfer history query
+// returns. Longer ranges are cut short, and the caller can page through the
+// rest using the total.
+const maxTxHistory = 1000
+
 func (m *Executor) Query(q *query.Query) (k, v []byte, err *protocol.Error) {
 	switch q.Type {
 	case types.QueryTypeTxId:
@@ -137,19 +334,32 @@ func (m *Executor) Query(q *query.Query) (k, v []byte, err *protocol.Error) {
 			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
 		}
 
+		if txh.Start < 0 || txh.Limit < 0 {
+			return nil, nil, &protocol.Error{Code: protocol.CodeTxnHistory, Message: fmt.Errorf("invalid range [%d, %d)", txh.Start, txh.Start+txh.Limit)}
+		}
+		if txh.Limit > maxTxHistory {
+			txh.Limit = maxTxHistory
+		}
+
 		thr := query.ResponseTxHistory{}
-		txids, maxAmt, err := m.db.GetTxRange(&txh.ChainId, txh.Start, txh.Start+txh.Limit)
+		var qerr error
+		thr.Total, err = m.db.StreamTxRange(&txh.ChainId, txh.Start, txh.Start+txh.Limit, func(_ int64, txids []types.Bytes32) error {
+			for i := range txids {
+				qr, err := m.queryByTxId(txids[i][:])
+				if err != nil {
+					qerr = err
+					return err
+				}
+				thr.Transactions = append(thr.Transactions, *qr)
+			}
+			return nil
+		})
+		if qerr != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeTxnQueryError, Message: qerr}
+		}
 		if err != nil {
 			return nil, nil, &protocol.Error{Code: protocol.CodeTxnHistory, Message: fmt.Errorf("error obtaining txid range %v", err)}
 		}
-		thr.Total = maxAmt
-		for i := range txids {
-			qr, err := m.queryByTxId(txids[i][:])
-			if err != nil {
-				return nil, nil, &protocol.Error{Code: protocol.CodeTxnQueryError, Message: err}
-			}
-			thr.Transactions = append(thr.Transactions, *qr)
-		}
 		k = []byte("tx-history")
 		v, err = thr.MarshalBinary()
 		if err != nil {
@@ -167,7 +377,7 @@ func (m *Executor) Query(q *query.Query) (k, v []byte, err *protocol.Error) {
 		}
 
 		var obj encoding.BinaryMarshaler
-		k, obj, err = m.queryByUrl(u)
+		k, obj, err = m.queryByUrl(u, chr.Height)
 		if err != nil {
 			return nil, nil, &protocol.Error{Code: protocol.CodeTxnQueryError, Message: err}
 		}
@@ -194,13 +404,83 @@ func (m *Executor) Query(q *query.Query) (k, v []byte, err *protocol.Error) {
 		if err != nil {
 			return nil, nil, &protocol.Error{Code: protocol.CodeMarshallingError, Message: fmt.Errorf("%v, on Url %s", err, chr.Url)}
 		}
+	case types.QueryTypeMetadataUrl:
+		chr := query.RequestByUrl{}
+		err := chr.UnmarshalBinary(q.Content)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
+		}
+		u, err := url.Parse(*chr.Url.AsString())
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeInvalidURL, Message: fmt.Errorf("invalid URL in query %s", chr.Url)}
+		}
+		md, err := m.queryMetadataByChainId(u.ResourceChain())
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeMetadataURL, Message: err}
+		}
+		k = []byte("metadata")
+		v, err = md.MarshalBinary()
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeMarshallingError, Message: fmt.Errorf("%v, on Url %s", err, chr.Url)}
+		}
+	case types.QueryTypeTransferHistory:
+		txh := query.RequestTxHistory{}
+		err := txh.UnmarshalBinary(q.Content)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
+		}
+		if txh.Start < 0 || txh.Limit < 0 {
+			return nil, nil, &protocol.Error{Code: protocol.CodeTransferHistory, Message: fmt.Errorf("invalid range [%d, %d)", txh.Start, txh.Start+txh.Limit)}
+		}
+		if txh.Limit > maxTxHistory {
+			txh.Limit = maxTxHistory
+		}
+		thr, err := m.queryTransferHistory(txh.ChainId[:], txh.Start, txh.Limit)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeTransferHistory, Message: err}
+		}
+		k = []byte("transfer-history")
+		v, err = thr.MarshalBinary()
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeMarshallingError, Message: fmt.Errorf("error marshalling payload for transfer history")}
+		}
+	case types.QueryTypeReceipt:
+		rr := query.RequestReceipt{}
+		err := rr.UnmarshalBinary(q.Content)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
+		}
+		rqr, err := m.queryReceipt(&rr)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeReceipt, Message: err}
+		}
+		k = []byte("receipt")
+		v, err = rqr.MarshalBinary()
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeMarshallingError, Message: fmt.Errorf("%v, on element %X", err, rr.Element)}
+		}
+	case types.QueryTypeKeyIndex:
+		chr := query.RequestKeyIndex{}
+		err := chr.UnmarshalBinary(q.Content)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
+		}
+		ki, err := m.queryKeyIndex(chr.Key)
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeKeyIndex, Message: err}
+		}
+		k = []byte("key-index")
+		v, err = ki.MarshalBinary()
+		if err != nil {
+			return nil, nil, &protocol.Error{Code: protocol.CodeMarshallingError, Message: fmt.Errorf("%v, on key %X", err, chr.Key)}
+		}
 	case types.QueryTypeChainId:
 		chr := query.RequestByChainId{}
-		err := chr.UnmarshalBinary(chr.ChainId[:])
+		err := chr.UnmarshalBinary(q.Content)
 		if err != nil {
 			return nil, nil, &protocol.Error{Code: protocol.CodeUnMarshallingError, Message: err}
 		}
-		obj, err := m.queryByChainId(chr.ChainId[:])
+		obj, err := m.queryByChainId(chr.ChainId[:], chr.Height)
 		if err != nil {
 			return nil, nil, &protocol.Error{Code: protocol.CodeChainIdError, Message: err}
 		}
@@ -0,0 +1,86 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+)
+
+// publicKeys returns the public keys of a key page
+func publicKeys(page *protocol.SigSpec) [][]byte {
+	keys := make([][]byte, len(page.Keys))
+	for i, key := range page.Keys {
+		keys[i] = key.PublicKey
+	}
+	return keys
+}
+
+func containsKey(keys [][]byte, key []byte) bool {
+	for _, k := range keys {
+		if bytes.Equal(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// updateKeyIndex updates the key index of every key that was added to or
+// removed from the page. Before is the list of keys the page had before it was
+// modified, or nil if the page is new.
+func updateKeyIndex(st *StateManager, page *protocol.SigSpec, before [][]byte) error {
+	after := publicKeys(page)
+	for _, key := range before {
+		if containsKey(after, key) {
+			continue
+		}
+		err := updateKeyIndexEntry(st, key, string(page.ChainUrl), false)
+		if err != nil {
+			return err
+		}
+	}
+	for _, key := range after {
+		if containsKey(before, key) {
+			continue
+		}
+		err := updateKeyIndexEntry(st, key, string(page.ChainUrl), true)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateKeyIndexEntry adds the page to or removes it from the key index of
+// the key
+func updateKeyIndexEntry(st *StateManager, key []byte, page string, add bool) error {
+	index := new(protocol.KeyPageIndex)
+	b, err := st.GetIndex(state.KeyIndex, key, "Pages")
+	if err == nil {
+		err = index.UnmarshalBinary(b)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load key index: %v", err)
+	}
+
+	pages := index.KeyPages[:0]
+	for _, p := range index.KeyPages {
+		if p != page {
+			pages = append(pages, p)
+		}
+	}
+	if add {
+		pages = append(pages, page)
+	}
+	index.KeyPages = pages
+
+	b, err = index.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal key index: %v", err)
+	}
+	st.WriteIndex(state.KeyIndex, key, "Pages", b)
+	return nil
+}
@@ -0,0 +1,47 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+)
+
+// setMetadata validates the data account referenced as the metadata of a
+// record queued by Create and attaches it to the record. The data account is
+// loaded from this BVC, so it must be routed along with the sponsor. If the
+// data account already holds a document, the document must be valid.
+func setMetadata(st *StateManager, record state.Chain, metadata string) error {
+	if metadata == "" {
+		return nil
+	}
+
+	dataUrl, err := url.Parse(metadata)
+	if err != nil {
+		return fmt.Errorf("invalid metadata URL: %v", err)
+	}
+
+	account := new(protocol.DataAccount)
+	err = st.LoadUrlAs(dataUrl, account)
+	switch {
+	case err == nil:
+		// Ok
+	case errors.Is(err, storage.ErrNotFound):
+		return fmt.Errorf("metadata account %q does not exist", dataUrl)
+	default:
+		return fmt.Errorf("invalid metadata account %q: %v", dataUrl, err)
+	}
+
+	if len(account.Data) > 0 {
+		_, err = protocol.ParseAccountMetadata(account.Data)
+		if err != nil {
+			return fmt.Errorf("metadata account %q: %v", dataUrl, err)
+		}
+	}
+
+	st.SetMetadata(record, dataUrl)
+	return nil
+}
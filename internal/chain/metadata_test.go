@@ -0,0 +1,51 @@
+package chain_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/AccumulateNetwork/accumulate/internal/chain"
+	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTokenAccount_Metadata(t *testing.T) {
+	db := new(state.StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, true))
+
+	fooKey := generateKey()
+	dbtx := db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbtx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "foo/tokens", protocol.AcmeUrl().String(), 1, false))
+
+	empty := protocol.NewDataAccount()
+	empty.ChainUrl = "acc://foo/empty"
+	invalid := protocol.NewDataAccount()
+	invalid.ChainUrl = "acc://foo/invalid"
+	invalid.Data = []byte(`{"description":"no name"}`)
+	require.NoError(t, acctesting.WriteStates(dbtx, empty, invalid))
+	_, err := dbtx.Commit(1, time.Unix(0, 0))
+	require.NoError(t, err)
+
+	create := func(metadata string) error {
+		body := new(protocol.TokenAccountCreate)
+		body.Url = "foo/account"
+		body.TokenUrl = protocol.AcmeUrl().String()
+		body.Metadata = metadata
+
+		tx, err := transactions.New("foo", edSigner(fooKey, 1), body)
+		require.NoError(t, err)
+		st, err := NewStateManager(db.Begin(), tx)
+		require.NoError(t, err)
+		return CreateTokenAccount{}.Validate(st, tx)
+	}
+
+	require.NoError(t, create("foo/empty"))
+	require.EqualError(t, create("foo/missing"), `metadata account "acc://foo/missing" does not exist`)
+	require.Error(t, create("foo/tokens"), "Token accounts cannot hold metadata")
+	require.EqualError(t, create("foo/invalid"), `metadata account "acc://foo/invalid": invalid metadata document: missing name`)
+}
@@ -0,0 +1,97 @@
+package chain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+)
+
+// checkSpendLimits verifies that the keys that signed the transaction are
+// allowed to spend the given amount of tokens and records the spend against
+// each key's current window. Lite accounts are not governed by a key page, so
+// they are never limited.
+func checkSpendLimits(st *StateManager, tx *transactions.GenTransaction, tokenUrl *url.URL, amount *big.Int) error {
+	bookId := st.Sponsor.Header().SigSpecId
+	if bookId == (types.Bytes32{}) {
+		return nil
+	}
+
+	book := new(protocol.SigSpecGroup)
+	err := st.LoadAs(bookId, book)
+	if err != nil {
+		return fmt.Errorf("invalid key book: %v", err)
+	}
+
+	if tx.SigInfo.PriorityIdx >= uint64(len(book.SigSpecs)) {
+		return fmt.Errorf("invalid sig spec index")
+	}
+
+	page := new(protocol.SigSpec)
+	err = st.LoadAs(book.SigSpecs[tx.SigInfo.PriorityIdx], page)
+	if err != nil {
+		return fmt.Errorf("invalid sig spec: %v", err)
+	}
+
+	var updated bool
+	seen := map[*protocol.KeySpec]bool{}
+	for i, sig := range tx.Signature {
+		key := page.FindKey(sig.PublicKey)
+		if key == nil || seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		for _, limit := range key.Limits {
+			limitUrl, err := url.Parse(limit.TokenUrl)
+			if err != nil {
+				return fmt.Errorf("invalid spend limit on key %d: %v", i, err)
+			}
+			if !limitUrl.Equal(tokenUrl) {
+				continue
+			}
+
+			ok, changed := limit.Spend(amount, st.BlockTime)
+			if !ok {
+				return fmt.Errorf("spend of %v %s exceeds the limit of key %d on page %d, a higher priority key page is required", amount, tokenUrl, i, tx.SigInfo.PriorityIdx)
+			}
+			updated = updated || changed
+		}
+	}
+
+	if updated {
+		st.Update(page)
+	}
+	return nil
+}
+
+// newSpendLimits validates the requested limits and copies them with an empty
+// spend window.
+func newSpendLimits(params []*protocol.KeySpendLimit) ([]*protocol.KeySpendLimit, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	limits := make([]*protocol.KeySpendLimit, len(params))
+	for i, p := range params {
+		tokenUrl, err := url.Parse(p.TokenUrl)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token URL for spend limit %d: %v", i, err)
+		}
+
+		if p.MaxPerPeriod == 0 && p.Period != 0 {
+			return nil, fmt.Errorf("spend limit %d has a period but no maximum per period", i)
+		}
+
+		limits[i] = &protocol.KeySpendLimit{
+			TokenUrl:     tokenUrl.String(),
+			MaxPerTx:     p.MaxPerTx,
+			MaxPerPeriod: p.MaxPerPeriod,
+			Period:       p.Period,
+		}
+	}
+	return limits, nil
+}
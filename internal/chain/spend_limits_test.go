@@ -0,0 +1,106 @@
+package chain_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/AccumulateNetwork/accumulate/internal/chain"
+	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+	tmed25519 "github.com/tendermint/tendermint/crypto/ed25519"
+)
+
+func TestWithdrawTokens_SpendLimits(t *testing.T) {
+	db := new(state.StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, true))
+
+	// foo/sigspec0 holds the admin key, foo/page1 holds a bot key with limits
+	fooKey, botKey := generateKey(), generateKey()
+	dbtx := db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbtx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "foo/tokens", protocol.AcmeUrl().String(), 10, false))
+
+	bookUrl, err := url.Parse("foo/ssg0")
+	require.NoError(t, err)
+	pageUrl, err := url.Parse("foo/page1")
+	require.NoError(t, err)
+
+	book := new(protocol.SigSpecGroup)
+	bookId := types.Bytes(bookUrl.ResourceChain()).AsBytes32()
+	_, err = dbtx.LoadChainAs(bookId[:], book)
+	require.NoError(t, err)
+
+	page := protocol.NewSigSpec()
+	page.ChainUrl = types.String(pageUrl.String())
+	page.SigSpecId = bookId
+	page.Keys = []*protocol.KeySpec{{
+		PublicKey: botKey.PubKey().Bytes(),
+		Limits: []*protocol.KeySpendLimit{{
+			TokenUrl:     protocol.AcmeUrl().String(),
+			MaxPerTx:     3 * acctesting.TokenMx,
+			MaxPerPeriod: 5 * acctesting.TokenMx,
+			Period:       time.Hour,
+		}},
+	}}
+	book.SigSpecs = append(book.SigSpecs, types.Bytes(pageUrl.ResourceChain()).AsBytes32())
+	require.NoError(t, acctesting.WriteStates(dbtx, book, page))
+	_, err = dbtx.Commit(1, time.Unix(0, 0))
+	require.NoError(t, err)
+
+	withdrawTx := func(key tmed25519.PrivKey, priority, amount uint64) *transactions.GenTransaction {
+		body := api.NewTokenTx("foo/tokens")
+		body.AddToAccount("bar/tokens", amount*acctesting.TokenMx)
+
+		tx, err := transactions.NewWith(&transactions.SignatureInfo{
+			URL:         "foo/tokens",
+			PriorityIdx: priority,
+		}, edSigner(key, 1), body)
+		require.NoError(t, err)
+		return tx
+	}
+
+	newState := func(tx *transactions.GenTransaction) *StateManager {
+		st, err := NewStateManager(db.Begin(), tx)
+		require.NoError(t, err)
+		st.BlockTime = time.Unix(0, 0)
+		return st
+	}
+
+	withdraw := func(key tmed25519.PrivKey, priority, amount uint64) error {
+		tx := withdrawTx(key, priority, amount)
+		return WithdrawTokens{}.Validate(newState(tx), tx)
+	}
+
+	t.Run("Per transaction", func(t *testing.T) {
+		require.Error(t, withdraw(botKey, 1, 4))
+		require.NoError(t, withdraw(botKey, 1, 3))
+	})
+
+	t.Run("Higher priority page", func(t *testing.T) {
+		require.NoError(t, withdraw(fooKey, 0, 4))
+	})
+
+	t.Run("Per period", func(t *testing.T) {
+		// Reuse the state manager so spends accumulate in the same window
+		tx := withdrawTx(botKey, 1, 3)
+		st := newState(tx)
+		require.NoError(t, WithdrawTokens{}.Validate(st, tx))
+		require.NoError(t, WithdrawTokens{}.Validate(st, withdrawTx(botKey, 1, 2)))
+		require.Error(t, WithdrawTokens{}.Validate(st, withdrawTx(botKey, 1, 1)))
+
+		// The window restarts once the period has elapsed
+		st.BlockTime = st.BlockTime.Add(time.Hour)
+		require.NoError(t, WithdrawTokens{}.Validate(st, withdrawTx(botKey, 1, 1)))
+
+		spec := new(protocol.SigSpec)
+		require.NoError(t, st.LoadUrlAs(pageUrl, spec))
+		require.Equal(t, uint64(1*acctesting.TokenMx), spec.Keys[0].Limits[0].WindowSpent)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/genesis"
 	"github.com/AccumulateNetwork/accumulate/internal/url"
@@ -22,6 +23,7 @@ type StateManager struct {
 	stores      map[[32]byte]*storeState
 	chains      map[[32]byte]state.Chain
 	writes      map[storage.Key][]byte
+	records     map[[32]byte][]byte
 	submissions []*submittedTx
 	storeCount  int
 	txHash      types.Bytes32
@@ -30,6 +32,9 @@ type StateManager struct {
 	Sponsor        state.Chain
 	SponsorUrl     *url.URL
 	SponsorChainId [32]byte
+
+	// BlockTime is the time of the block the transaction is executed in
+	BlockTime time.Time
 }
 
 type storeState struct {
@@ -37,6 +42,7 @@ type storeState struct {
 	order    int
 	chainId  *[32]byte
 	record   state.Chain
+	metadata string
 }
 
 // NewStateManager creates a new state manager and loads the transaction's
@@ -48,6 +54,7 @@ func NewStateManager(dbTx *state.DBTransaction, tx *transactions.GenTransaction)
 	m.chains = map[[32]byte]state.Chain{}
 	m.stores = map[[32]byte]*storeState{}
 	m.writes = map[storage.Key][]byte{}
+	m.records = map[[32]byte][]byte{}
 	m.txHash = types.Bytes(tx.TransactionHash()).AsBytes32()
 	m.txType = tx.TransactionType()
 
@@ -79,6 +86,21 @@ func NewStateManager(dbTx *state.DBTransaction, tx *transactions.GenTransaction)
 	return nil, err
 }
 
+// newBlockStateManager creates a state manager for changes the executor makes
+// outside of any transaction, such as at the end of a block. The changes are
+// recorded against the transaction that caused them.
+func newBlockStateManager(dbTx *state.DBTransaction, blockTime time.Time, cause [32]byte) *StateManager {
+	m := new(StateManager)
+	m.dbTx = dbTx
+	m.chains = map[[32]byte]state.Chain{}
+	m.stores = map[[32]byte]*storeState{}
+	m.writes = map[storage.Key][]byte{}
+	m.records = map[[32]byte][]byte{}
+	m.txHash = cause
+	m.BlockTime = blockTime
+	return m
+}
+
 type submittedTx struct {
 	url  *url.URL
 	body encoding.BinaryMarshaler
@@ -214,6 +236,23 @@ func (m *StateManager) Create(record ...state.Chain) {
 	}
 }
 
+// SetMetadata records the data account that holds the metadata of a record
+// queued by Create. Will panic if the record has not been queued by Create.
+func (m *StateManager) SetMetadata(record state.Chain, dataAccount *url.URL) {
+	u, err := record.Header().ParseUrl()
+	if err != nil {
+		panic(fmt.Errorf("attempted to set the metadata of an invalid chain: %v", err))
+	}
+
+	var chainId [32]byte
+	copy(chainId[:], u.ResourceChain())
+	s, ok := m.stores[chainId]
+	if !ok || !s.isCreate {
+		panic("Called StateManager.SetMetadata with a record that is not being created!")
+	}
+	s.metadata = dataAccount.String()
+}
+
 // Submit queues a synthetic transaction for submission.
 func (m *StateManager) Submit(url *url.URL, body encoding.BinaryMarshaler) {
 	if m.txType.IsSynthetic() {
@@ -227,6 +266,9 @@ func (m *StateManager) commit() error {
 	for k, v := range m.writes {
 		m.dbTx.Write(k, v)
 	}
+	for k, v := range m.records {
+		m.dbTx.WriteRecord(k, v)
+	}
 
 	// Create an ordered list of state stores
 	stores := make([]*storeState, 0, len(m.stores))
@@ -272,7 +314,7 @@ func (m *StateManager) commit() error {
 				create[idStr] = scc
 			}
 
-			scc.Chains = append(scc.Chains, protocol.ChainParams{Data: data})
+			scc.Chains = append(scc.Chains, protocol.ChainParams{Data: data, Metadata: store.metadata})
 
 		default:
 			// Update: update an existing record. Non-synthetic transactions are
@@ -294,6 +336,11 @@ func (m *StateManager) commit() error {
 				// Non-synthetic transactions are allowed to create transaction
 				// records
 
+			case store.record.Header().Type == types.ChainTypePendingSwap:
+				// Swap offers hold tokens taken from the maker by the same
+				// transaction, so they are created directly rather than by a
+				// synthetic transaction
+
 			case m.txType.IsSynthetic():
 				// Synthetic transactions are allowed to create records
 
@@ -336,6 +383,12 @@ func unmarshalRecord(obj *state.Object) (state.Chain, error) {
 		record = new(protocol.SigSpec)
 	case types.ChainTypeKeyBook:
 		record = new(protocol.SigSpecGroup)
+	case types.ChainTypeDataAccount:
+		record = new(protocol.DataAccount)
+	case types.ChainTypeTokenIssuer:
+		record = new(protocol.TokenIssuer)
+	case types.ChainTypePendingSwap:
+		record = new(protocol.PendingSwap)
 	default:
 		return nil, fmt.Errorf("unrecognized chain type %v", header.Type)
 	}
@@ -349,12 +402,12 @@ func unmarshalRecord(obj *state.Object) (state.Chain, error) {
 }
 
 func (s *StateManager) WriteIndex(index state.Index, chain []byte, key interface{}, value []byte) {
-	k := storage.ComputeKey(string(index), chain, key)
+	k := state.IndexKey(index, chain, key)
 	s.writes[k] = value
 }
 
 func (s *StateManager) GetIndex(index state.Index, chain []byte, key interface{}) ([]byte, error) {
-	k := storage.ComputeKey(string(index), chain, key)
+	k := state.IndexKey(index, chain, key)
 	w, ok := s.writes[k]
 	if ok {
 		return w, nil
@@ -362,6 +415,21 @@ func (s *StateManager) GetIndex(index state.Index, chain []byte, key interface{}
 	return s.dbTx.GetIndex(index, chain, key)
 }
 
+// WriteRecord queues a record that is committed to the BPT. See
+// state.DBTransaction.WriteRecord.
+func (s *StateManager) WriteRecord(key [32]byte, value []byte) {
+	s.records[key] = value
+}
+
+// ReadRecord returns a record, including one queued by WriteRecord.
+func (s *StateManager) ReadRecord(key [32]byte) ([]byte, error) {
+	v, ok := s.records[key]
+	if ok {
+		return v, nil
+	}
+	return s.dbTx.ReadRecord(key)
+}
+
 func (m *StateManager) AddDirectoryEntry(u *url.URL) error {
 	return AddDirectoryEntry(m, u)
 }
@@ -370,8 +438,11 @@ func AddDirectoryEntry(db interface {
 	WriteIndex(index state.Index, chain []byte, key interface{}, value []byte)
 	GetIndex(index state.Index, chain []byte, key interface{}) ([]byte, error)
 }, u *url.URL) error {
+	// Chains are listed in the directory of the ADI they belong to, which for a
+	// chain in a sub-ADI is not the root identity
+	parent, _ := u.Parent()
 	md := new(protocol.DirectoryIndexMetadata)
-	idc := u.IdentityChain()
+	idc := parent.ResourceChain()
 	b, err := db.GetIndex(state.DirectoryIndex, idc, "Metadata")
 	if err == nil {
 		err = md.UnmarshalBinary(b)
@@ -0,0 +1,119 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+)
+
+type SwapAccept struct{}
+
+func (SwapAccept) Type() types.TxType { return types.TxTypeSwapAccept }
+
+func (SwapAccept) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	body := new(protocol.SwapAccept)
+	err := tx.As(body)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+
+	makerUrl, err := url.Parse(body.Maker)
+	if err != nil {
+		return fmt.Errorf("invalid maker URL: %v", err)
+	}
+
+	swap, err := loadSwap(st, makerUrl, body.Offer)
+	if err != nil {
+		return err
+	}
+
+	if !st.BlockTime.Before(swap.Expires) {
+		return fmt.Errorf("offer %X expired at %v", body.Offer, swap.Expires)
+	}
+
+	if swap.Counterparty != "" {
+		counterparty, err := url.Parse(swap.Counterparty)
+		if err != nil {
+			return fmt.Errorf("invalid counterparty URL: %v", err)
+		}
+		if !counterparty.Equal(st.SponsorUrl) {
+			return fmt.Errorf("offer %X can only be accepted by %q", body.Offer, counterparty)
+		}
+	}
+
+	tokenUrl, err := url.Parse(swap.TokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+
+	requestTokenUrl, err := url.Parse(swap.RequestTokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid requested token URL: %v", err)
+	}
+
+	// The taker pays with the sponsor
+	taker, ok := st.Sponsor.(tokenChain)
+	if !ok {
+		return fmt.Errorf("invalid sponsor: want %v or %v, got %v", types.ChainTypeTokenAccount, types.ChainTypeLiteTokenAccount, st.Sponsor.Header().Type)
+	}
+
+	takerTokenUrl, err := taker.ParseTokenUrl()
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+	if !takerTokenUrl.Equal(requestTokenUrl) {
+		return fmt.Errorf("offer %X requests %s, %q holds %s", body.Offer, requestTokenUrl, st.SponsorUrl, takerTokenUrl)
+	}
+
+	makerRecipientUrl, makerRecipient, err := loadSwapAccount(st, swap.Recipient, requestTokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid maker recipient: %v", err)
+	}
+
+	takerRecipientUrl, takerRecipient, err := loadSwapAccount(st, body.Recipient, tokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	if !taker.CanDebitTokens(&swap.RequestAmount, st.BlockTime) {
+		return fmt.Errorf("insufficient balance")
+	}
+
+	err = checkSpendLimits(st, tx, requestTokenUrl, &swap.RequestAmount)
+	if err != nil {
+		return err
+	}
+
+	// Both transfers are committed together with the state manager, so either
+	// both parties are paid or neither is
+	if !taker.DebitTokens(&swap.RequestAmount, st.BlockTime) {
+		return fmt.Errorf("%q balance is insufficient", st.SponsorUrl)
+	}
+	if !makerRecipient.CreditTokens(&swap.RequestAmount) {
+		return fmt.Errorf("unable to credit %q", makerRecipientUrl)
+	}
+	if !takerRecipient.CreditTokens(&swap.Amount) {
+		return fmt.Errorf("unable to credit %q", takerRecipientUrl)
+	}
+
+	err = closeSwap(st, body.Offer, swap)
+	if err != nil {
+		return err
+	}
+	addTxReference(st, taker, st.SponsorUrl, tx)
+	addTxReference(st, makerRecipient, makerRecipientUrl, tx)
+	addTxReference(st, takerRecipient, takerRecipientUrl, tx)
+
+	err = recordTransfer(st, st.SponsorUrl, makerRecipientUrl.String(), requestTokenUrl, &swap.RequestAmount, false)
+	if err != nil {
+		return err
+	}
+	err = recordTransfer(st, makerRecipientUrl, st.SponsorUrl.String(), requestTokenUrl, &swap.RequestAmount, true)
+	if err != nil {
+		return err
+	}
+	return recordTransfer(st, takerRecipientUrl, makerUrl.String(), tokenUrl, &swap.Amount, true)
+}
@@ -0,0 +1,51 @@
+package chain
+
+import (
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+)
+
+type SwapCancel struct{}
+
+func (SwapCancel) Type() types.TxType { return types.TxTypeSwapCancel }
+
+// Validate releases the escrow of an offer back to the maker. The maker can
+// cancel an offer at any time until it is released at the end of the block it
+// expires in.
+func (SwapCancel) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	body := new(protocol.SwapCancel)
+	err := tx.As(body)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+
+	account, ok := st.Sponsor.(tokenChain)
+	if !ok {
+		return fmt.Errorf("invalid sponsor: want %v or %v, got %v", types.ChainTypeTokenAccount, types.ChainTypeLiteTokenAccount, st.Sponsor.Header().Type)
+	}
+
+	swap, err := loadSwap(st, st.SponsorUrl, body.Offer)
+	if err != nil {
+		return err
+	}
+
+	if !account.CreditTokens(&swap.Amount) {
+		return fmt.Errorf("unable to release escrow to %q", st.SponsorUrl)
+	}
+
+	tokenUrl, err := url.Parse(swap.TokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+
+	err = closeSwap(st, body.Offer, swap)
+	if err != nil {
+		return err
+	}
+	addTxReference(st, account, st.SponsorUrl, tx)
+	return recordTransfer(st, st.SponsorUrl, "", tokenUrl, &swap.Amount, true)
+}
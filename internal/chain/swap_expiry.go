@@ -0,0 +1,158 @@
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// swapBookKey is the key of the SwapBook record, which lists the open offers
+// so they can be released when they expire.
+var swapBookKey = storage.ComputeKey("SwapBook")
+
+type swapBookStore interface {
+	ReadRecord(key [32]byte) ([]byte, error)
+	WriteRecord(key [32]byte, value []byte)
+}
+
+func loadSwapBook(db swapBookStore) (*protocol.SwapBook, error) {
+	book := new(protocol.SwapBook)
+	b, err := db.ReadRecord(swapBookKey)
+	if err == nil {
+		err = book.UnmarshalBinary(b)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load the swap book: %v", err)
+	}
+	return book, nil
+}
+
+func writeSwapBook(db swapBookStore, book *protocol.SwapBook) error {
+	b, err := book.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal the swap book: %v", err)
+	}
+	db.WriteRecord(swapBookKey, b)
+	return nil
+}
+
+// addSwapExpiry adds an offer to the swap book. Offers are ordered by expiry,
+// then by the hash of the offer transaction, so every node releases them in
+// the same order.
+func addSwapExpiry(st *StateManager, maker *url.URL, offer [32]byte, expires time.Time) error {
+	book, err := loadSwapBook(st)
+	if err != nil {
+		return err
+	}
+
+	e := new(protocol.SwapExpiry)
+	e.Maker = maker.String()
+	e.Offer = offer
+	e.Expires = expires
+
+	i := sort.Search(len(book.Offers), func(i int) bool {
+		e := book.Offers[i]
+		if !e.Expires.Equal(expires) {
+			return e.Expires.After(expires)
+		}
+		return bytes.Compare(e.Offer[:], offer[:]) >= 0
+	})
+	book.Offers = append(book.Offers, nil)
+	copy(book.Offers[i+1:], book.Offers[i:])
+	book.Offers[i] = e
+	return writeSwapBook(st, book)
+}
+
+// removeSwapExpiry removes a closed offer from the swap book.
+func removeSwapExpiry(st *StateManager, offer [32]byte) error {
+	book, err := loadSwapBook(st)
+	if err != nil {
+		return err
+	}
+
+	for i, e := range book.Offers {
+		if e.Offer == offer {
+			book.Offers = append(book.Offers[:i], book.Offers[i+1:]...)
+			return writeSwapBook(st, book)
+		}
+	}
+	return fmt.Errorf("offer %X is not in the swap book", offer)
+}
+
+// releaseExpiredSwaps returns the escrow of every offer that has expired by
+// the time of the block to its maker. Each offer is released by a state
+// manager of its own, so an offer that cannot be released stays in the book
+// and does not hold back the others.
+func (m *Executor) releaseExpiredSwaps() error {
+	book, err := loadSwapBook(m.dbTx)
+	if err != nil {
+		return err
+	}
+
+	var open []*protocol.SwapExpiry
+	for i, e := range book.Offers {
+		if e.Expires.After(m.time) {
+			open = append(open, book.Offers[i:]...)
+			break
+		}
+
+		err = m.releaseSwap(e)
+		if err != nil {
+			m.logError("Failed to release an expired swap offer", "maker", e.Maker, "offer", fmt.Sprintf("%X", e.Offer), "error", err)
+			open = append(open, e)
+		}
+	}
+	if len(open) == len(book.Offers) {
+		return nil
+	}
+
+	book.Offers = open
+	return writeSwapBook(m.dbTx, book)
+}
+
+// releaseSwap returns the escrow of an expired offer to its maker and closes
+// the offer. The changes are recorded against the offer transaction.
+func (m *Executor) releaseSwap(e *protocol.SwapExpiry) error {
+	maker, err := url.Parse(e.Maker)
+	if err != nil {
+		return fmt.Errorf("invalid maker URL: %v", err)
+	}
+
+	st := newBlockStateManager(m.dbTx, m.time, e.Offer)
+	swap, err := loadSwap(st, maker, e.Offer)
+	if err != nil {
+		return err
+	}
+
+	record, err := st.LoadUrl(maker)
+	if err != nil {
+		return fmt.Errorf("failed to load %q: %v", maker, err)
+	}
+	account, ok := record.(tokenChain)
+	if !ok {
+		return fmt.Errorf("%q is not a token account", maker)
+	}
+
+	tokenUrl, err := url.Parse(swap.TokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+
+	if !account.CreditTokens(&swap.Amount) {
+		return fmt.Errorf("unable to release escrow to %q", maker)
+	}
+	swap.Closed = true
+	st.Update(account, swap)
+
+	err = recordTransfer(st, maker, "", tokenUrl, &swap.Amount, true)
+	if err != nil {
+		return err
+	}
+	return st.commit()
+}
@@ -0,0 +1,172 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+)
+
+type SwapOffer struct{}
+
+func (SwapOffer) Type() types.TxType { return types.TxTypeSwapOffer }
+
+// Validate moves the offered tokens into escrow. The escrow is released by
+// SwapAccept, which pays it to the taker, or returned to the maker by
+// SwapCancel or at the end of the block the offer expires in.
+func (SwapOffer) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	body := new(protocol.SwapOffer)
+	err := tx.As(body)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+
+	account, ok := st.Sponsor.(tokenChain)
+	if !ok {
+		return fmt.Errorf("invalid sponsor: want %v or %v, got %v", types.ChainTypeTokenAccount, types.ChainTypeLiteTokenAccount, st.Sponsor.Header().Type)
+	}
+
+	tokenUrl, err := account.ParseTokenUrl()
+	if err != nil {
+		return fmt.Errorf("invalid token URL: %v", err)
+	}
+
+	requestTokenUrl, err := url.Parse(body.RequestTokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid requested token URL: %v", err)
+	}
+
+	if body.Amount.Sign() <= 0 || body.RequestAmount.Sign() <= 0 {
+		return fmt.Errorf("swap amounts must be positive")
+	}
+
+	if !body.Expires.After(st.BlockTime) {
+		return fmt.Errorf("offer expires before it is made")
+	}
+
+	// The requested tokens are paid directly into the recipient when the offer
+	// is accepted, so it must be on this BVC
+	recipientUrl, _, err := loadSwapAccount(st, body.Recipient, requestTokenUrl)
+	if err != nil {
+		return fmt.Errorf("invalid recipient: %v", err)
+	}
+
+	offer := types.Bytes(tx.TransactionHash()).AsBytes32()
+	swap := protocol.NewPendingSwap()
+	swap.ChainUrl = types.String(swapUrl(st.SponsorUrl, offer).String())
+	swap.SigSpecId = st.Sponsor.Header().SigSpecId
+	swap.Maker = st.SponsorUrl.String()
+	swap.TokenUrl = tokenUrl.String()
+	swap.Amount.Set(&body.Amount)
+	swap.RequestTokenUrl = requestTokenUrl.String()
+	swap.RequestAmount.Set(&body.RequestAmount)
+	swap.Recipient = recipientUrl.String()
+	swap.Expires = body.Expires
+
+	if body.Counterparty != "" {
+		counterparty, err := url.Parse(body.Counterparty)
+		if err != nil {
+			return fmt.Errorf("invalid counterparty URL: %v", err)
+		}
+		swap.Counterparty = counterparty.String()
+	}
+
+	// Escrow the offered tokens
+	if !account.CanDebitTokens(&swap.Amount, st.BlockTime) {
+		return fmt.Errorf("insufficient balance")
+	}
+
+	err = checkSpendLimits(st, tx, tokenUrl, &swap.Amount)
+	if err != nil {
+		return err
+	}
+
+	if !account.DebitTokens(&swap.Amount, st.BlockTime) {
+		return fmt.Errorf("%q balance is insufficient", st.SponsorUrl)
+	}
+
+	st.Update(swap)
+	err = addSwapExpiry(st, st.SponsorUrl, offer, swap.Expires)
+	if err != nil {
+		return err
+	}
+	addTxReference(st, account, st.SponsorUrl, tx)
+
+	// The tokens are held in escrow, so there is no counterparty yet
+	return recordTransfer(st, st.SponsorUrl, "", tokenUrl, &swap.Amount, false)
+}
+
+// swapUrl returns the URL of the record of an offer, which is a chain of the
+// maker's account named after the hash of the offer transaction.
+func swapUrl(maker *url.URL, offer [32]byte) *url.URL {
+	return maker.JoinPath("swap", fmt.Sprintf("%x", offer))
+}
+
+// loadSwap loads an open swap offer. Offers that have been accepted,
+// cancelled, or released are reported as not found.
+func loadSwap(st *StateManager, maker *url.URL, offer [32]byte) (*protocol.PendingSwap, error) {
+	swap := new(protocol.PendingSwap)
+	err := st.LoadUrlAs(swapUrl(maker, offer), swap)
+	if err == nil && swap.Closed {
+		err = storage.ErrNotFound
+	}
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%q has no open offer %X", maker, offer)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load offer %X: %v", offer, err)
+	}
+	return swap, nil
+}
+
+// closeSwap closes an offer once its escrow has been released and removes it
+// from the swap book.
+func closeSwap(st *StateManager, offer [32]byte, swap *protocol.PendingSwap) error {
+	swap.Closed = true
+	st.Update(swap)
+	return removeSwapExpiry(st, offer)
+}
+
+// loadSwapAccount loads a token account taking part in a swap. Swaps are
+// restricted to a single BVC, so the account must be found locally.
+func loadSwapAccount(st *StateManager, s string, tokenUrl *url.URL) (*url.URL, tokenChain, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid URL: %v", err)
+	}
+
+	record, err := st.LoadUrl(u)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, nil, fmt.Errorf("%q does not exist on this BVC", u)
+	} else if err != nil {
+		return nil, nil, fmt.Errorf("failed to load %q: %v", u, err)
+	}
+
+	account, ok := record.(tokenChain)
+	if !ok {
+		return nil, nil, fmt.Errorf("%q is not a token account", u)
+	}
+
+	accountTokenUrl, err := account.ParseTokenUrl()
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid token URL: %v", err)
+	}
+	if !accountTokenUrl.Equal(tokenUrl) {
+		return nil, nil, fmt.Errorf("%q holds %s, not %s", u, accountTokenUrl, tokenUrl)
+	}
+
+	return u, account, nil
+}
+
+// addTxReference updates the account and adds the transaction to its
+// transaction history.
+func addTxReference(st *StateManager, account tokenChain, accountUrl *url.URL, tx *transactions.GenTransaction) {
+	txHash := types.Bytes(tx.TransactionHash()).AsBytes32()
+	refUrl := accountUrl.JoinPath(fmt.Sprint(account.NextTx()))
+	txr := state.NewTxReference(refUrl.String(), txHash[:])
+	st.Update(account, txr)
+}
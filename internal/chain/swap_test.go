@@ -0,0 +1,138 @@
+package chain_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/AccumulateNetwork/accumulate/internal/chain"
+	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSwap_AcceptAndCancel(t *testing.T) {
+	db := new(state.StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, true))
+
+	fooKey, barKey, bazKey := generateKey(), generateKey(), generateKey()
+	dbtx := db.Begin()
+	require.NoError(t, acctesting.CreateADI(dbtx, fooKey, "foo"))
+	require.NoError(t, acctesting.CreateADI(dbtx, barKey, "bar"))
+	require.NoError(t, acctesting.CreateADI(dbtx, bazKey, "baz"))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "foo/coins", "foo/coin", 6, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "foo/bucks", "bar/buck", 0, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "bar/bucks", "bar/buck", 20, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "bar/coins", "foo/coin", 0, false))
+	require.NoError(t, acctesting.CreateTokenAccount(dbtx, "baz/bucks", "bar/buck", 20, false))
+
+	// An open offer from foo, whose 4 coins are already in escrow
+	expires := time.Unix(100, 0)
+	offerId := [32]byte{1}
+	swap := protocol.NewPendingSwap()
+	swap.ChainUrl = types.String(fmt.Sprintf("acc://foo/coins/swap/%x", offerId))
+	swap.Maker = "acc://foo/coins"
+	swap.TokenUrl = "acc://foo/coin"
+	swap.Amount.SetInt64(4 * acctesting.TokenMx)
+	swap.RequestTokenUrl = "acc://bar/buck"
+	swap.RequestAmount.SetInt64(15 * acctesting.TokenMx)
+	swap.Recipient = "acc://foo/bucks"
+	swap.Counterparty = "acc://bar/bucks"
+	swap.Expires = expires
+	require.NoError(t, acctesting.WriteStates(dbtx, swap))
+
+	// The offer is listed in the swap book
+	book := new(protocol.SwapBook)
+	book.Offers = []*protocol.SwapExpiry{{Maker: "acc://foo/coins", Offer: offerId, Expires: expires}}
+	data, err := book.MarshalBinary()
+	require.NoError(t, err)
+	dbtx.WriteRecord(storage.ComputeKey("SwapBook"), data)
+	_, err = dbtx.Commit(1, time.Unix(0, 0))
+	require.NoError(t, err)
+
+	closed := func(st *StateManager) bool {
+		offer := new(protocol.PendingSwap)
+		require.NoError(t, st.LoadStringAs(string(swap.ChainUrl), offer))
+		return offer.Closed
+	}
+
+	newState := func(tx *transactions.GenTransaction, now time.Time) *StateManager {
+		st, err := NewStateManager(db.Begin(), tx)
+		require.NoError(t, err)
+		st.BlockTime = now
+		return st
+	}
+
+	accept := func(sponsor string, now time.Time) (*StateManager, error) {
+		body := new(protocol.SwapAccept)
+		body.Maker = "foo/coins"
+		body.Offer = offerId
+		body.Recipient = "bar/coins"
+		tx, err := transactions.New(sponsor, edSigner(generateKey(), 1), body)
+		require.NoError(t, err)
+		st := newState(tx, now)
+		return st, SwapAccept{}.Validate(st, tx)
+	}
+
+	t.Run("Wrong counterparty", func(t *testing.T) {
+		_, err := accept("baz/bucks", time.Unix(50, 0))
+		require.EqualError(t, err, `offer 0100000000000000000000000000000000000000000000000000000000000000 can only be accepted by "acc://bar/bucks"`)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		_, err := accept("bar/bucks", expires)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expired")
+	})
+
+	t.Run("Accept", func(t *testing.T) {
+		st, err := accept("bar/bucks", time.Unix(50, 0))
+		require.NoError(t, err)
+
+		balance := func(s string) int64 {
+			account := new(state.TokenAccount)
+			require.NoError(t, st.LoadStringAs(s, account))
+			return account.Balance.Int64()
+		}
+		require.Equal(t, int64(15*acctesting.TokenMx), balance("foo/bucks"))
+		require.Equal(t, int64(5*acctesting.TokenMx), balance("bar/bucks"))
+		require.Equal(t, int64(4*acctesting.TokenMx), balance("bar/coins"))
+
+		// The offer is closed
+		require.True(t, closed(st))
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		body := new(protocol.SwapCancel)
+		body.Offer = offerId
+		tx, err := transactions.New("foo/coins", edSigner(fooKey, 1), body)
+		require.NoError(t, err)
+		st := newState(tx, time.Unix(50, 0))
+
+		// The offer is open and its tokens are in escrow
+		account := new(state.TokenAccount)
+		require.NoError(t, st.LoadStringAs("foo/coins", account))
+		require.Equal(t, int64(6*acctesting.TokenMx), account.Balance.Int64())
+		require.False(t, closed(st))
+
+		require.NoError(t, SwapCancel{}.Validate(st, tx))
+
+		// The escrow is released back to the maker and the offer is closed
+		require.NoError(t, st.LoadStringAs("foo/coins", account))
+		require.Equal(t, int64(10*acctesting.TokenMx), account.Balance.Int64())
+		require.True(t, closed(st))
+	})
+
+	t.Run("Unknown offer", func(t *testing.T) {
+		body := new(protocol.SwapCancel)
+		body.Offer = [32]byte{2}
+		tx, err := transactions.New("foo/coins", edSigner(fooKey, 1), body)
+		require.NoError(t, err)
+		err = SwapCancel{}.Validate(newState(tx, time.Unix(0, 0)), tx)
+		require.EqualError(t, err, `"acc://foo/coins" has no open offer 0200000000000000000000000000000000000000000000000000000000000000`)
+	})
+}
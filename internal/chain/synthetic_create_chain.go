@@ -3,7 +3,6 @@ package chain
 import (
 	"errors"
 	"fmt"
-	"strings"
 
 	"github.com/AccumulateNetwork/accumulate/internal/url"
 	"github.com/AccumulateNetwork/accumulate/protocol"
@@ -43,7 +42,7 @@ func (SyntheticCreateChain) Validate(st *StateManager, tx *transactions.GenTrans
 			return fmt.Errorf("invalid chain URL: %v", err)
 		}
 
-		_, err = st.LoadUrl(u)
+		existing, err := st.LoadUrl(u)
 		switch {
 		case err != nil && !errors.Is(err, storage.ErrNotFound):
 			return fmt.Errorf("error fetching %q: %v", u, err)
@@ -55,6 +54,24 @@ func (SyntheticCreateChain) Validate(st *StateManager, tx *transactions.GenTrans
 
 		urls[i] = u
 		st.Update(record)
+
+		// Index the keys of a new or updated key page
+		if page, ok := record.(*protocol.SigSpec); ok {
+			var before [][]byte
+			if old, ok := existing.(*protocol.SigSpec); ok {
+				before = publicKeys(old)
+			}
+			err = updateKeyIndex(st, page, before)
+			if err != nil {
+				return err
+			}
+		}
+
+		// The metadata account was validated by the transaction that created
+		// the chain
+		if cc.Metadata != "" {
+			st.WriteIndex(state.MetadataIndex, u.ResourceChain(), "Url", []byte(cc.Metadata))
+		}
 	}
 
 	// Verify everything is sane
@@ -65,29 +82,27 @@ func (SyntheticCreateChain) Validate(st *StateManager, tx *transactions.GenTrans
 			return fmt.Errorf("internal error: failed to fetch pending record")
 		}
 
-		// Check the identity
-		switch record.Header().Type {
-		case types.ChainTypeIdentity:
-			// An ADI must be its own identity
-			if !u.Identity().Equal(u) {
-				return fmt.Errorf("ADI is not its own identity")
-			}
-		default:
-			// Anything else must be a sub-path
-			if u.Identity().Equal(u) {
-				return fmt.Errorf("chain type %v cannot be its own identity", record.Header().Type)
-			}
+		// Check the identity. Anything other than a root ADI, including a
+		// sub-ADI, must be a direct child of an ADI.
+		parent, hasParent := u.Parent()
+		switch {
+		case record.Header().Type == types.ChainTypeIdentity && !hasParent:
+			// A root ADI is its own identity
 
-			if u.Path != "" && strings.Contains(u.Path[1:], "/") {
-				return fmt.Errorf("chain type %v cannot contain more than one slash in its URL", record.Header().Type)
-			}
+		case !hasParent:
+			return fmt.Errorf("chain type %v cannot be its own identity", record.Header().Type)
 
-			// Make sure the ADI actually exists
-			_, err = st.LoadUrl(u.Identity())
+		default:
+			// Make sure the parent ADI actually exists
+			adi, err := st.LoadUrl(parent)
 			if errors.Is(err, storage.ErrNotFound) {
 				return fmt.Errorf("missing identity for %s", u.String())
 			} else if err != nil {
-				return fmt.Errorf("error fetching %q: %v", u.String(), err)
+				return fmt.Errorf("error fetching %q: %v", parent.String(), err)
+			}
+
+			if adi.Header().Type != types.ChainTypeIdentity {
+				return fmt.Errorf("chain type %v cannot be nested under %v", record.Header().Type, adi.Header().Type)
 			}
 
 			// Update the ADI's directory index
@@ -1,12 +1,15 @@
 package chain
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/AccumulateNetwork/accumulate/internal/genesis"
 	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 type SyntheticGenesis struct{}
@@ -24,6 +27,17 @@ func (SyntheticGenesis) Validate(st *StateManager, tx *transactions.GenTransacti
 	for _, record := range genesis.BootstrapStates() {
 		st.Update(record)
 	}
+
+	// The app state of the genesis document is written to the database as is.
+	// The major block schedule must be part of the BPT, so it is written again
+	// as a record.
+	b, err := st.ReadRecord(state.MajorBlockScheduleKey())
+	switch {
+	case err == nil:
+		st.WriteRecord(state.MajorBlockScheduleKey(), b)
+	case !errors.Is(err, storage.ErrNotFound):
+		return fmt.Errorf("failed to load the major block schedule: %v", err)
+	}
 	return nil
 }
 
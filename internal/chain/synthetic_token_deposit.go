@@ -68,6 +68,11 @@ func (SyntheticTokenDeposit) Validate(st *StateManager, tx *transactions.GenTran
 	}
 	st.Update(account)
 
+	err = recordTransfer(st, accountUrl, string(body.FromUrl), tokenUrl, &body.DepositAmount.Int, true)
+	if err != nil {
+		return err
+	}
+
 	//create a transaction reference chain acme-xxxxx/0, 1, 2, ... n.
 	//This will reference the txid to keep the history
 	txHash := types.Bytes(tx.TransactionHash()).AsBytes32()
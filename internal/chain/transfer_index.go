@@ -0,0 +1,68 @@
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/AccumulateNetwork/accumulate/internal/url"
+	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types/state"
+)
+
+// recordTransfer records a change of the balance of a token account. The
+// record is added to the transfer index of the account and to that of every
+// identity above it, so the history of an ADI includes the transfers of all of
+// its accounts, including those of its sub-ADIs.
+func recordTransfer(st *StateManager, account *url.URL, counterparty string, token *url.URL, amount *big.Int, incoming bool) error {
+	transfer := new(protocol.TransferRecord)
+	transfer.Txid = st.txHash
+	transfer.TxType = st.txType.String()
+	transfer.Account = account.String()
+	transfer.Counterparty = counterparty
+	transfer.Token = token.String()
+	transfer.Amount.Set(amount)
+	transfer.Incoming = incoming
+	transfer.Time = st.BlockTime
+
+	data, err := transfer.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal transfer: %v", err)
+	}
+
+	err = addTransferIndexEntry(st, account, data)
+	if err != nil {
+		return err
+	}
+	for id, ok := account.Parent(); ok; id, ok = id.Parent() {
+		err = addTransferIndexEntry(st, id, data)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addTransferIndexEntry(st *StateManager, u *url.URL, data []byte) error {
+	md := new(protocol.TransferIndexMetadata)
+	chain := u.ResourceChain()
+	b, err := st.GetIndex(state.TransferIndex, chain, "Metadata")
+	if err == nil {
+		err = md.UnmarshalBinary(b)
+	}
+	if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load the transfer index of %q: %v", u, err)
+	}
+
+	c := md.Count
+	md.Count++
+	b, err = md.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %v", err)
+	}
+
+	st.WriteIndex(state.TransferIndex, chain, "Metadata", b)
+	st.WriteIndex(state.TransferIndex, chain, c, data)
+	return nil
+}
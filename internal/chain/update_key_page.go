@@ -27,6 +27,8 @@ func (UpdateKeyPage) Validate(st *StateManager, tx *transactions.GenTransaction)
 		return fmt.Errorf("invalid sponsor: want chain type %v, got %v", types.ChainTypeKeyPage, st.Sponsor.Header().Type)
 	}
 
+	before := publicKeys(page)
+
 	// We're changing the height of the key page, so reset all the nonces
 	for _, key := range page.Keys {
 		key.Nonce = 0
@@ -104,12 +106,25 @@ func (UpdateKeyPage) Validate(st *StateManager, tx *transactions.GenTransaction)
 			return fmt.Errorf("cannot delete last key of the highest priority page of a key book")
 		}
 
+	case protocol.SetKeyLimits:
+		if len(body.Key) == 0 {
+			return fmt.Errorf("trying to set the limits of a key but you didn't give me an existing key")
+		}
+		if oldKey == nil {
+			return fmt.Errorf("no matching key found")
+		}
+
+		oldKey.Limits, err = newSpendLimits(body.Limits)
+		if err != nil {
+			return err
+		}
+
 	default:
 		return fmt.Errorf("invalid operation: %v", body.Operation)
 	}
 
 	st.Update(page)
-	return nil
+	return updateKeyIndex(st, page, before)
 }
 
 func (UpdateKeyPage) CheckTx(st *StateManager, tx *transactions.GenTransaction) error {
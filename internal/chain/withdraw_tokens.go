@@ -59,10 +59,15 @@ func (WithdrawTokens) Validate(st *StateManager, tx *transactions.GenTransaction
 		total.Add(total.AsBigInt(), new(big.Int).SetUint64(to.Amount))
 	}
 
-	if !account.CanDebitTokens(&total.Int) {
+	if !account.CanDebitTokens(&total.Int, st.BlockTime) {
 		return fmt.Errorf("insufficient balance")
 	}
 
+	err = checkSpendLimits(st, tx, tokenUrl, &total.Int)
+	if err != nil {
+		return err
+	}
+
 	token := types.String(tokenUrl.String())
 	txid := types.Bytes(tx.TransactionHash())
 	for i, u := range recipients {
@@ -77,11 +82,18 @@ func (WithdrawTokens) Validate(st *StateManager, tx *transactions.GenTransaction
 		st.Submit(u, deposit)
 	}
 
-	if !account.DebitTokens(&total.Int) {
+	if !account.DebitTokens(&total.Int, st.BlockTime) {
 		return fmt.Errorf("%q balance is insufficient", st.SponsorUrl)
 	}
 	st.Update(account)
 
+	for i, u := range recipients {
+		err = recordTransfer(st, st.SponsorUrl, u.String(), tokenUrl, new(big.Int).SetUint64(body.To[i].Amount), false)
+		if err != nil {
+			return err
+		}
+	}
+
 	txHash := txid.AsBytes32()
 	//create a transaction reference chain acme-xxxxx/0, 1, 2, ... n.
 	//This will reference the txid to keep the history
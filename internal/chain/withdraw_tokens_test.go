@@ -10,6 +10,7 @@ import (
 	acctesting "github.com/AccumulateNetwork/accumulate/internal/testing"
 	testing2 "github.com/AccumulateNetwork/accumulate/internal/testing"
 	"github.com/AccumulateNetwork/accumulate/protocol"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/types"
 	anon "github.com/AccumulateNetwork/accumulate/types/anonaddress"
 	"github.com/AccumulateNetwork/accumulate/types/state"
@@ -20,7 +21,7 @@ import (
 func TestAnonTokenTransactions(t *testing.T) {
 	tokenUrl := types.String(protocol.AcmeUrl().String())
 	db := &state.StateDB{}
-	err := db.Open("mem", true, true)
+	err := db.Open("memory", "", storage.Options{}, true)
 	require.NoError(t, err)
 
 	_, privKey, _ := ed25519.GenerateKey(nil)
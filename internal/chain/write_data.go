@@ -1,7 +1,6 @@
 package chain
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/AccumulateNetwork/accumulate/protocol"
@@ -14,11 +13,16 @@ type WriteData struct{}
 func (WriteData) Type() types.TxType { return types.TxTypeWriteData }
 
 func (WriteData) Validate(st *StateManager, tx *transactions.GenTransaction) error {
+	account, ok := st.Sponsor.(*protocol.DataAccount)
+	if !ok {
+		return fmt.Errorf("invalid sponsor: want chain type %v, got %v", types.ChainTypeDataAccount, st.Sponsor.Header().Type)
+	}
+
 	body := new(protocol.WriteData)
 	err := tx.As(body)
 	if err != nil {
 		return fmt.Errorf("invalid payload: %v", err)
 	}
 
-	return errors.New("not implemented") // TODO
+	return writeDataEntry(st, st.SponsorUrl, account, body.Data)
 }
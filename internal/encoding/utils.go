@@ -145,7 +145,7 @@ func StringUnmarshalBinary(b []byte) (string, error) {
 
 func SplitDuration(d time.Duration) (sec, ns uint64) {
 	sec = uint64(d.Seconds())
-	ns = uint64((d - d.Round(time.Second)).Nanoseconds())
+	ns = uint64((d - d.Truncate(time.Second)).Nanoseconds())
 	return sec, ns
 }
 
@@ -164,7 +164,7 @@ func DurationUnmarshalBinary(b []byte) (time.Duration, error) {
 	if err != nil {
 		return 0, fmt.Errorf("error decoding seconds: %w", err)
 	}
-	ns, err := UvarintUnmarshalBinary(b)
+	ns, err := UvarintUnmarshalBinary(b[UvarintBinarySize(sec):])
 	if err != nil {
 		return 0, fmt.Errorf("error decoding nanoseconds: %w", err)
 	}
@@ -11,6 +11,7 @@ import (
 	protocol "github.com/AccumulateNetwork/accumulate/protocol"
 	query "github.com/AccumulateNetwork/accumulate/types/api/query"
 	transactions "github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	state "github.com/AccumulateNetwork/accumulate/types/state"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -173,6 +174,20 @@ func (mr *MockStateMockRecorder) BlockIndex() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockIndex", reflect.TypeOf((*MockState)(nil).BlockIndex))
 }
 
+// RestoreSnapshot mocks base method.
+func (m *MockState) RestoreSnapshot(data, appHash []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreSnapshot", data, appHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreSnapshot indicates an expected call of RestoreSnapshot.
+func (mr *MockStateMockRecorder) RestoreSnapshot(data, appHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreSnapshot", reflect.TypeOf((*MockState)(nil).RestoreSnapshot), data, appHash)
+}
+
 // RootHash mocks base method.
 func (m *MockState) RootHash() []byte {
 	m.ctrl.T.Helper()
@@ -187,6 +202,21 @@ func (mr *MockStateMockRecorder) RootHash() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RootHash", reflect.TypeOf((*MockState)(nil).RootHash))
 }
 
+// SnapshotView mocks base method.
+func (m *MockState) SnapshotView() (*state.SnapshotView, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnapshotView")
+	ret0, _ := ret[0].(*state.SnapshotView)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SnapshotView indicates an expected call of SnapshotView.
+func (mr *MockStateMockRecorder) SnapshotView() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnapshotView", reflect.TypeOf((*MockState)(nil).SnapshotView))
+}
+
 // SubnetID mocks base method.
 func (m *MockState) SubnetID() (string, error) {
 	m.ctrl.T.Helper()
@@ -39,18 +39,18 @@ func (m *MockQuerier) EXPECT() *MockQuerierMockRecorder {
 }
 
 // QueryChain mocks base method.
-func (m *MockQuerier) QueryChain(id []byte) (*api.QueryResponse, error) {
+func (m *MockQuerier) QueryChain(id []byte, height int64) (*api.QueryResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryChain", id)
+	ret := m.ctrl.Call(m, "QueryChain", id, height)
 	ret0, _ := ret[0].(*api.QueryResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryChain indicates an expected call of QueryChain.
-func (mr *MockQuerierMockRecorder) QueryChain(id interface{}) *gomock.Call {
+func (mr *MockQuerierMockRecorder) QueryChain(id, height interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryChain", reflect.TypeOf((*MockQuerier)(nil).QueryChain), id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryChain", reflect.TypeOf((*MockQuerier)(nil).QueryChain), id, height)
 }
 
 // QueryDirectory mocks base method.
@@ -68,6 +68,51 @@ func (mr *MockQuerierMockRecorder) QueryDirectory(url interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryDirectory", reflect.TypeOf((*MockQuerier)(nil).QueryDirectory), url)
 }
 
+// QueryKeyIndex mocks base method.
+func (m *MockQuerier) QueryKeyIndex(key []byte) (*api.QueryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryKeyIndex", key)
+	ret0, _ := ret[0].(*api.QueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryKeyIndex indicates an expected call of QueryKeyIndex.
+func (mr *MockQuerierMockRecorder) QueryKeyIndex(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryKeyIndex", reflect.TypeOf((*MockQuerier)(nil).QueryKeyIndex), key)
+}
+
+// QueryReceipt mocks base method.
+func (m *MockQuerier) QueryReceipt(url string, hash, anchor []byte) (*api.QueryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryReceipt", url, hash, anchor)
+	ret0, _ := ret[0].(*api.QueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryReceipt indicates an expected call of QueryReceipt.
+func (mr *MockQuerierMockRecorder) QueryReceipt(url, hash, anchor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryReceipt", reflect.TypeOf((*MockQuerier)(nil).QueryReceipt), url, hash, anchor)
+}
+
+// QueryTransferHistory mocks base method.
+func (m *MockQuerier) QueryTransferHistory(url string, start, count int64) (*api.QueryMultiResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueryTransferHistory", url, start, count)
+	ret0, _ := ret[0].(*api.QueryMultiResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueryTransferHistory indicates an expected call of QueryTransferHistory.
+func (mr *MockQuerierMockRecorder) QueryTransferHistory(url, start, count interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryTransferHistory", reflect.TypeOf((*MockQuerier)(nil).QueryTransferHistory), url, start, count)
+}
+
 // QueryTx mocks base method.
 func (m *MockQuerier) QueryTx(id []byte) (*api.QueryResponse, error) {
 	m.ctrl.T.Helper()
@@ -99,18 +144,18 @@ func (mr *MockQuerierMockRecorder) QueryTxHistory(url, start, count interface{})
 }
 
 // QueryUrl mocks base method.
-func (m *MockQuerier) QueryUrl(url string) (*api.QueryResponse, error) {
+func (m *MockQuerier) QueryUrl(url string, height int64) (*api.QueryResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "QueryUrl", url)
+	ret := m.ctrl.Call(m, "QueryUrl", url, height)
 	ret0, _ := ret[0].(*api.QueryResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // QueryUrl indicates an expected call of QueryUrl.
-func (mr *MockQuerierMockRecorder) QueryUrl(url interface{}) *gomock.Call {
+func (mr *MockQuerierMockRecorder) QueryUrl(url, height interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUrl", reflect.TypeOf((*MockQuerier)(nil).QueryUrl), url)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueryUrl", reflect.TypeOf((*MockQuerier)(nil).QueryUrl), url, height)
 }
 
 // MockABCIQueryClient is a mock of ABCIQueryClient interface.
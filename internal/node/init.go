@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"time"
 
 	cfg "github.com/AccumulateNetwork/accumulate/config"
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/smt/storage/memory"
+	"github.com/AccumulateNetwork/accumulate/types/state"
 	tmcfg "github.com/tendermint/tendermint/config"
 	tmlog "github.com/tendermint/tendermint/libs/log"
 	tmos "github.com/tendermint/tendermint/libs/os"
@@ -38,6 +40,21 @@ type InitOptions struct {
 	Config     []*cfg.Config
 	RemoteIP   []string
 	ListenIP   []string
+
+	// MajorBlockInterval and MajorBlockPeriod are the major block schedule of
+	// the network, see state.MajorBlockSchedule. They are written to the
+	// genesis document, so they are ignored if GenesisDoc is set.
+	MajorBlockInterval int64
+	MajorBlockPeriod   time.Duration
+}
+
+// majorBlockSchedule returns the genesis record of the major block schedule.
+func majorBlockSchedule(opts InitOptions) []byte {
+	schedule := new(state.MajorBlockSchedule)
+	schedule.Blocks = opts.MajorBlockInterval
+	schedule.Period = opts.MajorBlockPeriod
+	b, _ := schedule.MarshalBinary()
+	return b
 }
 
 // Init creates the initial configuration for a set of nodes, using
@@ -109,6 +126,7 @@ func Init(opts InitOptions) (err error) {
 		db := new(memory.DB)
 		_ = db.InitDB("")
 		_ = db.Put(storage.ComputeKey("SubnetID"), []byte(opts.SubnetID))
+		_ = db.Put(state.RecordStorageKey(state.MajorBlockScheduleKey()), majorBlockSchedule(opts))
 		state, _ := db.MarshalBinary()
 		state, err := json.Marshal(state)
 		if err != nil {
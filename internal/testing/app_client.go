@@ -194,6 +194,7 @@ func (c *ABCIApplicationClient) execute(interval time.Duration) {
 
 		begin := abci.RequestBeginBlock{}
 		begin.Header.Height = c.nextHeight()
+		begin.Header.Time = time.Now()
 		c.app.BeginBlock(begin)
 
 		// Process the queue
@@ -14,6 +14,7 @@ import (
 	"github.com/AccumulateNetwork/accumulate/internal/node"
 	"github.com/AccumulateNetwork/accumulate/internal/relay"
 	"github.com/AccumulateNetwork/accumulate/networks"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 	"github.com/rs/zerolog"
 	tmcfg "github.com/tendermint/tendermint/config"
@@ -51,6 +52,7 @@ func NodeInitOptsForNetwork(network *networks.Subnet) (node.InitOptions, error)
 		config[i].LogLevel = "error"
 		config[i].Consensus.CreateEmptyBlocks = false
 		config[i].Accumulate.Type = network.Type
+		config[i].Accumulate.Storage = cfg.DefaultStorage()
 		config[i].Accumulate.Networks = []string{fmt.Sprintf("tcp://%s:%d", remoteIP[0], network.Port)}
 	}
 
@@ -70,13 +72,29 @@ func NewBVCNode(dir string, memDB bool, relayTo []string, newZL func(string) zer
 		return nil, nil, nil, fmt.Errorf("failed to load config: %v", err)
 	}
 
-	dbPath := filepath.Join(cfg.RootDir, "valacc.db")
+	store := cfg.Accumulate.Storage
+	if memDB {
+		store.Type = "memory"
+	}
+	dbPath := store.Path
+	if !filepath.IsAbs(dbPath) {
+		dbPath = filepath.Join(cfg.RootDir, dbPath)
+	}
+
 	//ToDo: FIX:::  bvcId := sha256.Sum256([]byte(cfg.Instrumentation.Namespace))
 	sdb := new(state.StateDB)
-	err = sdb.Open(dbPath, memDB, true)
+	err = sdb.Open(store.Type, dbPath, storage.Options{
+		CacheSize:        store.CacheSize,
+		SyncWrites:       store.SyncWrites,
+		ValueLogFileSize: store.ValueLogFileSize,
+	}, true)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to open database %s: %v", dbPath, err)
 	}
+	sdb.SetPendingRetention(cfg.Accumulate.PendingRetention)
+	sdb.SetUndoRetention(cfg.Accumulate.UndoRetention)
+	sdb.SetHistoryRetention(cfg.Accumulate.HistoryRetention)
+	sdb.SetBPTCacheSize(store.BPTCacheSize)
 	cleanup(func() {
 		_ = sdb.GetDB().Close()
 	})
@@ -104,6 +122,18 @@ func NewBVCNode(dir string, memDB bool, relayTo []string, newZL func(string) zer
 		return nil, nil, nil, fmt.Errorf("failed to create chain manager: %v", err)
 	}
 
+	if anchoring := cfg.Accumulate.Anchoring; anchoring.Publisher != "" {
+		target := anchoring.Target
+		if anchoring.Publisher == "file" && !filepath.IsAbs(target) {
+			target = filepath.Join(cfg.RootDir, target)
+		}
+		publisher, err := chain.NewAnchorPublisher(anchoring.Publisher, target)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create anchor publisher: %v", err)
+		}
+		mgr.EnableAnchoring(publisher, anchoring.Account, anchoring.EveryBlock)
+	}
+
 	var zl zerolog.Logger
 	if newZL == nil {
 		w, err := logging.NewConsoleWriter(cfg.LogFormat)
@@ -122,11 +152,15 @@ func NewBVCNode(dir string, memDB bool, relayTo []string, newZL func(string) zer
 	}
 
 	sdb.SetLogger(logger)
+	mgr.SetLogger(logger)
+	cleanup(mgr.StopAnchoring)
 
 	app, err := abci.NewAccumulator(sdb, pv.Key.PubKey.Address(), mgr, logger)
 	if err != nil {
 		return nil, nil, nil, fmt.Errorf("failed to create ABCI app: %v", err)
 	}
+	app.EnableSnapshots(filepath.Join(cfg.RootDir, "snapshots"), cfg.Accumulate.SnapshotInterval, cfg.Accumulate.SnapshotKeep)
+	cleanup(app.WaitForSnapshots)
 
 	node, err := node.New(cfg, app, logger)
 	if err != nil {
@@ -134,6 +168,7 @@ func NewBVCNode(dir string, memDB bool, relayTo []string, newZL func(string) zer
 	}
 	go func() {
 		<-node.Quit()
+		app.WaitForSnapshots()
 		sdb.GetDB().Close()
 	}()
 	cleanup(func() {
@@ -160,6 +160,26 @@ func (u *URL) Identity() *URL {
 	return &v
 }
 
+// Parent returns a copy of the URL with the last path element removed. Parent
+// returns false if the URL does not have a path, in which case the copy is
+// identical to the URL.
+func (u *URL) Parent() (*URL, bool) {
+	v := *u
+	p := strings.TrimSuffix(u.Path, "/")
+	if p == "" {
+		v.Path = ""
+		return &v, false
+	}
+
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		v.Path = ""
+	} else {
+		v.Path = p[:i]
+	}
+	return &v, true
+}
+
 // IdentityChain constructs a chain identifier from the lower case hostname. The
 // port is not included.
 //
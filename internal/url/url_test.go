@@ -102,3 +102,28 @@ func TestURL_ResourceChain(t *testing.T) {
 		})
 	}
 }
+
+func TestURL_Parent(t *testing.T) {
+	cases := []struct {
+		url    string
+		expect string
+		ok     bool
+	}{
+		{"foo", "acc://foo", false},
+		{"foo/", "acc://foo", false},
+		{"foo/bar", "acc://foo", true},
+		{"foo/bar/", "acc://foo", true},
+		{"foo/bar/baz", "acc://foo/bar", true},
+	}
+
+	for _, c := range cases {
+		t.Run(c.url, func(t *testing.T) {
+			u, err := Parse(c.url)
+			require.NoError(t, err)
+			p, ok := u.Parent()
+			require.Equal(t, c.ok, ok)
+			require.Equal(t, c.expect, p.String())
+			require.Equal(t, u.Routing(), p.Routing())
+		})
+	}
+}
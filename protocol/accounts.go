@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"math/big"
+	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/url"
 )
@@ -32,12 +33,14 @@ func (acct *AnonTokenAccount) CreditTokens(amount *big.Int) bool {
 	return true
 }
 
-func (acct *AnonTokenAccount) CanDebitTokens(amount *big.Int) bool {
+// CanDebitTokens returns true if the balance covers the amount. Lite accounts
+// cannot have a vesting schedule, so the time is ignored.
+func (acct *AnonTokenAccount) CanDebitTokens(amount *big.Int, _ time.Time) bool {
 	return amount != nil && acct.Balance.Cmp(amount) >= 0
 }
 
-func (acct *AnonTokenAccount) DebitTokens(amount *big.Int) bool {
-	if !acct.CanDebitTokens(amount) {
+func (acct *AnonTokenAccount) DebitTokens(amount *big.Int, now time.Time) bool {
+	if !acct.CanDebitTokens(amount, now) {
 		return false
 	}
 
@@ -46,6 +46,14 @@ const (
 	CodeInvalidTxnError ErrorCode = 22
 	//CodeAddTxnError is returned when adding txn to state db fails
 	CodeAddTxnError ErrorCode = 23
+	//CodeMetadataURL is returned when the metadata of a chain cannot be resolved
+	CodeMetadataURL ErrorCode = 24
+	//CodeKeyIndex is returned when the key index of a public key cannot be resolved
+	CodeKeyIndex ErrorCode = 25
+	//CodeTransferHistory is returned when the transfer history query fails
+	CodeTransferHistory ErrorCode = 26
+	//CodeReceipt is returned when a receipt cannot be built
+	CodeReceipt ErrorCode = 27
 )
 
 type Error struct {
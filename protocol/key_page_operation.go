@@ -12,6 +12,7 @@ const (
 	UpdateKey KeyPageOperation = iota + 1
 	AddKey
 	RemoveKey
+	SetKeyLimits
 )
 
 func KeyPageOperationByName(s string) KeyPageOperation {
@@ -22,6 +23,8 @@ func KeyPageOperationByName(s string) KeyPageOperation {
 		return AddKey
 	case "remove":
 		return RemoveKey
+	case "limit":
+		return SetKeyLimits
 	default:
 		return KeyPageOperation(0)
 	}
@@ -35,6 +38,8 @@ func (op KeyPageOperation) String() string {
 		return "add"
 	case RemoveKey:
 		return "remove"
+	case SetKeyLimits:
+		return "limit"
 	default:
 		return fmt.Sprintf("KeyPageOperation:%d", op)
 	}
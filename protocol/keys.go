@@ -2,6 +2,8 @@ package protocol
 
 import (
 	"bytes"
+	"math/big"
+	"time"
 )
 
 func (ms *SigSpec) FindKey(pubKey []byte) *KeySpec {
@@ -17,3 +19,35 @@ func (ms *SigSpec) FindKey(pubKey []byte) *KeySpec {
 
 	return nil
 }
+
+// Spend checks whether the amount can be spent at the given time without
+// exceeding the limit. If it can, Spend adds the amount to the current window.
+// The window restarts once Period has elapsed since WindowStart; if Period is
+// zero, the window never restarts. Spend returns false if the limit would be
+// exceeded, and whether the limit's window was modified.
+func (l *KeySpendLimit) Spend(amount *big.Int, now time.Time) (ok, changed bool) {
+	if !amount.IsUint64() {
+		return false, false
+	}
+	v := amount.Uint64()
+
+	if l.MaxPerTx > 0 && v > l.MaxPerTx {
+		return false, false
+	}
+
+	if l.MaxPerPeriod == 0 {
+		return true, false
+	}
+
+	start, spent := l.WindowStart, l.WindowSpent
+	if l.Period > 0 && !now.Before(start.Add(l.Period)) {
+		start, spent = now, 0
+	}
+
+	if spent+v < spent || spent+v > l.MaxPerPeriod {
+		return false, false
+	}
+
+	l.WindowStart, l.WindowSpent = start, spent+v
+	return true, true
+}
@@ -0,0 +1,48 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	neturl "net/url"
+)
+
+// ParseAccountMetadata unmarshals and validates a metadata document read from
+// a data account.
+func ParseAccountMetadata(data []byte) (*AccountMetadata, error) {
+	md := new(AccountMetadata)
+	err := json.Unmarshal(data, md)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata document: %v", err)
+	}
+
+	err = md.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return md, nil
+}
+
+// Validate checks that the metadata has a name, that the logo hash (if any) is
+// a SHA-256 hash, and that the website (if any) is an HTTP(S) URL.
+func (md *AccountMetadata) Validate() error {
+	if md.Name == "" {
+		return errors.New("invalid metadata document: missing name")
+	}
+
+	if md.LogoHash != nil && len(md.LogoHash) != 32 {
+		return fmt.Errorf("invalid metadata document: logo hash must be 32 bytes, got %d", len(md.LogoHash))
+	}
+
+	if md.Website != "" {
+		u, err := neturl.Parse(md.Website)
+		if err != nil {
+			return fmt.Errorf("invalid metadata document: invalid website: %v", err)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return fmt.Errorf("invalid metadata document: website must be an HTTP or HTTPS URL")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,72 @@
+package protocol
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/types/state"
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+// NewStateProof converts a proof built by the state database
+func NewStateProof(p *state.Proof) *StateProof {
+	proof := new(StateProof)
+	proof.Height = p.Height
+	proof.Entry = *NewReceipt(p.Entry)
+	proof.Anchor = *NewReceipt(p.Anchor)
+	proof.AnchorHead = append([]byte{}, p.AnchorHead...)
+	proof.State = *NewReceipt(p.State)
+	return proof
+}
+
+// Verify checks that the proof leads from the entry to the app hash of the
+// header. The app hash of a block is reported by the header of the next block,
+// so the header must be that of the block after the one the proof was built
+// for.
+func (p *StateProof) Verify(header *tmtypes.Header) error {
+	if !p.Entry.Validate() {
+		return fmt.Errorf("invalid entry receipt")
+	}
+	if !p.Anchor.Validate() {
+		return fmt.Errorf("invalid anchor receipt")
+	}
+	if !p.State.Validate() {
+		return fmt.Errorf("invalid state receipt")
+	}
+
+	// The root of the chain must be anchored
+	if p.Anchor.Element != p.Entry.Anchor {
+		return fmt.Errorf("the anchor receipt does not start at the root of the chain")
+	}
+
+	// The anchor head must record the root of the anchor chain
+	head := new(state.AnchorMetadata)
+	err := head.UnmarshalBinary(p.AnchorHead)
+	if err != nil {
+		return fmt.Errorf("invalid anchor head: %v", err)
+	}
+	if head.Index != p.Height {
+		return fmt.Errorf("the anchor head is for block %d, not %d", head.Index, p.Height)
+	}
+	if head.Root != p.Anchor.Anchor {
+		return fmt.Errorf("the anchor head does not record the root of the anchor receipt")
+	}
+
+	// The BPT must hold the hash of the anchor head
+	key := state.MinorAnchorChainKey()
+	hash := sha256.Sum256(p.AnchorHead)
+	leaf := sha256.Sum256(append(key[:], hash[:]...))
+	if p.State.Element != leaf {
+		return fmt.Errorf("the state receipt does not start at the anchor head")
+	}
+
+	// The root of the BPT is the app hash
+	if header.Height != p.Height+1 {
+		return fmt.Errorf("the header is for block %d, not %d", header.Height, p.Height+1)
+	}
+	if !bytes.Equal(header.AppHash, p.State.Anchor[:]) {
+		return fmt.Errorf("the state receipt does not lead to the app hash")
+	}
+	return nil
+}
@@ -139,9 +139,6 @@ func IsValidAdiUrl(u *url.URL) error {
 	if reDigits16.MatchString(u.Authority) && len(u.Authority) == 48 {
 		errs = append(errs, "identity could be a lite account key")
 	}
-	if u.Path != "" {
-		errs = append(errs, "path is not empty")
-	}
 	if u.Query != "" {
 		errs = append(errs, "query is not empty")
 	}
@@ -168,6 +165,25 @@ func IsValidAdiUrl(u *url.URL) error {
 		}
 	}
 
+	// A path identifies a sub-ADI, e.g. acc://corp/eng. Each segment of the
+	// path must be a valid name on its own.
+	path := strings.TrimPrefix(u.Path, "/")
+	if path != "" {
+		for _, segment := range strings.Split(path, "/") {
+			if segment == "" {
+				errs = append(errs, "path has an empty segment")
+				continue
+			}
+
+			for _, r := range segment {
+				if unicode.In(r, unicode.Letter, unicode.Number) || r == '-' {
+					continue
+				}
+				errs = append(errs, fmt.Sprintf("illegal character %q in path", r))
+			}
+		}
+	}
+
 	if len(errs) == 0 {
 		return nil
 	}
@@ -25,6 +25,8 @@ func TestIsValidAdiUrl(t *testing.T) {
 	good := map[string]string{
 		"Simple":            "foo",
 		"Identity has dash": "foo-bar",
+		"Sub-ADI":           "foo/bar",
+		"Nested sub-ADI":    "foo/bar/baz-1",
 	}
 	bad := map[string]struct {
 		URL URL
@@ -33,7 +35,8 @@ func TestIsValidAdiUrl(t *testing.T) {
 		"Invalid UTF-8":           {URL{Authority: "\xF1"}, "not valid UTF-8"},
 		"Has port":                {URL{Authority: "foo:123"}, "identity has a port number"},
 		"Empty identity":          {URL{}, "identity is empty"},
-		"Has path":                {URL{Authority: "foo", Path: "bar"}, "path is not empty"},
+		"Path has empty segment":  {URL{Authority: "foo", Path: "/bar//baz"}, "path has an empty segment"},
+		"Path has dot":            {URL{Authority: "foo", Path: "/bar.baz"}, "illegal character '.' in path"},
 		"Has query":               {URL{Authority: "foo", Query: "bar"}, "query is not empty"},
 		"Has fragment":            {URL{Authority: "foo", Fragment: "bar"}, "fragment is not empty"},
 		"Identity has dot":        {URL{Authority: "foo.bar"}, "identity contains dot(s)"},
@@ -0,0 +1,36 @@
+package protocol
+
+import "github.com/AccumulateNetwork/accumulate/smt/managed"
+
+// NewReceipt converts a receipt built by the Merkle manager
+func NewReceipt(r *managed.Receipt) *Receipt {
+	receipt := new(Receipt)
+	copy(receipt.Element[:], r.Element)
+	copy(receipt.Anchor[:], r.Anchor)
+	receipt.Nodes = make([]*ReceiptNode, len(r.Nodes))
+	for i, n := range r.Nodes {
+		node := new(ReceiptNode)
+		node.Right = n.Right
+		node.Hash = n.Hash.Copy()
+		receipt.Nodes[i] = node
+	}
+	return receipt
+}
+
+// Managed converts the receipt to the form used by the Merkle manager
+func (r *Receipt) Managed() *managed.Receipt {
+	receipt := new(managed.Receipt)
+	receipt.Element = append(managed.Hash{}, r.Element[:]...)
+	receipt.Anchor = append(managed.Hash{}, r.Anchor[:]...)
+	receipt.Nodes = make([]*managed.Node, len(r.Nodes))
+	for i, n := range r.Nodes {
+		receipt.Nodes[i] = &managed.Node{Right: n.Right, Hash: managed.Hash(n.Hash).Copy()}
+	}
+	return receipt
+}
+
+// Validate returns true if applying the nodes to the element produces the
+// anchor
+func (r *Receipt) Validate() bool {
+	return r.Managed().Validate()
+}
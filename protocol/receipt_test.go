@@ -0,0 +1,44 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReceiptEncoding(t *testing.T) {
+	db, err := database.NewDBManager("memory", "")
+	require.NoError(t, err)
+	mm, err := managed.NewMerkleManager(db, 4)
+	require.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		mm.AddHash(managed.Sha256([]byte(fmt.Sprint(i))))
+	}
+
+	r := managed.GetReceipt(mm, managed.Sha256([]byte("3")), managed.Sha256([]byte("49")))
+	require.NotNil(t, r)
+	receipt := NewReceipt(r)
+	require.True(t, receipt.Validate())
+	require.Equal(t, r, receipt.Managed())
+
+	data, err := receipt.MarshalBinary()
+	require.NoError(t, err)
+	decoded := new(Receipt)
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, receipt, decoded)
+	require.True(t, decoded.Validate())
+
+	data, err = json.Marshal(receipt)
+	require.NoError(t, err)
+	decoded = new(Receipt)
+	require.NoError(t, json.Unmarshal(data, decoded))
+	require.Equal(t, receipt, decoded)
+
+	// A receipt whose element has changed is not valid
+	decoded.Element[0]++
+	require.False(t, decoded.Validate())
+}
@@ -14,6 +14,13 @@ import (
 	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
+type AccountMetadata struct {
+	Name        string `json:"name,omitempty" form:"name" query:"name" validate:"required"`
+	Description string `json:"description,omitempty" form:"description" query:"description"`
+	LogoHash    []byte `json:"logoHash,omitempty" form:"logoHash" query:"logoHash"`
+	Website     string `json:"website,omitempty" form:"website" query:"website"`
+}
+
 type AcmeFaucet struct {
 	Url string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
 }
@@ -39,6 +46,7 @@ type BurnTokens struct {
 type ChainParams struct {
 	Data     []byte `json:"data,omitempty" form:"data" query:"data" validate:"required"`
 	IsUpdate bool   `json:"isUpdate,omitempty" form:"isUpdate" query:"isUpdate" validate:"required"`
+	Metadata string `json:"metadata,omitempty" form:"metadata" query:"metadata" validate:"acc-url"`
 }
 
 type CreateDataAccount struct {
@@ -67,6 +75,10 @@ type DataAccount struct {
 	Data []byte `json:"data,omitempty" form:"data" query:"data" validate:"required"`
 }
 
+type DataIndexMetadata struct {
+	Count uint64 `json:"count,omitempty" form:"count" query:"count" validate:"required"`
+}
+
 type DirectoryIndexMetadata struct {
 	Count uint64 `json:"count,omitempty" form:"count" query:"count" validate:"required"`
 }
@@ -75,11 +87,21 @@ type DirectoryQueryResult struct {
 	Entries []string `json:"entries,omitempty" form:"entries" query:"entries" validate:"required"`
 }
 
+type ExternalAnchor struct {
+	Height    int64     `json:"height,omitempty" form:"height" query:"height" validate:"required"`
+	Root      [32]byte  `json:"root,omitempty" form:"root" query:"root" validate:"required"`
+	Timestamp time.Time `json:"timestamp,omitempty" form:"timestamp" query:"timestamp" validate:"required"`
+	Major     bool      `json:"major,omitempty" form:"major" query:"major" validate:"required"`
+	Ledger    string    `json:"ledger,omitempty" form:"ledger" query:"ledger" validate:"required"`
+	Reference []byte    `json:"reference,omitempty" form:"reference" query:"reference" validate:"required"`
+}
+
 type IdentityCreate struct {
 	Url         string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
-	PublicKey   []byte `json:"publicKey,omitempty" form:"publicKey" query:"publicKey" validate:"required"`
+	PublicKey   []byte `json:"publicKey,omitempty" form:"publicKey" query:"publicKey"`
 	KeyBookName string `json:"keyBookName,omitempty" form:"keyBookName" query:"keyBookName"`
 	KeyPageName string `json:"keyPageName,omitempty" form:"keyPageName" query:"keyPageName"`
+	Metadata    string `json:"metadata,omitempty" form:"metadata" query:"metadata" validate:"acc-url"`
 }
 
 type IssueTokens struct {
@@ -87,13 +109,39 @@ type IssueTokens struct {
 	Amount    big.Int `json:"amount,omitempty" form:"amount" query:"amount" validate:"required"`
 }
 
+type KeyIndexEntry struct {
+	KeyPage string `json:"keyPage,omitempty" form:"keyPage" query:"keyPage" validate:"required"`
+	KeyBook string `json:"keyBook,omitempty" form:"keyBook" query:"keyBook"`
+	Index   uint64 `json:"index,omitempty" form:"index" query:"index" validate:"required"`
+}
+
+type KeyIndexQueryResult struct {
+	Entries     []*KeyIndexEntry `json:"entries,omitempty" form:"entries" query:"entries" validate:"required"`
+	LiteAccount string           `json:"liteAccount,omitempty" form:"liteAccount" query:"liteAccount"`
+}
+
+type KeyPageIndex struct {
+	KeyPages []string `json:"keyPages,omitempty" form:"keyPages" query:"keyPages" validate:"required"`
+}
+
 type KeySpec struct {
-	PublicKey []byte `json:"publicKey,omitempty" form:"publicKey" query:"publicKey" validate:"required"`
-	Nonce     uint64 `json:"nonce,omitempty" form:"nonce" query:"nonce" validate:"required"`
+	PublicKey []byte           `json:"publicKey,omitempty" form:"publicKey" query:"publicKey" validate:"required"`
+	Nonce     uint64           `json:"nonce,omitempty" form:"nonce" query:"nonce" validate:"required"`
+	Limits    []*KeySpendLimit `json:"limits,omitempty" form:"limits" query:"limits"`
 }
 
 type KeySpecParams struct {
-	PublicKey []byte `json:"publicKey,omitempty" form:"publicKey" query:"publicKey" validate:"required"`
+	PublicKey []byte           `json:"publicKey,omitempty" form:"publicKey" query:"publicKey" validate:"required"`
+	Limits    []*KeySpendLimit `json:"limits,omitempty" form:"limits" query:"limits"`
+}
+
+type KeySpendLimit struct {
+	TokenUrl     string        `json:"tokenUrl,omitempty" form:"tokenUrl" query:"tokenUrl" validate:"required,acc-url"`
+	MaxPerTx     uint64        `json:"maxPerTx,omitempty" form:"maxPerTx" query:"maxPerTx"`
+	MaxPerPeriod uint64        `json:"maxPerPeriod,omitempty" form:"maxPerPeriod" query:"maxPerPeriod"`
+	Period       time.Duration `json:"period,omitempty" form:"period" query:"period"`
+	WindowStart  time.Time     `json:"windowStart,omitempty" form:"windowStart" query:"windowStart"`
+	WindowSpent  uint64        `json:"windowSpent,omitempty" form:"windowSpent" query:"windowSpent"`
 }
 
 type LiteDataAccount struct {
@@ -101,6 +149,11 @@ type LiteDataAccount struct {
 	Data []byte `json:"data,omitempty" form:"data" query:"data" validate:"required"`
 }
 
+type MetadataQueryResult struct {
+	Url  string `json:"url,omitempty" form:"url" query:"url" validate:"required"`
+	Data []byte `json:"data,omitempty" form:"data" query:"data" validate:"required"`
+}
+
 type MetricsRequest struct {
 	Metric   string        `json:"metric,omitempty" form:"metric" query:"metric" validate:"required"`
 	Duration time.Duration `json:"duration,omitempty" form:"duration" query:"duration" validate:"required"`
@@ -110,6 +163,36 @@ type MetricsResponse struct {
 	Value interface{} `json:"value,omitempty" form:"value" query:"value" validate:"required"`
 }
 
+type PendingSwap struct {
+	state.ChainHeader
+	Maker           string    `json:"maker,omitempty" form:"maker" query:"maker" validate:"required,acc-url"`
+	TokenUrl        string    `json:"tokenUrl,omitempty" form:"tokenUrl" query:"tokenUrl" validate:"required,acc-url"`
+	Amount          big.Int   `json:"amount,omitempty" form:"amount" query:"amount" validate:"required"`
+	RequestTokenUrl string    `json:"requestTokenUrl,omitempty" form:"requestTokenUrl" query:"requestTokenUrl" validate:"required,acc-url"`
+	RequestAmount   big.Int   `json:"requestAmount,omitempty" form:"requestAmount" query:"requestAmount" validate:"required"`
+	Recipient       string    `json:"recipient,omitempty" form:"recipient" query:"recipient" validate:"required,acc-url"`
+	Counterparty    string    `json:"counterparty,omitempty" form:"counterparty" query:"counterparty" validate:"acc-url"`
+	Expires         time.Time `json:"expires,omitempty" form:"expires" query:"expires" validate:"required"`
+	Closed          bool      `json:"closed,omitempty" form:"closed" query:"closed" validate:"required"`
+}
+
+type Receipt struct {
+	Element [32]byte       `json:"element,omitempty" form:"element" query:"element" validate:"required"`
+	Anchor  [32]byte       `json:"anchor,omitempty" form:"anchor" query:"anchor" validate:"required"`
+	Nodes   []*ReceiptNode `json:"nodes,omitempty" form:"nodes" query:"nodes" validate:"required"`
+}
+
+type ReceiptNode struct {
+	Right bool   `json:"right,omitempty" form:"right" query:"right" validate:"required"`
+	Hash  []byte `json:"hash,omitempty" form:"hash" query:"hash" validate:"required"`
+}
+
+type ReceiptQueryResult struct {
+	ElementIndex uint64  `json:"elementIndex,omitempty" form:"elementIndex" query:"elementIndex" validate:"required"`
+	AnchorIndex  uint64  `json:"anchorIndex,omitempty" form:"anchorIndex" query:"anchorIndex" validate:"required"`
+	Receipt      Receipt `json:"receipt,omitempty" form:"receipt" query:"receipt" validate:"required"`
+}
+
 type SigSpec struct {
 	state.ChainHeader
 	CreditBalance big.Int    `json:"creditBalance,omitempty" form:"creditBalance" query:"creditBalance" validate:"required"`
@@ -121,6 +204,43 @@ type SigSpecGroup struct {
 	SigSpecs [][32]byte `json:"sigSpecs,omitempty" form:"sigSpecs" query:"sigSpecs" validate:"required"`
 }
 
+type StateProof struct {
+	Height     int64   `json:"height,omitempty" form:"height" query:"height" validate:"required"`
+	Entry      Receipt `json:"entry,omitempty" form:"entry" query:"entry" validate:"required"`
+	Anchor     Receipt `json:"anchor,omitempty" form:"anchor" query:"anchor" validate:"required"`
+	AnchorHead []byte  `json:"anchorHead,omitempty" form:"anchorHead" query:"anchorHead" validate:"required"`
+	State      Receipt `json:"state,omitempty" form:"state" query:"state" validate:"required"`
+}
+
+type SwapAccept struct {
+	Maker     string   `json:"maker,omitempty" form:"maker" query:"maker" validate:"required,acc-url"`
+	Offer     [32]byte `json:"offer,omitempty" form:"offer" query:"offer" validate:"required"`
+	Recipient string   `json:"recipient,omitempty" form:"recipient" query:"recipient" validate:"required,acc-url"`
+}
+
+type SwapBook struct {
+	Offers []*SwapExpiry `json:"offers,omitempty" form:"offers" query:"offers" validate:"required"`
+}
+
+type SwapCancel struct {
+	Offer [32]byte `json:"offer,omitempty" form:"offer" query:"offer" validate:"required"`
+}
+
+type SwapExpiry struct {
+	Maker   string    `json:"maker,omitempty" form:"maker" query:"maker" validate:"required,acc-url"`
+	Offer   [32]byte  `json:"offer,omitempty" form:"offer" query:"offer" validate:"required"`
+	Expires time.Time `json:"expires,omitempty" form:"expires" query:"expires" validate:"required"`
+}
+
+type SwapOffer struct {
+	Amount          big.Int   `json:"amount,omitempty" form:"amount" query:"amount" validate:"required"`
+	RequestTokenUrl string    `json:"requestTokenUrl,omitempty" form:"requestTokenUrl" query:"requestTokenUrl" validate:"required,acc-url"`
+	RequestAmount   big.Int   `json:"requestAmount,omitempty" form:"requestAmount" query:"requestAmount" validate:"required"`
+	Recipient       string    `json:"recipient,omitempty" form:"recipient" query:"recipient" validate:"required,acc-url"`
+	Counterparty    string    `json:"counterparty,omitempty" form:"counterparty" query:"counterparty" validate:"acc-url"`
+	Expires         time.Time `json:"expires,omitempty" form:"expires" query:"expires" validate:"required"`
+}
+
 type SyntheticBurnTokens struct {
 	Amount big.Int `json:"amount,omitempty" form:"amount" query:"amount" validate:"required"`
 }
@@ -143,9 +263,11 @@ type SyntheticWriteData struct {
 }
 
 type TokenAccountCreate struct {
-	Url        string `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
-	TokenUrl   string `json:"tokenUrl,omitempty" form:"tokenUrl" query:"tokenUrl" validate:"required,acc-url"`
-	KeyBookUrl string `json:"keyBookUrl,omitempty" form:"keyBookUrl" query:"keyBookUrl" validate:"required,acc-url"`
+	Url        string                `json:"url,omitempty" form:"url" query:"url" validate:"required,acc-url"`
+	TokenUrl   string                `json:"tokenUrl,omitempty" form:"tokenUrl" query:"tokenUrl" validate:"required,acc-url"`
+	KeyBookUrl string                `json:"keyBookUrl,omitempty" form:"keyBookUrl" query:"keyBookUrl" validate:"required,acc-url"`
+	Vesting    state.VestingSchedule `json:"vesting,omitempty" form:"vesting" query:"vesting"`
+	Metadata   string                `json:"metadata,omitempty" form:"metadata" query:"metadata" validate:"acc-url"`
 }
 
 type TokenIssuer struct {
@@ -155,6 +277,26 @@ type TokenIssuer struct {
 	Properties string `json:"properties,omitempty" form:"properties" query:"properties" validate:"required,acc-url"`
 }
 
+type TransferHistoryQueryResult struct {
+	Total   uint64            `json:"total,omitempty" form:"total" query:"total" validate:"required"`
+	Records []*TransferRecord `json:"records,omitempty" form:"records" query:"records" validate:"required"`
+}
+
+type TransferIndexMetadata struct {
+	Count uint64 `json:"count,omitempty" form:"count" query:"count" validate:"required"`
+}
+
+type TransferRecord struct {
+	Txid         [32]byte  `json:"txid,omitempty" form:"txid" query:"txid" validate:"required"`
+	TxType       string    `json:"txType,omitempty" form:"txType" query:"txType" validate:"required"`
+	Account      string    `json:"account,omitempty" form:"account" query:"account" validate:"required"`
+	Counterparty string    `json:"counterparty,omitempty" form:"counterparty" query:"counterparty"`
+	Token        string    `json:"token,omitempty" form:"token" query:"token" validate:"required"`
+	Amount       big.Int   `json:"amount,omitempty" form:"amount" query:"amount" validate:"required"`
+	Incoming     bool      `json:"incoming,omitempty" form:"incoming" query:"incoming" validate:"required"`
+	Time         time.Time `json:"time,omitempty" form:"time" query:"time" validate:"required"`
+}
+
 type TxResult struct {
 	SyntheticTxs []*TxSynthRef `json:"syntheticTxs,omitempty" form:"syntheticTxs" query:"syntheticTxs" validate:"required"`
 }
@@ -170,6 +312,7 @@ type UpdateKeyPage struct {
 	Operation KeyPageOperation `json:"operation,omitempty" form:"operation" query:"operation" validate:"required"`
 	Key       []byte           `json:"key,omitempty" form:"key" query:"key" validate:"required"`
 	NewKey    []byte           `json:"newKey,omitempty" form:"newKey" query:"newKey" validate:"required"`
+	Limits    []*KeySpendLimit `json:"limits,omitempty" form:"limits" query:"limits"`
 }
 
 type WriteData struct {
@@ -199,6 +342,12 @@ func NewLiteDataAccount() *LiteDataAccount {
 	return v
 }
 
+func NewPendingSwap() *PendingSwap {
+	v := new(PendingSwap)
+	v.Type = types.ChainTypePendingSwap
+	return v
+}
+
 func NewSigSpec() *SigSpec {
 	v := new(SigSpec)
 	v.Type = types.ChainTypeKeyPage
@@ -235,6 +384,12 @@ func (*IdentityCreate) GetType() types.TransactionType { return types.TxTypeCrea
 
 func (*IssueTokens) GetType() types.TransactionType { return types.TxTypeIssueTokens }
 
+func (*SwapAccept) GetType() types.TransactionType { return types.TxTypeSwapAccept }
+
+func (*SwapCancel) GetType() types.TransactionType { return types.TxTypeSwapCancel }
+
+func (*SwapOffer) GetType() types.TransactionType { return types.TxTypeSwapOffer }
+
 func (*SyntheticBurnTokens) GetType() types.TransactionType { return types.TxTypeSyntheticBurnTokens }
 
 func (*SyntheticCreateChain) GetType() types.TransactionType { return types.TxTypeSyntheticCreateChain }
@@ -315,6 +470,8 @@ func (v *ChainParams) BinarySize() int {
 
 	n += encoding.BoolBinarySize(v.IsUpdate)
 
+	n += encoding.StringBinarySize(v.Metadata)
+
 	return n
 }
 
@@ -386,6 +543,14 @@ func (v *DataAccount) BinarySize() int {
 	return n
 }
 
+func (v *DataIndexMetadata) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(v.Count)
+
+	return n
+}
+
 func (v *DirectoryIndexMetadata) BinarySize() int {
 	var n int
 
@@ -407,6 +572,24 @@ func (v *DirectoryQueryResult) BinarySize() int {
 	return n
 }
 
+func (v *ExternalAnchor) BinarySize() int {
+	var n int
+
+	n += encoding.VarintBinarySize(v.Height)
+
+	n += encoding.ChainBinarySize(&v.Root)
+
+	n += encoding.TimeBinarySize(v.Timestamp)
+
+	n += encoding.BoolBinarySize(v.Major)
+
+	n += encoding.StringBinarySize(v.Ledger)
+
+	n += encoding.BytesBinarySize(v.Reference)
+
+	return n
+}
+
 func (v *IdentityCreate) BinarySize() int {
 	var n int
 
@@ -420,6 +603,8 @@ func (v *IdentityCreate) BinarySize() int {
 
 	n += encoding.StringBinarySize(v.KeyPageName)
 
+	n += encoding.StringBinarySize(v.Metadata)
+
 	return n
 }
 
@@ -435,6 +620,46 @@ func (v *IssueTokens) BinarySize() int {
 	return n
 }
 
+func (v *KeyIndexEntry) BinarySize() int {
+	var n int
+
+	n += encoding.StringBinarySize(v.KeyPage)
+
+	n += encoding.StringBinarySize(v.KeyBook)
+
+	n += encoding.UvarintBinarySize(v.Index)
+
+	return n
+}
+
+func (v *KeyIndexQueryResult) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(uint64(len(v.Entries)))
+
+	for _, v := range v.Entries {
+		n += v.BinarySize()
+
+	}
+
+	n += encoding.StringBinarySize(v.LiteAccount)
+
+	return n
+}
+
+func (v *KeyPageIndex) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(uint64(len(v.KeyPages)))
+
+	for _, v := range v.KeyPages {
+		n += encoding.StringBinarySize(v)
+
+	}
+
+	return n
+}
+
 func (v *KeySpec) BinarySize() int {
 	var n int
 
@@ -442,6 +667,13 @@ func (v *KeySpec) BinarySize() int {
 
 	n += encoding.UvarintBinarySize(v.Nonce)
 
+	n += encoding.UvarintBinarySize(uint64(len(v.Limits)))
+
+	for _, v := range v.Limits {
+		n += v.BinarySize()
+
+	}
+
 	return n
 }
 
@@ -450,6 +682,31 @@ func (v *KeySpecParams) BinarySize() int {
 
 	n += encoding.BytesBinarySize(v.PublicKey)
 
+	n += encoding.UvarintBinarySize(uint64(len(v.Limits)))
+
+	for _, v := range v.Limits {
+		n += v.BinarySize()
+
+	}
+
+	return n
+}
+
+func (v *KeySpendLimit) BinarySize() int {
+	var n int
+
+	n += encoding.StringBinarySize(v.TokenUrl)
+
+	n += encoding.UvarintBinarySize(v.MaxPerTx)
+
+	n += encoding.UvarintBinarySize(v.MaxPerPeriod)
+
+	n += encoding.DurationBinarySize(v.Period)
+
+	n += encoding.TimeBinarySize(v.WindowStart)
+
+	n += encoding.UvarintBinarySize(v.WindowSpent)
+
 	return n
 }
 
@@ -466,6 +723,16 @@ func (v *LiteDataAccount) BinarySize() int {
 	return n
 }
 
+func (v *MetadataQueryResult) BinarySize() int {
+	var n int
+
+	n += encoding.StringBinarySize(v.Url)
+
+	n += encoding.BytesBinarySize(v.Data)
+
+	return n
+}
+
 func (v *MetricsRequest) BinarySize() int {
 	var n int
 
@@ -476,6 +743,74 @@ func (v *MetricsRequest) BinarySize() int {
 	return n
 }
 
+func (v *PendingSwap) BinarySize() int {
+	var n int
+
+	// Enforce sanity
+	v.Type = types.ChainTypePendingSwap
+
+	n += v.ChainHeader.GetHeaderSize()
+
+	n += encoding.StringBinarySize(v.Maker)
+
+	n += encoding.StringBinarySize(v.TokenUrl)
+
+	n += encoding.BigintBinarySize(&v.Amount)
+
+	n += encoding.StringBinarySize(v.RequestTokenUrl)
+
+	n += encoding.BigintBinarySize(&v.RequestAmount)
+
+	n += encoding.StringBinarySize(v.Recipient)
+
+	n += encoding.StringBinarySize(v.Counterparty)
+
+	n += encoding.TimeBinarySize(v.Expires)
+
+	n += encoding.BoolBinarySize(v.Closed)
+
+	return n
+}
+
+func (v *Receipt) BinarySize() int {
+	var n int
+
+	n += encoding.ChainBinarySize(&v.Element)
+
+	n += encoding.ChainBinarySize(&v.Anchor)
+
+	n += encoding.UvarintBinarySize(uint64(len(v.Nodes)))
+
+	for _, v := range v.Nodes {
+		n += v.BinarySize()
+
+	}
+
+	return n
+}
+
+func (v *ReceiptNode) BinarySize() int {
+	var n int
+
+	n += encoding.BoolBinarySize(v.Right)
+
+	n += encoding.BytesBinarySize(v.Hash)
+
+	return n
+}
+
+func (v *ReceiptQueryResult) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(v.ElementIndex)
+
+	n += encoding.UvarintBinarySize(v.AnchorIndex)
+
+	n += v.Receipt.BinarySize()
+
+	return n
+}
+
 func (v *SigSpec) BinarySize() int {
 	var n int
 
@@ -509,6 +844,91 @@ func (v *SigSpecGroup) BinarySize() int {
 	return n
 }
 
+func (v *StateProof) BinarySize() int {
+	var n int
+
+	n += encoding.VarintBinarySize(v.Height)
+
+	n += v.Entry.BinarySize()
+
+	n += v.Anchor.BinarySize()
+
+	n += encoding.BytesBinarySize(v.AnchorHead)
+
+	n += v.State.BinarySize()
+
+	return n
+}
+
+func (v *SwapAccept) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(types.TxTypeSwapAccept.ID())
+
+	n += encoding.StringBinarySize(v.Maker)
+
+	n += encoding.ChainBinarySize(&v.Offer)
+
+	n += encoding.StringBinarySize(v.Recipient)
+
+	return n
+}
+
+func (v *SwapBook) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(uint64(len(v.Offers)))
+
+	for _, v := range v.Offers {
+		n += v.BinarySize()
+
+	}
+
+	return n
+}
+
+func (v *SwapCancel) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(types.TxTypeSwapCancel.ID())
+
+	n += encoding.ChainBinarySize(&v.Offer)
+
+	return n
+}
+
+func (v *SwapExpiry) BinarySize() int {
+	var n int
+
+	n += encoding.StringBinarySize(v.Maker)
+
+	n += encoding.ChainBinarySize(&v.Offer)
+
+	n += encoding.TimeBinarySize(v.Expires)
+
+	return n
+}
+
+func (v *SwapOffer) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(types.TxTypeSwapOffer.ID())
+
+	n += encoding.BigintBinarySize(&v.Amount)
+
+	n += encoding.StringBinarySize(v.RequestTokenUrl)
+
+	n += encoding.BigintBinarySize(&v.RequestAmount)
+
+	n += encoding.StringBinarySize(v.Recipient)
+
+	n += encoding.StringBinarySize(v.Counterparty)
+
+	n += encoding.TimeBinarySize(v.Expires)
+
+	return n
+}
+
 func (v *SyntheticBurnTokens) BinarySize() int {
 	var n int
 
@@ -577,6 +997,10 @@ func (v *TokenAccountCreate) BinarySize() int {
 
 	n += encoding.StringBinarySize(v.KeyBookUrl)
 
+	n += v.Vesting.BinarySize()
+
+	n += encoding.StringBinarySize(v.Metadata)
+
 	return n
 }
 
@@ -597,12 +1021,14 @@ func (v *TokenIssuer) BinarySize() int {
 	return n
 }
 
-func (v *TxResult) BinarySize() int {
+func (v *TransferHistoryQueryResult) BinarySize() int {
 	var n int
 
-	n += encoding.UvarintBinarySize(uint64(len(v.SyntheticTxs)))
+	n += encoding.UvarintBinarySize(v.Total)
 
-	for _, v := range v.SyntheticTxs {
+	n += encoding.UvarintBinarySize(uint64(len(v.Records)))
+
+	for _, v := range v.Records {
 		n += v.BinarySize()
 
 	}
@@ -610,14 +1036,57 @@ func (v *TxResult) BinarySize() int {
 	return n
 }
 
-func (v *TxSynthRef) BinarySize() int {
+func (v *TransferIndexMetadata) BinarySize() int {
 	var n int
 
-	n += encoding.UvarintBinarySize(v.Type)
+	n += encoding.UvarintBinarySize(v.Count)
 
-	n += encoding.ChainBinarySize(&v.Hash)
+	return n
+}
 
-	n += encoding.StringBinarySize(v.Url)
+func (v *TransferRecord) BinarySize() int {
+	var n int
+
+	n += encoding.ChainBinarySize(&v.Txid)
+
+	n += encoding.StringBinarySize(v.TxType)
+
+	n += encoding.StringBinarySize(v.Account)
+
+	n += encoding.StringBinarySize(v.Counterparty)
+
+	n += encoding.StringBinarySize(v.Token)
+
+	n += encoding.BigintBinarySize(&v.Amount)
+
+	n += encoding.BoolBinarySize(v.Incoming)
+
+	n += encoding.TimeBinarySize(v.Time)
+
+	return n
+}
+
+func (v *TxResult) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(uint64(len(v.SyntheticTxs)))
+
+	for _, v := range v.SyntheticTxs {
+		n += v.BinarySize()
+
+	}
+
+	return n
+}
+
+func (v *TxSynthRef) BinarySize() int {
+	var n int
+
+	n += encoding.UvarintBinarySize(v.Type)
+
+	n += encoding.ChainBinarySize(&v.Hash)
+
+	n += encoding.StringBinarySize(v.Url)
 
 	n += encoding.ChainBinarySize(&v.TxRef)
 
@@ -635,6 +1104,13 @@ func (v *UpdateKeyPage) BinarySize() int {
 
 	n += encoding.BytesBinarySize(v.NewKey)
 
+	n += encoding.UvarintBinarySize(uint64(len(v.Limits)))
+
+	for _, v := range v.Limits {
+		n += v.BinarySize()
+
+	}
+
 	return n
 }
 
@@ -723,6 +1199,8 @@ func (v *ChainParams) MarshalBinary() ([]byte, error) {
 
 	buffer.Write(encoding.BoolMarshalBinary(v.IsUpdate))
 
+	buffer.Write(encoding.StringMarshalBinary(v.Metadata))
+
 	return buffer.Bytes(), nil
 }
 
@@ -801,6 +1279,14 @@ func (v *DataAccount) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+func (v *DataIndexMetadata) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Count))
+
+	return buffer.Bytes(), nil
+}
+
 func (v *DirectoryIndexMetadata) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
@@ -822,6 +1308,24 @@ func (v *DirectoryQueryResult) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+func (v *ExternalAnchor) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.VarintMarshalBinary(v.Height))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Root))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Timestamp))
+
+	buffer.Write(encoding.BoolMarshalBinary(v.Major))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Ledger))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Reference))
+
+	return buffer.Bytes(), nil
+}
+
 func (v *IdentityCreate) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
@@ -835,6 +1339,8 @@ func (v *IdentityCreate) MarshalBinary() ([]byte, error) {
 
 	buffer.Write(encoding.StringMarshalBinary(v.KeyPageName))
 
+	buffer.Write(encoding.StringMarshalBinary(v.Metadata))
+
 	return buffer.Bytes(), nil
 }
 
@@ -850,68 +1356,81 @@ func (v *IssueTokens) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func (v *KeySpec) MarshalBinary() ([]byte, error) {
+func (v *KeyIndexEntry) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.BytesMarshalBinary(v.PublicKey))
+	buffer.Write(encoding.StringMarshalBinary(v.KeyPage))
 
-	buffer.Write(encoding.UvarintMarshalBinary(v.Nonce))
+	buffer.Write(encoding.StringMarshalBinary(v.KeyBook))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Index))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *KeySpecParams) MarshalBinary() ([]byte, error) {
+func (v *KeyIndexQueryResult) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.BytesMarshalBinary(v.PublicKey))
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Entries))))
+	for i, v := range v.Entries {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Entries[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	buffer.Write(encoding.StringMarshalBinary(v.LiteAccount))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *LiteDataAccount) MarshalBinary() ([]byte, error) {
+func (v *KeyPageIndex) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	// Enforce sanity
-	v.Type = types.ChainTypeLiteDataAccount
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.KeyPages))))
+	for i, v := range v.KeyPages {
+		_ = i
+		buffer.Write(encoding.StringMarshalBinary(v))
 
-	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
-		return nil, fmt.Errorf("error encoding header: %w", err)
-	} else {
-		buffer.Write(b)
 	}
-	buffer.Write(encoding.BytesMarshalBinary(v.Data))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *MetricsRequest) MarshalBinary() ([]byte, error) {
+func (v *KeySpec) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.StringMarshalBinary(v.Metric))
+	buffer.Write(encoding.BytesMarshalBinary(v.PublicKey))
 
-	buffer.Write(encoding.DurationMarshalBinary(v.Duration))
+	buffer.Write(encoding.UvarintMarshalBinary(v.Nonce))
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Limits))))
+	for i, v := range v.Limits {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Limits[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SigSpec) MarshalBinary() ([]byte, error) {
+func (v *KeySpecParams) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	// Enforce sanity
-	v.Type = types.ChainTypeKeyPage
-
-	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
-		return nil, fmt.Errorf("error encoding header: %w", err)
-	} else {
-		buffer.Write(b)
-	}
-	buffer.Write(encoding.BigintMarshalBinary(&v.CreditBalance))
+	buffer.Write(encoding.BytesMarshalBinary(v.PublicKey))
 
-	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Keys))))
-	for i, v := range v.Keys {
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Limits))))
+	for i, v := range v.Limits {
 		_ = i
 		if b, err := v.MarshalBinary(); err != nil {
-			return nil, fmt.Errorf("error encoding Keys[%d]: %w", i, err)
+			return nil, fmt.Errorf("error encoding Limits[%d]: %w", i, err)
 		} else {
 			buffer.Write(b)
 		}
@@ -921,125 +1440,157 @@ func (v *SigSpec) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func (v *SigSpecGroup) MarshalBinary() ([]byte, error) {
+func (v *KeySpendLimit) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.StringMarshalBinary(v.TokenUrl))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.MaxPerTx))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.MaxPerPeriod))
+
+	buffer.Write(encoding.DurationMarshalBinary(v.Period))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.WindowStart))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.WindowSpent))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *LiteDataAccount) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
 	// Enforce sanity
-	v.Type = types.ChainTypeKeyBook
+	v.Type = types.ChainTypeLiteDataAccount
 
 	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
 		return nil, fmt.Errorf("error encoding header: %w", err)
 	} else {
 		buffer.Write(b)
 	}
-	buffer.Write(encoding.ChainSetMarshalBinary(v.SigSpecs))
+	buffer.Write(encoding.BytesMarshalBinary(v.Data))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SyntheticBurnTokens) MarshalBinary() ([]byte, error) {
+func (v *MetadataQueryResult) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticBurnTokens.ID()))
+	buffer.Write(encoding.StringMarshalBinary(v.Url))
 
-	buffer.Write(encoding.BigintMarshalBinary(&v.Amount))
+	buffer.Write(encoding.BytesMarshalBinary(v.Data))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SyntheticCreateChain) MarshalBinary() ([]byte, error) {
+func (v *MetricsRequest) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticCreateChain.ID()))
-
-	buffer.Write(encoding.ChainMarshalBinary(&v.Cause))
-
-	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Chains))))
-	for i, v := range v.Chains {
-		_ = i
-		if b, err := v.MarshalBinary(); err != nil {
-			return nil, fmt.Errorf("error encoding Chains[%d]: %w", i, err)
-		} else {
-			buffer.Write(b)
-		}
+	buffer.Write(encoding.StringMarshalBinary(v.Metric))
 
-	}
+	buffer.Write(encoding.DurationMarshalBinary(v.Duration))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SyntheticDepositCredits) MarshalBinary() ([]byte, error) {
+func (v *PendingSwap) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticDepositCredits.ID()))
+	// Enforce sanity
+	v.Type = types.ChainTypePendingSwap
 
-	buffer.Write(encoding.ChainMarshalBinary(&v.Cause))
+	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding header: %w", err)
+	} else {
+		buffer.Write(b)
+	}
+	buffer.Write(encoding.StringMarshalBinary(v.Maker))
 
-	buffer.Write(encoding.UvarintMarshalBinary(v.Amount))
+	buffer.Write(encoding.StringMarshalBinary(v.TokenUrl))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.Amount))
+
+	buffer.Write(encoding.StringMarshalBinary(v.RequestTokenUrl))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.RequestAmount))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Recipient))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Counterparty))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Expires))
+
+	buffer.Write(encoding.BoolMarshalBinary(v.Closed))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SyntheticGenesis) MarshalBinary() ([]byte, error) {
+func (v *Receipt) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticGenesis.ID()))
+	buffer.Write(encoding.ChainMarshalBinary(&v.Element))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Anchor))
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Nodes))))
+	for i, v := range v.Nodes {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Nodes[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
 
 	return buffer.Bytes(), nil
 }
 
-func (v *SyntheticWriteData) MarshalBinary() ([]byte, error) {
+func (v *ReceiptNode) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticWriteData.ID()))
+	buffer.Write(encoding.BoolMarshalBinary(v.Right))
 
-	buffer.Write(encoding.BytesMarshalBinary(v.Data))
+	buffer.Write(encoding.BytesMarshalBinary(v.Hash))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *TokenAccountCreate) MarshalBinary() ([]byte, error) {
+func (v *ReceiptQueryResult) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeCreateTokenAccount.ID()))
-
-	buffer.Write(encoding.StringMarshalBinary(v.Url))
+	buffer.Write(encoding.UvarintMarshalBinary(v.ElementIndex))
 
-	buffer.Write(encoding.StringMarshalBinary(v.TokenUrl))
+	buffer.Write(encoding.UvarintMarshalBinary(v.AnchorIndex))
 
-	buffer.Write(encoding.StringMarshalBinary(v.KeyBookUrl))
+	if b, err := v.Receipt.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding Receipt: %w", err)
+	} else {
+		buffer.Write(b)
+	}
 
 	return buffer.Bytes(), nil
 }
 
-func (v *TokenIssuer) MarshalBinary() ([]byte, error) {
+func (v *SigSpec) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
 	// Enforce sanity
-	v.Type = types.ChainTypeTokenIssuer
+	v.Type = types.ChainTypeKeyPage
 
 	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
 		return nil, fmt.Errorf("error encoding header: %w", err)
 	} else {
 		buffer.Write(b)
 	}
-	buffer.Write(encoding.StringMarshalBinary(v.Symbol))
-
-	buffer.Write(encoding.UvarintMarshalBinary(v.Precision))
-
-	buffer.Write(encoding.StringMarshalBinary(v.Properties))
-
-	return buffer.Bytes(), nil
-}
-
-func (v *TxResult) MarshalBinary() ([]byte, error) {
-	var buffer bytes.Buffer
+	buffer.Write(encoding.BigintMarshalBinary(&v.CreditBalance))
 
-	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.SyntheticTxs))))
-	for i, v := range v.SyntheticTxs {
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Keys))))
+	for i, v := range v.Keys {
 		_ = i
 		if b, err := v.MarshalBinary(); err != nil {
-			return nil, fmt.Errorf("error encoding SyntheticTxs[%d]: %w", i, err)
+			return nil, fmt.Errorf("error encoding Keys[%d]: %w", i, err)
 		} else {
 			buffer.Write(b)
 		}
@@ -1049,52 +1600,349 @@ func (v *TxResult) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func (v *TxSynthRef) MarshalBinary() ([]byte, error) {
+func (v *SigSpecGroup) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(v.Type))
-
-	buffer.Write(encoding.ChainMarshalBinary(&v.Hash))
-
-	buffer.Write(encoding.StringMarshalBinary(v.Url))
+	// Enforce sanity
+	v.Type = types.ChainTypeKeyBook
 
-	buffer.Write(encoding.ChainMarshalBinary(&v.TxRef))
+	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding header: %w", err)
+	} else {
+		buffer.Write(b)
+	}
+	buffer.Write(encoding.ChainSetMarshalBinary(v.SigSpecs))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *UpdateKeyPage) MarshalBinary() ([]byte, error) {
+func (v *StateProof) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeUpdateKeyPage.ID()))
+	buffer.Write(encoding.VarintMarshalBinary(v.Height))
 
-	if b, err := v.Operation.MarshalBinary(); err != nil {
-		return nil, fmt.Errorf("error encoding Operation: %w", err)
+	if b, err := v.Entry.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding Entry: %w", err)
 	} else {
 		buffer.Write(b)
 	}
 
-	buffer.Write(encoding.BytesMarshalBinary(v.Key))
+	if b, err := v.Anchor.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding Anchor: %w", err)
+	} else {
+		buffer.Write(b)
+	}
 
-	buffer.Write(encoding.BytesMarshalBinary(v.NewKey))
+	buffer.Write(encoding.BytesMarshalBinary(v.AnchorHead))
+
+	if b, err := v.State.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding State: %w", err)
+	} else {
+		buffer.Write(b)
+	}
 
 	return buffer.Bytes(), nil
 }
 
-func (v *WriteData) MarshalBinary() ([]byte, error) {
+func (v *SwapAccept) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeWriteData.ID()))
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSwapAccept.ID()))
 
-	buffer.Write(encoding.BytesMarshalBinary(v.Data))
+	buffer.Write(encoding.StringMarshalBinary(v.Maker))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Offer))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Recipient))
 
 	return buffer.Bytes(), nil
 }
 
-func (v *WriteDataTo) MarshalBinary() ([]byte, error) {
+func (v *SwapBook) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
-	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeWriteDataTo.ID()))
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Offers))))
+	for i, v := range v.Offers {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Offers[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SwapCancel) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSwapCancel.ID()))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Offer))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SwapExpiry) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.StringMarshalBinary(v.Maker))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Offer))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Expires))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SwapOffer) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSwapOffer.ID()))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.Amount))
+
+	buffer.Write(encoding.StringMarshalBinary(v.RequestTokenUrl))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.RequestAmount))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Recipient))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Counterparty))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Expires))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SyntheticBurnTokens) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticBurnTokens.ID()))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.Amount))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SyntheticCreateChain) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticCreateChain.ID()))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Cause))
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Chains))))
+	for i, v := range v.Chains {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Chains[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SyntheticDepositCredits) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticDepositCredits.ID()))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Cause))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Amount))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SyntheticGenesis) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticGenesis.ID()))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *SyntheticWriteData) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeSyntheticWriteData.ID()))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Data))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TokenAccountCreate) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeCreateTokenAccount.ID()))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Url))
+
+	buffer.Write(encoding.StringMarshalBinary(v.TokenUrl))
+
+	buffer.Write(encoding.StringMarshalBinary(v.KeyBookUrl))
+
+	if b, err := v.Vesting.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding Vesting: %w", err)
+	} else {
+		buffer.Write(b)
+	}
+
+	buffer.Write(encoding.StringMarshalBinary(v.Metadata))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TokenIssuer) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	// Enforce sanity
+	v.Type = types.ChainTypeTokenIssuer
+
+	if b, err := v.ChainHeader.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding header: %w", err)
+	} else {
+		buffer.Write(b)
+	}
+	buffer.Write(encoding.StringMarshalBinary(v.Symbol))
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Precision))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Properties))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TransferHistoryQueryResult) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Total))
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Records))))
+	for i, v := range v.Records {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Records[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TransferIndexMetadata) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Count))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TransferRecord) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Txid))
+
+	buffer.Write(encoding.StringMarshalBinary(v.TxType))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Account))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Counterparty))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Token))
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.Amount))
+
+	buffer.Write(encoding.BoolMarshalBinary(v.Incoming))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Time))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TxResult) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.SyntheticTxs))))
+	for i, v := range v.SyntheticTxs {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding SyntheticTxs[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (v *TxSynthRef) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(v.Type))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Hash))
+
+	buffer.Write(encoding.StringMarshalBinary(v.Url))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.TxRef))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *UpdateKeyPage) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeUpdateKeyPage.ID()))
+
+	if b, err := v.Operation.MarshalBinary(); err != nil {
+		return nil, fmt.Errorf("error encoding Operation: %w", err)
+	} else {
+		buffer.Write(b)
+	}
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Key))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.NewKey))
+
+	buffer.Write(encoding.UvarintMarshalBinary(uint64(len(v.Limits))))
+	for i, v := range v.Limits {
+		_ = i
+		if b, err := v.MarshalBinary(); err != nil {
+			return nil, fmt.Errorf("error encoding Limits[%d]: %w", i, err)
+		} else {
+			buffer.Write(b)
+		}
+
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (v *WriteData) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeWriteData.ID()))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Data))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *WriteDataTo) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.UvarintMarshalBinary(types.TxTypeWriteDataTo.ID()))
 
 	buffer.Write(encoding.StringMarshalBinary(v.Recipient))
 
@@ -1229,6 +2077,13 @@ func (v *ChainParams) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.BoolBinarySize(v.IsUpdate):]
 
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Metadata: %w", err)
+	} else {
+		v.Metadata = x
+	}
+	data = data[encoding.StringBinarySize(v.Metadata):]
+
 	return nil
 }
 
@@ -1374,7 +2229,7 @@ func (v *DataAccount) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func (v *DirectoryIndexMetadata) UnmarshalBinary(data []byte) error {
+func (v *DataIndexMetadata) UnmarshalBinary(data []byte) error {
 	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
 		return fmt.Errorf("error decoding Count: %w", err)
 	} else {
@@ -1385,10 +2240,21 @@ func (v *DirectoryIndexMetadata) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
-func (v *DirectoryQueryResult) UnmarshalBinary(data []byte) error {
-	var lenEntries uint64
+func (v *DirectoryIndexMetadata) UnmarshalBinary(data []byte) error {
 	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Entries: %w", err)
+		return fmt.Errorf("error decoding Count: %w", err)
+	} else {
+		v.Count = x
+	}
+	data = data[encoding.UvarintBinarySize(v.Count):]
+
+	return nil
+}
+
+func (v *DirectoryQueryResult) UnmarshalBinary(data []byte) error {
+	var lenEntries uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Entries: %w", err)
 	} else {
 		lenEntries = x
 	}
@@ -1408,6 +2274,52 @@ func (v *DirectoryQueryResult) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *ExternalAnchor) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Height: %w", err)
+	} else {
+		v.Height = x
+	}
+	data = data[encoding.VarintBinarySize(v.Height):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Root: %w", err)
+	} else {
+		v.Root = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Root):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Timestamp: %w", err)
+	} else {
+		v.Timestamp = x
+	}
+	data = data[encoding.TimeBinarySize(v.Timestamp):]
+
+	if x, err := encoding.BoolUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Major: %w", err)
+	} else {
+		v.Major = x
+	}
+	data = data[encoding.BoolBinarySize(v.Major):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Ledger: %w", err)
+	} else {
+		v.Ledger = x
+	}
+	data = data[encoding.StringBinarySize(v.Ledger):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Reference: %w", err)
+	} else {
+		v.Reference = x
+	}
+	data = data[encoding.BytesBinarySize(v.Reference):]
+
+	return nil
+}
+
 func (v *IdentityCreate) UnmarshalBinary(data []byte) error {
 	typ := types.TxTypeCreateIdentity
 	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
@@ -1445,6 +2357,13 @@ func (v *IdentityCreate) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.StringBinarySize(v.KeyPageName):]
 
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Metadata: %w", err)
+	} else {
+		v.Metadata = x
+	}
+	data = data[encoding.StringBinarySize(v.Metadata):]
+
 	return nil
 }
 
@@ -1474,6 +2393,84 @@ func (v *IssueTokens) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *KeyIndexEntry) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding KeyPage: %w", err)
+	} else {
+		v.KeyPage = x
+	}
+	data = data[encoding.StringBinarySize(v.KeyPage):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding KeyBook: %w", err)
+	} else {
+		v.KeyBook = x
+	}
+	data = data[encoding.StringBinarySize(v.KeyBook):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Index: %w", err)
+	} else {
+		v.Index = x
+	}
+	data = data[encoding.UvarintBinarySize(v.Index):]
+
+	return nil
+}
+
+func (v *KeyIndexQueryResult) UnmarshalBinary(data []byte) error {
+	var lenEntries uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Entries: %w", err)
+	} else {
+		lenEntries = x
+	}
+	data = data[encoding.UvarintBinarySize(lenEntries):]
+
+	v.Entries = make([]*KeyIndexEntry, lenEntries)
+	for i := range v.Entries {
+		x := new(KeyIndexEntry)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Entries[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Entries[i] = x
+	}
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding LiteAccount: %w", err)
+	} else {
+		v.LiteAccount = x
+	}
+	data = data[encoding.StringBinarySize(v.LiteAccount):]
+
+	return nil
+}
+
+func (v *KeyPageIndex) UnmarshalBinary(data []byte) error {
+	var lenKeyPages uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding KeyPages: %w", err)
+	} else {
+		lenKeyPages = x
+	}
+	data = data[encoding.UvarintBinarySize(lenKeyPages):]
+
+	v.KeyPages = make([]string, lenKeyPages)
+	for i := range v.KeyPages {
+		if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding KeyPages[%d]: %w", i, err)
+		} else {
+			v.KeyPages[i] = x
+		}
+		data = data[encoding.StringBinarySize(v.KeyPages[i]):]
+
+	}
+
+	return nil
+}
+
 func (v *KeySpec) UnmarshalBinary(data []byte) error {
 	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
 		return fmt.Errorf("error decoding PublicKey: %w", err)
@@ -1489,6 +2486,25 @@ func (v *KeySpec) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.UvarintBinarySize(v.Nonce):]
 
+	var lenLimits uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Limits: %w", err)
+	} else {
+		lenLimits = x
+	}
+	data = data[encoding.UvarintBinarySize(lenLimits):]
+
+	v.Limits = make([]*KeySpendLimit, lenLimits)
+	for i := range v.Limits {
+		x := new(KeySpendLimit)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Limits[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Limits[i] = x
+	}
+
 	return nil
 }
 
@@ -1500,6 +2516,71 @@ func (v *KeySpecParams) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.BytesBinarySize(v.PublicKey):]
 
+	var lenLimits uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Limits: %w", err)
+	} else {
+		lenLimits = x
+	}
+	data = data[encoding.UvarintBinarySize(lenLimits):]
+
+	v.Limits = make([]*KeySpendLimit, lenLimits)
+	for i := range v.Limits {
+		x := new(KeySpendLimit)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Limits[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Limits[i] = x
+	}
+
+	return nil
+}
+
+func (v *KeySpendLimit) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TokenUrl: %w", err)
+	} else {
+		v.TokenUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.TokenUrl):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding MaxPerTx: %w", err)
+	} else {
+		v.MaxPerTx = x
+	}
+	data = data[encoding.UvarintBinarySize(v.MaxPerTx):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding MaxPerPeriod: %w", err)
+	} else {
+		v.MaxPerPeriod = x
+	}
+	data = data[encoding.UvarintBinarySize(v.MaxPerPeriod):]
+
+	if x, err := encoding.DurationUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Period: %w", err)
+	} else {
+		v.Period = x
+	}
+	data = data[encoding.DurationBinarySize(v.Period):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding WindowStart: %w", err)
+	} else {
+		v.WindowStart = x
+	}
+	data = data[encoding.TimeBinarySize(v.WindowStart):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding WindowSpent: %w", err)
+	} else {
+		v.WindowSpent = x
+	}
+	data = data[encoding.UvarintBinarySize(v.WindowSpent):]
+
 	return nil
 }
 
@@ -1522,6 +2603,24 @@ func (v *LiteDataAccount) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *MetadataQueryResult) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Url: %w", err)
+	} else {
+		v.Url = x
+	}
+	data = data[encoding.StringBinarySize(v.Url):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Data: %w", err)
+	} else {
+		v.Data = x
+	}
+	data = data[encoding.BytesBinarySize(v.Data):]
+
+	return nil
+}
+
 func (v *MetricsRequest) UnmarshalBinary(data []byte) error {
 	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
 		return fmt.Errorf("error decoding Metric: %w", err)
@@ -1540,6 +2639,159 @@ func (v *MetricsRequest) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *PendingSwap) UnmarshalBinary(data []byte) error {
+	typ := types.ChainTypePendingSwap
+	if err := v.ChainHeader.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding header: %w", err)
+	} else if v.Type != typ {
+		return fmt.Errorf("invalid chain type: want %v, got %v", typ, v.Type)
+	}
+	data = data[v.GetHeaderSize():]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Maker: %w", err)
+	} else {
+		v.Maker = x
+	}
+	data = data[encoding.StringBinarySize(v.Maker):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TokenUrl: %w", err)
+	} else {
+		v.TokenUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.TokenUrl):]
+
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Amount: %w", err)
+	} else {
+		v.Amount.Set(x)
+	}
+	data = data[encoding.BigintBinarySize(&v.Amount):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding RequestTokenUrl: %w", err)
+	} else {
+		v.RequestTokenUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.RequestTokenUrl):]
+
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding RequestAmount: %w", err)
+	} else {
+		v.RequestAmount.Set(x)
+	}
+	data = data[encoding.BigintBinarySize(&v.RequestAmount):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Recipient: %w", err)
+	} else {
+		v.Recipient = x
+	}
+	data = data[encoding.StringBinarySize(v.Recipient):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Counterparty: %w", err)
+	} else {
+		v.Counterparty = x
+	}
+	data = data[encoding.StringBinarySize(v.Counterparty):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Expires: %w", err)
+	} else {
+		v.Expires = x
+	}
+	data = data[encoding.TimeBinarySize(v.Expires):]
+
+	if x, err := encoding.BoolUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Closed: %w", err)
+	} else {
+		v.Closed = x
+	}
+	data = data[encoding.BoolBinarySize(v.Closed):]
+
+	return nil
+}
+
+func (v *Receipt) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Element: %w", err)
+	} else {
+		v.Element = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Element):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Anchor: %w", err)
+	} else {
+		v.Anchor = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Anchor):]
+
+	var lenNodes uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Nodes: %w", err)
+	} else {
+		lenNodes = x
+	}
+	data = data[encoding.UvarintBinarySize(lenNodes):]
+
+	v.Nodes = make([]*ReceiptNode, lenNodes)
+	for i := range v.Nodes {
+		x := new(ReceiptNode)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Nodes[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Nodes[i] = x
+	}
+
+	return nil
+}
+
+func (v *ReceiptNode) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.BoolUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Right: %w", err)
+	} else {
+		v.Right = x
+	}
+	data = data[encoding.BoolBinarySize(v.Right):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Hash: %w", err)
+	} else {
+		v.Hash = x
+	}
+	data = data[encoding.BytesBinarySize(v.Hash):]
+
+	return nil
+}
+
+func (v *ReceiptQueryResult) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding ElementIndex: %w", err)
+	} else {
+		v.ElementIndex = x
+	}
+	data = data[encoding.UvarintBinarySize(v.ElementIndex):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding AnchorIndex: %w", err)
+	} else {
+		v.AnchorIndex = x
+	}
+	data = data[encoding.UvarintBinarySize(v.AnchorIndex):]
+
+	if err := v.Receipt.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Receipt: %w", err)
+	}
+	data = data[v.Receipt.BinarySize():]
+
+	return nil
+}
+
 func (v *SigSpec) UnmarshalBinary(data []byte) error {
 	typ := types.ChainTypeKeyPage
 	if err := v.ChainHeader.UnmarshalBinary(data); err != nil {
@@ -1597,6 +2849,193 @@ func (v *SigSpecGroup) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *StateProof) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Height: %w", err)
+	} else {
+		v.Height = x
+	}
+	data = data[encoding.VarintBinarySize(v.Height):]
+
+	if err := v.Entry.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Entry: %w", err)
+	}
+	data = data[v.Entry.BinarySize():]
+
+	if err := v.Anchor.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Anchor: %w", err)
+	}
+	data = data[v.Anchor.BinarySize():]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding AnchorHead: %w", err)
+	} else {
+		v.AnchorHead = x
+	}
+	data = data[encoding.BytesBinarySize(v.AnchorHead):]
+
+	if err := v.State.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding State: %w", err)
+	}
+	data = data[v.State.BinarySize():]
+
+	return nil
+}
+
+func (v *SwapAccept) UnmarshalBinary(data []byte) error {
+	typ := types.TxTypeSwapAccept
+	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TX type: %w", err)
+	} else if v != uint64(typ) {
+		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
+	}
+	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Maker: %w", err)
+	} else {
+		v.Maker = x
+	}
+	data = data[encoding.StringBinarySize(v.Maker):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Offer):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Recipient: %w", err)
+	} else {
+		v.Recipient = x
+	}
+	data = data[encoding.StringBinarySize(v.Recipient):]
+
+	return nil
+}
+
+func (v *SwapBook) UnmarshalBinary(data []byte) error {
+	var lenOffers uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Offers: %w", err)
+	} else {
+		lenOffers = x
+	}
+	data = data[encoding.UvarintBinarySize(lenOffers):]
+
+	v.Offers = make([]*SwapExpiry, lenOffers)
+	for i := range v.Offers {
+		x := new(SwapExpiry)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Offers[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Offers[i] = x
+	}
+
+	return nil
+}
+
+func (v *SwapCancel) UnmarshalBinary(data []byte) error {
+	typ := types.TxTypeSwapCancel
+	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TX type: %w", err)
+	} else if v != uint64(typ) {
+		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
+	}
+	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Offer):]
+
+	return nil
+}
+
+func (v *SwapExpiry) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Maker: %w", err)
+	} else {
+		v.Maker = x
+	}
+	data = data[encoding.StringBinarySize(v.Maker):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Offer):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Expires: %w", err)
+	} else {
+		v.Expires = x
+	}
+	data = data[encoding.TimeBinarySize(v.Expires):]
+
+	return nil
+}
+
+func (v *SwapOffer) UnmarshalBinary(data []byte) error {
+	typ := types.TxTypeSwapOffer
+	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TX type: %w", err)
+	} else if v != uint64(typ) {
+		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
+	}
+	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Amount: %w", err)
+	} else {
+		v.Amount.Set(x)
+	}
+	data = data[encoding.BigintBinarySize(&v.Amount):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding RequestTokenUrl: %w", err)
+	} else {
+		v.RequestTokenUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.RequestTokenUrl):]
+
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding RequestAmount: %w", err)
+	} else {
+		v.RequestAmount.Set(x)
+	}
+	data = data[encoding.BigintBinarySize(&v.RequestAmount):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Recipient: %w", err)
+	} else {
+		v.Recipient = x
+	}
+	data = data[encoding.StringBinarySize(v.Recipient):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Counterparty: %w", err)
+	} else {
+		v.Counterparty = x
+	}
+	data = data[encoding.StringBinarySize(v.Counterparty):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Expires: %w", err)
+	} else {
+		v.Expires = x
+	}
+	data = data[encoding.TimeBinarySize(v.Expires):]
+
+	return nil
+}
+
 func (v *SyntheticBurnTokens) UnmarshalBinary(data []byte) error {
 	typ := types.TxTypeSyntheticBurnTokens
 	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
@@ -1697,80 +3136,193 @@ func (v *SyntheticWriteData) UnmarshalBinary(data []byte) error {
 	} else if v != uint64(typ) {
 		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
 	}
-	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Data: %w", err)
+	} else {
+		v.Data = x
+	}
+	data = data[encoding.BytesBinarySize(v.Data):]
+
+	return nil
+}
+
+func (v *TokenAccountCreate) UnmarshalBinary(data []byte) error {
+	typ := types.TxTypeCreateTokenAccount
+	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TX type: %w", err)
+	} else if v != uint64(typ) {
+		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
+	}
+	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Url: %w", err)
+	} else {
+		v.Url = x
+	}
+	data = data[encoding.StringBinarySize(v.Url):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding TokenUrl: %w", err)
+	} else {
+		v.TokenUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.TokenUrl):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding KeyBookUrl: %w", err)
+	} else {
+		v.KeyBookUrl = x
+	}
+	data = data[encoding.StringBinarySize(v.KeyBookUrl):]
+
+	if err := v.Vesting.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Vesting: %w", err)
+	}
+	data = data[v.Vesting.BinarySize():]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Metadata: %w", err)
+	} else {
+		v.Metadata = x
+	}
+	data = data[encoding.StringBinarySize(v.Metadata):]
+
+	return nil
+}
+
+func (v *TokenIssuer) UnmarshalBinary(data []byte) error {
+	typ := types.ChainTypeTokenIssuer
+	if err := v.ChainHeader.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding header: %w", err)
+	} else if v.Type != typ {
+		return fmt.Errorf("invalid chain type: want %v, got %v", typ, v.Type)
+	}
+	data = data[v.GetHeaderSize():]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Symbol: %w", err)
+	} else {
+		v.Symbol = x
+	}
+	data = data[encoding.StringBinarySize(v.Symbol):]
+
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Precision: %w", err)
+	} else {
+		v.Precision = x
+	}
+	data = data[encoding.UvarintBinarySize(v.Precision):]
+
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Properties: %w", err)
+	} else {
+		v.Properties = x
+	}
+	data = data[encoding.StringBinarySize(v.Properties):]
+
+	return nil
+}
+
+func (v *TransferHistoryQueryResult) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Total: %w", err)
+	} else {
+		v.Total = x
+	}
+	data = data[encoding.UvarintBinarySize(v.Total):]
+
+	var lenRecords uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Records: %w", err)
+	} else {
+		lenRecords = x
+	}
+	data = data[encoding.UvarintBinarySize(lenRecords):]
+
+	v.Records = make([]*TransferRecord, lenRecords)
+	for i := range v.Records {
+		x := new(TransferRecord)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Records[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Records[i] = x
+	}
 
-	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Data: %w", err)
+	return nil
+}
+
+func (v *TransferIndexMetadata) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Count: %w", err)
 	} else {
-		v.Data = x
+		v.Count = x
 	}
-	data = data[encoding.BytesBinarySize(v.Data):]
+	data = data[encoding.UvarintBinarySize(v.Count):]
 
 	return nil
 }
 
-func (v *TokenAccountCreate) UnmarshalBinary(data []byte) error {
-	typ := types.TxTypeCreateTokenAccount
-	if v, err := encoding.UvarintUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding TX type: %w", err)
-	} else if v != uint64(typ) {
-		return fmt.Errorf("invalid TX type: want %v, got %v", typ, types.TransactionType(v))
+func (v *TransferRecord) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Txid: %w", err)
+	} else {
+		v.Txid = x
 	}
-	data = data[encoding.UvarintBinarySize(uint64(typ)):]
+	data = data[encoding.ChainBinarySize(&v.Txid):]
 
 	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Url: %w", err)
+		return fmt.Errorf("error decoding TxType: %w", err)
 	} else {
-		v.Url = x
+		v.TxType = x
 	}
-	data = data[encoding.StringBinarySize(v.Url):]
+	data = data[encoding.StringBinarySize(v.TxType):]
 
 	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding TokenUrl: %w", err)
+		return fmt.Errorf("error decoding Account: %w", err)
 	} else {
-		v.TokenUrl = x
+		v.Account = x
 	}
-	data = data[encoding.StringBinarySize(v.TokenUrl):]
+	data = data[encoding.StringBinarySize(v.Account):]
 
 	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding KeyBookUrl: %w", err)
+		return fmt.Errorf("error decoding Counterparty: %w", err)
 	} else {
-		v.KeyBookUrl = x
+		v.Counterparty = x
 	}
-	data = data[encoding.StringBinarySize(v.KeyBookUrl):]
-
-	return nil
-}
+	data = data[encoding.StringBinarySize(v.Counterparty):]
 
-func (v *TokenIssuer) UnmarshalBinary(data []byte) error {
-	typ := types.ChainTypeTokenIssuer
-	if err := v.ChainHeader.UnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding header: %w", err)
-	} else if v.Type != typ {
-		return fmt.Errorf("invalid chain type: want %v, got %v", typ, v.Type)
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Token: %w", err)
+	} else {
+		v.Token = x
 	}
-	data = data[v.GetHeaderSize():]
+	data = data[encoding.StringBinarySize(v.Token):]
 
-	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Symbol: %w", err)
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Amount: %w", err)
 	} else {
-		v.Symbol = x
+		v.Amount.Set(x)
 	}
-	data = data[encoding.StringBinarySize(v.Symbol):]
+	data = data[encoding.BigintBinarySize(&v.Amount):]
 
-	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Precision: %w", err)
+	if x, err := encoding.BoolUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Incoming: %w", err)
 	} else {
-		v.Precision = x
+		v.Incoming = x
 	}
-	data = data[encoding.UvarintBinarySize(v.Precision):]
+	data = data[encoding.BoolBinarySize(v.Incoming):]
 
-	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
-		return fmt.Errorf("error decoding Properties: %w", err)
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Time: %w", err)
 	} else {
-		v.Properties = x
+		v.Time = x
 	}
-	data = data[encoding.StringBinarySize(v.Properties):]
+	data = data[encoding.TimeBinarySize(v.Time):]
 
 	return nil
 }
@@ -1858,6 +3410,25 @@ func (v *UpdateKeyPage) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.BytesBinarySize(v.NewKey):]
 
+	var lenLimits uint64
+	if x, err := encoding.UvarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Limits: %w", err)
+	} else {
+		lenLimits = x
+	}
+	data = data[encoding.UvarintBinarySize(lenLimits):]
+
+	v.Limits = make([]*KeySpendLimit, lenLimits)
+	for i := range v.Limits {
+		x := new(KeySpendLimit)
+		if err := x.UnmarshalBinary(data); err != nil {
+			return fmt.Errorf("error decoding Limits[%d]: %w", i, err)
+		}
+		data = data[x.BinarySize():]
+
+		v.Limits[i] = x
+	}
+
 	return nil
 }
 
@@ -1906,13 +3477,29 @@ func (v *WriteDataTo) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *AccountMetadata) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Name        string  `json:"name,omitempty"`
+		Description string  `json:"description,omitempty"`
+		LogoHash    *string `json:"logoHash,omitempty"`
+		Website     string  `json:"website,omitempty"`
+	}{}
+	u.Name = v.Name
+	u.Description = v.Description
+	u.LogoHash = encoding.BytesToJSON(v.LogoHash)
+	u.Website = v.Website
+	return json.Marshal(&u)
+}
+
 func (v *ChainParams) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Data     *string `json:"data,omitempty"`
 		IsUpdate bool    `json:"isUpdate,omitempty"`
+		Metadata string  `json:"metadata,omitempty"`
 	}{}
 	u.Data = encoding.BytesToJSON(v.Data)
 	u.IsUpdate = v.IsUpdate
+	u.Metadata = v.Metadata
 	return json.Marshal(&u)
 }
 
@@ -1936,35 +3523,77 @@ func (v *DataAccount) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *ExternalAnchor) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Height    int64     `json:"height,omitempty"`
+		Root      string    `json:"root,omitempty"`
+		Timestamp time.Time `json:"timestamp,omitempty"`
+		Major     bool      `json:"major,omitempty"`
+		Ledger    string    `json:"ledger,omitempty"`
+		Reference *string   `json:"reference,omitempty"`
+	}{}
+	u.Height = v.Height
+	u.Root = encoding.ChainToJSON(v.Root)
+	u.Timestamp = v.Timestamp
+	u.Major = v.Major
+	u.Ledger = v.Ledger
+	u.Reference = encoding.BytesToJSON(v.Reference)
+	return json.Marshal(&u)
+}
+
 func (v *IdentityCreate) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Url         string  `json:"url,omitempty"`
 		PublicKey   *string `json:"publicKey,omitempty"`
 		KeyBookName string  `json:"keyBookName,omitempty"`
 		KeyPageName string  `json:"keyPageName,omitempty"`
+		Metadata    string  `json:"metadata,omitempty"`
 	}{}
 	u.Url = v.Url
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
 	u.KeyBookName = v.KeyBookName
 	u.KeyPageName = v.KeyPageName
+	u.Metadata = v.Metadata
 	return json.Marshal(&u)
 }
 
 func (v *KeySpec) MarshalJSON() ([]byte, error) {
 	u := struct {
-		PublicKey *string `json:"publicKey,omitempty"`
-		Nonce     uint64  `json:"nonce,omitempty"`
+		PublicKey *string          `json:"publicKey,omitempty"`
+		Nonce     uint64           `json:"nonce,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
 	u.Nonce = v.Nonce
+	u.Limits = v.Limits
 	return json.Marshal(&u)
 }
 
 func (v *KeySpecParams) MarshalJSON() ([]byte, error) {
 	u := struct {
-		PublicKey *string `json:"publicKey,omitempty"`
+		PublicKey *string          `json:"publicKey,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
+	u.Limits = v.Limits
+	return json.Marshal(&u)
+}
+
+func (v *KeySpendLimit) MarshalJSON() ([]byte, error) {
+	u := struct {
+		TokenUrl     string      `json:"tokenUrl,omitempty"`
+		MaxPerTx     uint64      `json:"maxPerTx,omitempty"`
+		MaxPerPeriod uint64      `json:"maxPerPeriod,omitempty"`
+		Period       interface{} `json:"period,omitempty"`
+		WindowStart  time.Time   `json:"windowStart,omitempty"`
+		WindowSpent  uint64      `json:"windowSpent,omitempty"`
+	}{}
+	u.TokenUrl = v.TokenUrl
+	u.MaxPerTx = v.MaxPerTx
+	u.MaxPerPeriod = v.MaxPerPeriod
+	u.Period = encoding.DurationToJSON(v.Period)
+	u.WindowStart = v.WindowStart
+	u.WindowSpent = v.WindowSpent
 	return json.Marshal(&u)
 }
 
@@ -1978,6 +3607,16 @@ func (v *LiteDataAccount) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *MetadataQueryResult) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Url  string  `json:"url,omitempty"`
+		Data *string `json:"data,omitempty"`
+	}{}
+	u.Url = v.Url
+	u.Data = encoding.BytesToJSON(v.Data)
+	return json.Marshal(&u)
+}
+
 func (v *MetricsRequest) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Metric   string      `json:"metric,omitempty"`
@@ -1988,6 +3627,28 @@ func (v *MetricsRequest) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *Receipt) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Element string         `json:"element,omitempty"`
+		Anchor  string         `json:"anchor,omitempty"`
+		Nodes   []*ReceiptNode `json:"nodes,omitempty"`
+	}{}
+	u.Element = encoding.ChainToJSON(v.Element)
+	u.Anchor = encoding.ChainToJSON(v.Anchor)
+	u.Nodes = v.Nodes
+	return json.Marshal(&u)
+}
+
+func (v *ReceiptNode) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Right bool    `json:"right,omitempty"`
+		Hash  *string `json:"hash,omitempty"`
+	}{}
+	u.Right = v.Right
+	u.Hash = encoding.BytesToJSON(v.Hash)
+	return json.Marshal(&u)
+}
+
 func (v *SigSpecGroup) MarshalJSON() ([]byte, error) {
 	u := struct {
 		state.ChainHeader
@@ -1998,6 +3659,54 @@ func (v *SigSpecGroup) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *StateProof) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Height     int64   `json:"height,omitempty"`
+		Entry      Receipt `json:"entry,omitempty"`
+		Anchor     Receipt `json:"anchor,omitempty"`
+		AnchorHead *string `json:"anchorHead,omitempty"`
+		State      Receipt `json:"state,omitempty"`
+	}{}
+	u.Height = v.Height
+	u.Entry = v.Entry
+	u.Anchor = v.Anchor
+	u.AnchorHead = encoding.BytesToJSON(v.AnchorHead)
+	u.State = v.State
+	return json.Marshal(&u)
+}
+
+func (v *SwapAccept) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Maker     string `json:"maker,omitempty"`
+		Offer     string `json:"offer,omitempty"`
+		Recipient string `json:"recipient,omitempty"`
+	}{}
+	u.Maker = v.Maker
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	u.Recipient = v.Recipient
+	return json.Marshal(&u)
+}
+
+func (v *SwapCancel) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Offer string `json:"offer,omitempty"`
+	}{}
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	return json.Marshal(&u)
+}
+
+func (v *SwapExpiry) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Maker   string    `json:"maker,omitempty"`
+		Offer   string    `json:"offer,omitempty"`
+		Expires time.Time `json:"expires,omitempty"`
+	}{}
+	u.Maker = v.Maker
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	u.Expires = v.Expires
+	return json.Marshal(&u)
+}
+
 func (v *SyntheticCreateChain) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Cause  string        `json:"cause,omitempty"`
@@ -2026,6 +3735,28 @@ func (v *SyntheticWriteData) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *TransferRecord) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Txid         string    `json:"txid,omitempty"`
+		TxType       string    `json:"txType,omitempty"`
+		Account      string    `json:"account,omitempty"`
+		Counterparty string    `json:"counterparty,omitempty"`
+		Token        string    `json:"token,omitempty"`
+		Amount       big.Int   `json:"amount,omitempty"`
+		Incoming     bool      `json:"incoming,omitempty"`
+		Time         time.Time `json:"time,omitempty"`
+	}{}
+	u.Txid = encoding.ChainToJSON(v.Txid)
+	u.TxType = v.TxType
+	u.Account = v.Account
+	u.Counterparty = v.Counterparty
+	u.Token = v.Token
+	u.Amount = v.Amount
+	u.Incoming = v.Incoming
+	u.Time = v.Time
+	return json.Marshal(&u)
+}
+
 func (v *TxSynthRef) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Type  uint64 `json:"type,omitempty"`
@@ -2045,10 +3776,12 @@ func (v *UpdateKeyPage) MarshalJSON() ([]byte, error) {
 		Operation KeyPageOperation `json:"operation,omitempty"`
 		Key       *string          `json:"key,omitempty"`
 		NewKey    *string          `json:"newKey,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.Operation = v.Operation
 	u.Key = encoding.BytesToJSON(v.Key)
 	u.NewKey = encoding.BytesToJSON(v.NewKey)
+	u.Limits = v.Limits
 	return json.Marshal(&u)
 }
 
@@ -2070,13 +3803,40 @@ func (v *WriteDataTo) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *AccountMetadata) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Name        string  `json:"name,omitempty"`
+		Description string  `json:"description,omitempty"`
+		LogoHash    *string `json:"logoHash,omitempty"`
+		Website     string  `json:"website,omitempty"`
+	}{}
+	u.Name = v.Name
+	u.Description = v.Description
+	u.LogoHash = encoding.BytesToJSON(v.LogoHash)
+	u.Website = v.Website
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Name = u.Name
+	v.Description = u.Description
+	if x, err := encoding.BytesFromJSON(u.LogoHash); err != nil {
+		return fmt.Errorf("error decoding LogoHash: %w", err)
+	} else {
+		v.LogoHash = x
+	}
+	v.Website = u.Website
+	return nil
+}
+
 func (v *ChainParams) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Data     *string `json:"data,omitempty"`
 		IsUpdate bool    `json:"isUpdate,omitempty"`
+		Metadata string  `json:"metadata,omitempty"`
 	}{}
 	u.Data = encoding.BytesToJSON(v.Data)
 	u.IsUpdate = v.IsUpdate
+	u.Metadata = v.Metadata
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -2086,6 +3846,7 @@ func (v *ChainParams) UnmarshalJSON(data []byte) error {
 		v.Data = x
 	}
 	v.IsUpdate = u.IsUpdate
+	v.Metadata = u.Metadata
 	return nil
 }
 
@@ -2127,17 +3888,54 @@ func (v *DataAccount) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (v *ExternalAnchor) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Height    int64     `json:"height,omitempty"`
+		Root      string    `json:"root,omitempty"`
+		Timestamp time.Time `json:"timestamp,omitempty"`
+		Major     bool      `json:"major,omitempty"`
+		Ledger    string    `json:"ledger,omitempty"`
+		Reference *string   `json:"reference,omitempty"`
+	}{}
+	u.Height = v.Height
+	u.Root = encoding.ChainToJSON(v.Root)
+	u.Timestamp = v.Timestamp
+	u.Major = v.Major
+	u.Ledger = v.Ledger
+	u.Reference = encoding.BytesToJSON(v.Reference)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Height = u.Height
+	if x, err := encoding.ChainFromJSON(u.Root); err != nil {
+		return fmt.Errorf("error decoding Root: %w", err)
+	} else {
+		v.Root = x
+	}
+	v.Timestamp = u.Timestamp
+	v.Major = u.Major
+	v.Ledger = u.Ledger
+	if x, err := encoding.BytesFromJSON(u.Reference); err != nil {
+		return fmt.Errorf("error decoding Reference: %w", err)
+	} else {
+		v.Reference = x
+	}
+	return nil
+}
+
 func (v *IdentityCreate) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Url         string  `json:"url,omitempty"`
 		PublicKey   *string `json:"publicKey,omitempty"`
 		KeyBookName string  `json:"keyBookName,omitempty"`
 		KeyPageName string  `json:"keyPageName,omitempty"`
+		Metadata    string  `json:"metadata,omitempty"`
 	}{}
 	u.Url = v.Url
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
 	u.KeyBookName = v.KeyBookName
 	u.KeyPageName = v.KeyPageName
+	u.Metadata = v.Metadata
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -2149,16 +3947,19 @@ func (v *IdentityCreate) UnmarshalJSON(data []byte) error {
 	}
 	v.KeyBookName = u.KeyBookName
 	v.KeyPageName = u.KeyPageName
+	v.Metadata = u.Metadata
 	return nil
 }
 
 func (v *KeySpec) UnmarshalJSON(data []byte) error {
 	u := struct {
-		PublicKey *string `json:"publicKey,omitempty"`
-		Nonce     uint64  `json:"nonce,omitempty"`
+		PublicKey *string          `json:"publicKey,omitempty"`
+		Nonce     uint64           `json:"nonce,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
 	u.Nonce = v.Nonce
+	u.Limits = v.Limits
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -2168,14 +3969,17 @@ func (v *KeySpec) UnmarshalJSON(data []byte) error {
 		v.PublicKey = x
 	}
 	v.Nonce = u.Nonce
+	v.Limits = u.Limits
 	return nil
 }
 
 func (v *KeySpecParams) UnmarshalJSON(data []byte) error {
 	u := struct {
-		PublicKey *string `json:"publicKey,omitempty"`
+		PublicKey *string          `json:"publicKey,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.PublicKey = encoding.BytesToJSON(v.PublicKey)
+	u.Limits = v.Limits
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -2184,6 +3988,38 @@ func (v *KeySpecParams) UnmarshalJSON(data []byte) error {
 	} else {
 		v.PublicKey = x
 	}
+	v.Limits = u.Limits
+	return nil
+}
+
+func (v *KeySpendLimit) UnmarshalJSON(data []byte) error {
+	u := struct {
+		TokenUrl     string      `json:"tokenUrl,omitempty"`
+		MaxPerTx     uint64      `json:"maxPerTx,omitempty"`
+		MaxPerPeriod uint64      `json:"maxPerPeriod,omitempty"`
+		Period       interface{} `json:"period,omitempty"`
+		WindowStart  time.Time   `json:"windowStart,omitempty"`
+		WindowSpent  uint64      `json:"windowSpent,omitempty"`
+	}{}
+	u.TokenUrl = v.TokenUrl
+	u.MaxPerTx = v.MaxPerTx
+	u.MaxPerPeriod = v.MaxPerPeriod
+	u.Period = encoding.DurationToJSON(v.Period)
+	u.WindowStart = v.WindowStart
+	u.WindowSpent = v.WindowSpent
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.TokenUrl = u.TokenUrl
+	v.MaxPerTx = u.MaxPerTx
+	v.MaxPerPeriod = u.MaxPerPeriod
+	if x, err := encoding.DurationFromJSON(u.Period); err != nil {
+		return fmt.Errorf("error decoding Period: %w", err)
+	} else {
+		v.Period = x
+	}
+	v.WindowStart = u.WindowStart
+	v.WindowSpent = u.WindowSpent
 	return nil
 }
 
@@ -2206,6 +4042,25 @@ func (v *LiteDataAccount) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (v *MetadataQueryResult) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Url  string  `json:"url,omitempty"`
+		Data *string `json:"data,omitempty"`
+	}{}
+	u.Url = v.Url
+	u.Data = encoding.BytesToJSON(v.Data)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Url = u.Url
+	if x, err := encoding.BytesFromJSON(u.Data); err != nil {
+		return fmt.Errorf("error decoding Data: %w", err)
+	} else {
+		v.Data = x
+	}
+	return nil
+}
+
 func (v *MetricsRequest) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Metric   string      `json:"metric,omitempty"`
@@ -2225,6 +4080,51 @@ func (v *MetricsRequest) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (v *Receipt) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Element string         `json:"element,omitempty"`
+		Anchor  string         `json:"anchor,omitempty"`
+		Nodes   []*ReceiptNode `json:"nodes,omitempty"`
+	}{}
+	u.Element = encoding.ChainToJSON(v.Element)
+	u.Anchor = encoding.ChainToJSON(v.Anchor)
+	u.Nodes = v.Nodes
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.ChainFromJSON(u.Element); err != nil {
+		return fmt.Errorf("error decoding Element: %w", err)
+	} else {
+		v.Element = x
+	}
+	if x, err := encoding.ChainFromJSON(u.Anchor); err != nil {
+		return fmt.Errorf("error decoding Anchor: %w", err)
+	} else {
+		v.Anchor = x
+	}
+	v.Nodes = u.Nodes
+	return nil
+}
+
+func (v *ReceiptNode) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Right bool    `json:"right,omitempty"`
+		Hash  *string `json:"hash,omitempty"`
+	}{}
+	u.Right = v.Right
+	u.Hash = encoding.BytesToJSON(v.Hash)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Right = u.Right
+	if x, err := encoding.BytesFromJSON(u.Hash); err != nil {
+		return fmt.Errorf("error decoding Hash: %w", err)
+	} else {
+		v.Hash = x
+	}
+	return nil
+}
+
 func (v *SigSpecGroup) UnmarshalJSON(data []byte) error {
 	u := struct {
 		state.ChainHeader
@@ -2244,6 +4144,94 @@ func (v *SigSpecGroup) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (v *StateProof) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Height     int64   `json:"height,omitempty"`
+		Entry      Receipt `json:"entry,omitempty"`
+		Anchor     Receipt `json:"anchor,omitempty"`
+		AnchorHead *string `json:"anchorHead,omitempty"`
+		State      Receipt `json:"state,omitempty"`
+	}{}
+	u.Height = v.Height
+	u.Entry = v.Entry
+	u.Anchor = v.Anchor
+	u.AnchorHead = encoding.BytesToJSON(v.AnchorHead)
+	u.State = v.State
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Height = u.Height
+	v.Entry = u.Entry
+	v.Anchor = u.Anchor
+	if x, err := encoding.BytesFromJSON(u.AnchorHead); err != nil {
+		return fmt.Errorf("error decoding AnchorHead: %w", err)
+	} else {
+		v.AnchorHead = x
+	}
+	v.State = u.State
+	return nil
+}
+
+func (v *SwapAccept) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Maker     string `json:"maker,omitempty"`
+		Offer     string `json:"offer,omitempty"`
+		Recipient string `json:"recipient,omitempty"`
+	}{}
+	u.Maker = v.Maker
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	u.Recipient = v.Recipient
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Maker = u.Maker
+	if x, err := encoding.ChainFromJSON(u.Offer); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	v.Recipient = u.Recipient
+	return nil
+}
+
+func (v *SwapCancel) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Offer string `json:"offer,omitempty"`
+	}{}
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.ChainFromJSON(u.Offer); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	return nil
+}
+
+func (v *SwapExpiry) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Maker   string    `json:"maker,omitempty"`
+		Offer   string    `json:"offer,omitempty"`
+		Expires time.Time `json:"expires,omitempty"`
+	}{}
+	u.Maker = v.Maker
+	u.Offer = encoding.ChainToJSON(v.Offer)
+	u.Expires = v.Expires
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Maker = u.Maker
+	if x, err := encoding.ChainFromJSON(u.Offer); err != nil {
+		return fmt.Errorf("error decoding Offer: %w", err)
+	} else {
+		v.Offer = x
+	}
+	v.Expires = u.Expires
+	return nil
+}
+
 func (v *SyntheticCreateChain) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Cause  string        `json:"cause,omitempty"`
@@ -2298,6 +4286,43 @@ func (v *SyntheticWriteData) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+func (v *TransferRecord) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Txid         string    `json:"txid,omitempty"`
+		TxType       string    `json:"txType,omitempty"`
+		Account      string    `json:"account,omitempty"`
+		Counterparty string    `json:"counterparty,omitempty"`
+		Token        string    `json:"token,omitempty"`
+		Amount       big.Int   `json:"amount,omitempty"`
+		Incoming     bool      `json:"incoming,omitempty"`
+		Time         time.Time `json:"time,omitempty"`
+	}{}
+	u.Txid = encoding.ChainToJSON(v.Txid)
+	u.TxType = v.TxType
+	u.Account = v.Account
+	u.Counterparty = v.Counterparty
+	u.Token = v.Token
+	u.Amount = v.Amount
+	u.Incoming = v.Incoming
+	u.Time = v.Time
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.ChainFromJSON(u.Txid); err != nil {
+		return fmt.Errorf("error decoding Txid: %w", err)
+	} else {
+		v.Txid = x
+	}
+	v.TxType = u.TxType
+	v.Account = u.Account
+	v.Counterparty = u.Counterparty
+	v.Token = u.Token
+	v.Amount = u.Amount
+	v.Incoming = u.Incoming
+	v.Time = u.Time
+	return nil
+}
+
 func (v *TxSynthRef) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Type  uint64 `json:"type,omitempty"`
@@ -2332,10 +4357,12 @@ func (v *UpdateKeyPage) UnmarshalJSON(data []byte) error {
 		Operation KeyPageOperation `json:"operation,omitempty"`
 		Key       *string          `json:"key,omitempty"`
 		NewKey    *string          `json:"newKey,omitempty"`
+		Limits    []*KeySpendLimit `json:"limits,omitempty"`
 	}{}
 	u.Operation = v.Operation
 	u.Key = encoding.BytesToJSON(v.Key)
 	u.NewKey = encoding.BytesToJSON(v.NewKey)
+	u.Limits = v.Limits
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -2350,6 +4377,7 @@ func (v *UpdateKeyPage) UnmarshalJSON(data []byte) error {
 	} else {
 		v.NewKey = x
 	}
+	v.Limits = u.Limits
 	return nil
 }
 
@@ -118,6 +118,22 @@ func (m *MerkleManager) ReadChainHead(chainID []byte) (ms *MerkleState, err erro
 	return ms, nil
 }
 
+// WriteChainBase
+// Save the head of the chain as the base of its history.  A chain whose head
+// was restored from a snapshot has none of the elements before its head, so
+// VerifyChain replays the chain from its base rather than from the first
+// element.  Does nothing if the chain has no head.
+func (m *MerkleManager) WriteChainBase(chainID []byte) error {
+	state, err := m.Manager.Key(chainID, "Head").Get()
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	m.Manager.Key(chainID, "Base").PutBatch(state)
+	return nil
+}
+
 // Equal
 // Compares the MerkleManager to the given MerkleManager and returns false if
 // the fields in the MerkleManager are different from m2
@@ -4,57 +4,97 @@ import "fmt"
 
 // GetRange
 // returns the list of hashes with indexes indicated by range: (begin,end)
-// begin must be before or equal to end.  The hash with index begin and end
-// are included in the hashes returned.  Indexes are zero based, so the
-// first hash in the MerkleState is at 0
+// begin must be before or equal to end.  The hash with index begin is
+// included in the hashes returned, the hash with index end is not.  Indexes
+// are zero based, so the first hash in the MerkleState is at 0.
+//
+// The hashes are read a mark at a time by ReadRange, so the range can be as
+// long as the chain.
 func (m *MerkleManager) GetRange(ChainID []byte, begin, end int64) (hashes []Hash, err error) {
-	m.SetChainID(ChainID)
+	if err := m.SetChainID(ChainID); err != nil {
+		return nil, err
+	}
 	// We return nothing for ranges that are out of range.
 	if begin < 0 { // begin cannot be negative.  If it is, assume the user meant zero
 		begin = 0
 	}
-	// Limit hashes returned to half the mark frequency.
-	if end-begin > m.MarkFreq/2 {
-		end = begin + m.MarkFreq/2
-	}
 	if m.MS.Count <= begin || 0 > end { // Note count is 1 based, so the index count is out of range
 		return nil, fmt.Errorf("impossible range provided %d,%d", begin, end) // Return zero begin and/or end are impossible
 	}
 	if end >= m.MS.Count { // If end is past the length of MS then truncate the range to count-1
 		end = m.MS.Count // End isn't included, so it can be equal to the Count
 	}
-	markPoint := m.MarkFreq - 1 // markPoint has the list of hashes just prior to the first mark.
-	if begin > m.MarkFreq-1 {   // If begin is past the first mark, calculate it
-		markPoint = (begin)&(^m.MarkMask) - 1
-		markPoint += m.MarkFreq
-	}
-	s := m.GetState(markPoint) // Get the state of the mark right after the begin index
-	var hl []Hash              // Collect all the hashes of the mark points covering the range of begin-end
-	if s == nil {              // If no state found, then get the highest state of the chain
-		head, err := m.ReadChainHead(ChainID) //
-		if err != nil {                       // If we have an error, we will just ignore it.
-			return nil, fmt.Errorf("No State found %x:  %v:err", ChainID, err)
+	if begin >= end { // Just check if we are to return anything
+		return nil, fmt.Errorf("no elements in the range provided")
+	}
+
+	err = m.readRange(begin, end, func(_ int64, h []Hash) error {
+		hashes = append(hashes, h...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
+
+// ReadRange
+// Calls fn with the hashes with indexes from begin up to but not including
+// end, in order, a mark at a time.  Each call gets the index of its first
+// hash.  The parts of the range outside the chain are ignored, so the range
+// may be empty.  If fn returns an error, ReadRange stops and returns it.
+//
+// Each complete mark is read from the state stored at its end, and the last
+// partial mark from the head of the chain, so a range costs a read per mark
+// rather than a read per element.  Elements are read one at a time only if
+// those states do not hold them.
+func (m *MerkleManager) ReadRange(ChainID []byte, begin, end int64, fn func(index int64, hashes []Hash) error) error {
+	if err := m.SetChainID(ChainID); err != nil {
+		return err
+	}
+	return m.readRange(begin, end, fn)
+}
+
+// readRange
+// Implements ReadRange on the chain that has been set
+func (m *MerkleManager) readRange(begin, end int64, fn func(index int64, hashes []Hash) error) error {
+	head := m.MS
+	if begin < 0 {
+		begin = 0
+	}
+	if end > head.Count {
+		end = head.Count
+	}
+	for begin < end {
+		first := begin &^ m.MarkMask //              The index of the first element of the mark
+		last := first + m.MarkFreq   //              and of the element after its end
+		if last > end {
+			last = end
 		}
-		hl = append(hl, head.HashList...)
-	} else { // If a mark follows begin, then
-		hl = append(hl, s.HashList...)
-		s = m.GetState(markPoint + m.MarkFreq) // Get the next state
-		if s != nil {
-			hl = append(hl, s.HashList...)
-		} else {
-			head, err := m.ReadChainHead(ChainID)
-			if err != nil { // If we have an error, we will just ignore it.
-				return nil, fmt.Errorf("No State found %x:  %v:err", ChainID, err)
+
+		var hashes []Hash
+		if first+m.MarkFreq <= head.Count { //       The mark is complete, so its state holds its elements
+			s := m.GetState(first + m.MarkFreq - 1)
+			if s != nil && int64(len(s.HashList)) == m.MarkFreq {
+				hashes = s.HashList[begin-first : last-first]
+			}
+		} else if int64(len(head.HashList)) == head.Count-first { //   The head holds the elements of the last mark
+			hashes = append([]Hash{}, head.HashList[begin-first:last-first]...)
+		}
+		if hashes == nil { //                        Fall back to reading each element
+			for i := begin; i < last; i++ {
+				h, err := m.Get(i)
+				if err != nil {
+					return fmt.Errorf("failed to read element %d: %w", i, err)
+				}
+				hashes = append(hashes, h)
 			}
-			hl = append(hl, head.HashList...)
 		}
-	}
 
-	if len(hl) > 0 && begin != end { // Just check if we are to return anything
-		first := (begin) & m.MarkMask // Calculate the offset to the beginning of the range
-		last := first + end - begin   // and to the end of the range
-		return hl[first:last], nil    // Return this slice.
+		if err := fn(begin, hashes); err != nil {
+			return err
+		}
+		begin = last
 	}
-
-	return nil, fmt.Errorf("no elements in the range provided")
+	return nil
 }
@@ -124,3 +124,72 @@ func TestMerkleManager_GetRange(t *testing.T) {
 		}
 	}
 }
+
+func TestMerkleManager_ReadRange(t *testing.T) {
+	const markPower = 3
+	const markFreq = 1 << markPower
+	const count = 8*markFreq + 5
+	chainID := []byte{1}
+
+	dbManager, err := database.NewDBManager("memory", "")
+	require.NoError(t, err)
+	mm, err := NewMerkleManager(dbManager, markPower)
+	require.NoError(t, err)
+	require.NoError(t, mm.SetChainID(chainID))
+	for i := int64(0); i < count; i++ {
+		mm.AddHash(i2b(i))
+	}
+	dbManager.EndBatch()
+
+	read := func(begin, end int64) (hashes []Hash, calls int) {
+		next := begin
+		if next < 0 {
+			next = 0
+		}
+		err := mm.ReadRange(chainID, begin, end, func(index int64, h []Hash) error {
+			require.Equal(t, next, index, "batches must be contiguous")
+			next += int64(len(h))
+			require.LessOrEqual(t, len(h), markFreq, "a batch must not span marks")
+			hashes = append(hashes, h...)
+			calls++
+			return nil
+		})
+		require.NoError(t, err)
+		return hashes, calls
+	}
+	check := func(begin, end int64) {
+		hashes, _ := read(begin, end)
+		if begin < 0 {
+			begin = 0
+		}
+		if end > count {
+			end = count
+		}
+		if end < begin {
+			end = begin
+		}
+		require.Len(t, hashes, int(end-begin), "range (%d,%d)", begin, end)
+		for i, h := range hashes {
+			require.Equal(t, begin+int64(i), b2i(h), "range (%d,%d)", begin, end)
+		}
+	}
+
+	for begin := int64(-1); begin <= count+1; begin++ {
+		for end := begin; end <= count+2; end++ {
+			check(begin, end)
+		}
+	}
+
+	// One batch per mark
+	_, calls := read(0, count)
+	require.Equal(t, count/markFreq+1, calls)
+
+	// Elements whose mark state is missing are read one at a time
+	require.NoError(t, dbManager.Key(chainID, "States", int64(2*markFreq-1)).Delete())
+	check(markFreq-2, 3*markFreq+1)
+
+	// GetRange is not limited to a mark
+	hashes, err := mm.GetRange(chainID, 1, count)
+	require.NoError(t, err)
+	require.Len(t, hashes, count-1)
+}
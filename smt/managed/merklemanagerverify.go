@@ -0,0 +1,134 @@
+package managed
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// ChainError
+// Reports the first index at which a chain does not match what AddHash would
+// have written.  An Index equal to the number of elements of the chain means
+// the elements are consistent but the head of the chain is not.
+type ChainError struct {
+	ChainID []byte // The chain
+	Index   int64  // The index of the first inconsistent element
+	Problem string // What is wrong at that index
+}
+
+// Error
+// Describes the inconsistency
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("chain %X is inconsistent at index %d: %s", e.ChainID, e.Index, e.Problem)
+}
+
+// VerifyChain
+// Replays every element of a chain into a fresh MerkleState, or into the base
+// of the chain if it has one, and checks the records of the chain as it goes:
+// the ElementIndex of each element, the States and NextElement stored at each
+// mark, and finally the Head of the chain.  Returns a *ChainError for the
+// first inconsistent index, or another error if the database cannot be read.
+// The MerkleManager is not changed.
+func (m *MerkleManager) VerifyChain(ChainID []byte) error {
+	fail := func(index int64, format string, args ...interface{}) error {
+		return &ChainError{ChainID: ChainID, Index: index, Problem: fmt.Sprintf(format, args...)}
+	}
+
+	head := new(MerkleState) //                                  A missing head is an empty chain
+	data, err := m.Manager.Key(ChainID, "Head").Get()
+	if err == nil {
+		err = head.UnMarshal(data)
+	} else if errors.Is(err, storage.ErrNotFound) {
+		err = nil
+	} else {
+		return fmt.Errorf("failed to load the head of chain %X: %v", ChainID, err)
+	}
+	headErr := err //                                            If the head is corrupt, still check the elements
+
+	// A chain restored from a snapshot starts at its base
+	ms := new(MerkleState)
+	ms.InitSha256()
+	data, err = m.Manager.Key(ChainID, "Base").Get()
+	if err == nil {
+		if err = ms.UnMarshal(data); err != nil {
+			return fail(0, "the base is corrupt: %v", err)
+		}
+	} else if !errors.Is(err, storage.ErrNotFound) {
+		return fmt.Errorf("failed to load the base of chain %X: %v", ChainID, err)
+	}
+	for i := ms.Count; ; i++ {
+		element, err := m.Manager.Key(ChainID, "Element", i).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			if headErr == nil && i < head.Count {
+				return fail(i, "the element is missing but the head has %d elements", head.Count)
+			}
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to load element %d of chain %X: %v", i, ChainID, err)
+		}
+		if headErr == nil && i >= head.Count {
+			return fail(i, "the element is past the head, which has %d elements", head.Count)
+		}
+
+		// The index of an element is the index of its first instance
+		data, err := m.Manager.Key(ChainID, "ElementIndex", element).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			return fail(i, "the element %X is not indexed", element)
+		} else if err != nil {
+			return fmt.Errorf("failed to load the index of element %d of chain %X: %v", i, ChainID, err)
+		}
+		index, _ := common.BytesInt64(data)
+		if index > i {
+			return fail(i, "the element %X is indexed at %d", element, index)
+		}
+		if index < i {
+			first, err := m.Manager.Key(ChainID, "Element", index).Get()
+			if err != nil && !errors.Is(err, storage.ErrNotFound) {
+				return fmt.Errorf("failed to load element %d of chain %X: %v", index, ChainID, err)
+			}
+			if !bytes.Equal(first, element) {
+				return fail(i, "the element %X is indexed at %d, which holds %X", element, index, first)
+			}
+		}
+
+		ms.AddToMerkleTree(element)
+		if i&m.MarkMask != m.MarkMask {
+			continue
+		}
+
+		// At a mark the state after adding the element is stored, with the
+		// elements added since the last mark
+		data, err = m.Manager.Key(ChainID, "States", i).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			return fail(i, "the mark state is missing")
+		} else if err != nil {
+			return fmt.Errorf("failed to load the mark state %d of chain %X: %v", i, ChainID, err)
+		}
+		state := new(MerkleState)
+		if err = state.UnMarshal(data); err != nil {
+			return fail(i, "the mark state is corrupt: %v", err)
+		}
+		if !state.Equal(ms) {
+			return fail(i, "the mark state does not match the elements")
+		}
+		next, err := m.Manager.Key(ChainID, "NextElement", i).Get()
+		if err != nil && !errors.Is(err, storage.ErrNotFound) {
+			return fmt.Errorf("failed to load the next element %d of chain %X: %v", i, ChainID, err)
+		}
+		if !bytes.Equal(next, element) {
+			return fail(i, "the next element of the mark is %X, not %X", next, element)
+		}
+		ms.HashList = ms.HashList[:0]
+	}
+
+	if headErr != nil {
+		return fail(ms.Count, "the head is corrupt: %v", headErr)
+	}
+	if !head.Equal(ms) {
+		return fail(ms.Count, "the head does not match the elements")
+	}
+	return nil
+}
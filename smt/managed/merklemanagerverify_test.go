@@ -0,0 +1,89 @@
+package managed
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMerkleManager_VerifyChain(t *testing.T) {
+	const markPower = 3
+	const markFreq = 1 << markPower
+	const count = 3*markFreq + 3
+	chainID := []byte{1}
+
+	build := func() (*MerkleManager, *database.Manager) {
+		dbManager, err := database.NewDBManager("memory", "")
+		require.NoError(t, err)
+		mm, err := NewMerkleManager(dbManager, markPower)
+		require.NoError(t, err)
+		require.NoError(t, mm.SetChainID(chainID))
+		for i := int64(0); i < count; i++ {
+			mm.AddHash(i2b(i % (count - 2))) // The last two elements repeat the first two
+		}
+		dbManager.EndBatch()
+		require.NoError(t, mm.VerifyChain(chainID))
+		return mm, dbManager
+	}
+	firstBad := func(mm *MerkleManager) int64 {
+		err := mm.VerifyChain(chainID)
+		var cerr *ChainError
+		require.True(t, errors.As(err, &cerr), "expected a chain error, got %v", err)
+		return cerr.Index
+	}
+
+	mm, db := build()
+	require.NoError(t, db.Key(chainID, "Element", int64(markFreq+2)).Delete())
+	require.Equal(t, int64(markFreq+2), firstBad(mm), "missing element")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "Element", int64(5)).Put(i2b(1000)))
+	require.Equal(t, int64(5), firstBad(mm), "changed element")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "ElementIndex", i2b(4)).Put(common.Int64Bytes(6)))
+	require.Equal(t, int64(4), firstBad(mm), "wrong element index")
+
+	mm, db = build()
+	state, err := db.Key(chainID, "States", int64(markFreq-1)).Get()
+	require.NoError(t, err)
+	require.NoError(t, db.Key(chainID, "States", int64(2*markFreq-1)).Put(state))
+	require.Equal(t, int64(2*markFreq-1), firstBad(mm), "wrong mark state")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "NextElement", int64(3*markFreq-1)).Delete())
+	require.Equal(t, int64(3*markFreq-1), firstBad(mm), "missing next element")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "Element", int64(count)).Put(i2b(count)))
+	require.Equal(t, int64(count), firstBad(mm), "element past the head")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "Head").Put(state))
+	require.Equal(t, int64(markFreq), firstBad(mm), "head behind the elements")
+
+	mm, db = build()
+	require.NoError(t, db.Key(chainID, "Head").Put([]byte{1, 2, 3}))
+	require.Equal(t, int64(count), firstBad(mm), "corrupt head")
+
+	// A chain restored from its head is verified from its base
+	mm, db = build()
+	head, err := db.Key(chainID, "Head").Get()
+	require.NoError(t, err)
+	restored, err := database.NewDBManager("memory", "")
+	require.NoError(t, err)
+	require.NoError(t, restored.Key(chainID, "Head").Put(head))
+	mm, err = NewMerkleManager(restored, markPower)
+	require.NoError(t, err)
+	require.Equal(t, int64(0), firstBad(mm), "restored without a base")
+	require.NoError(t, mm.WriteChainBase(chainID))
+	require.NoError(t, mm.SetChainID(chainID))
+	for i := int64(0); i < markFreq; i++ {
+		mm.AddHash(i2b(count + i))
+	}
+	restored.EndBatch()
+	require.NoError(t, mm.VerifyChain(chainID))
+}
@@ -3,7 +3,6 @@ package managed
 import (
 	"bytes"
 	"fmt"
-	"math"
 )
 
 type Node struct {
@@ -19,187 +18,88 @@ type Receipt struct {
 	Nodes   []*Node // Apply these hashes to create an anchor
 }
 
-// GetElementState
-// Looks through the Merkle Tree, and finds the state just before the element has been added to the
-// Merkle Tree, and the height in the Pending list of the derivative of the element.
-// Returns a nil for the merkleState if the element is not in the Merkle Tree
-func GetElementState(
-	manager *MerkleManager, //                       Parameters
-	element Hash,
-) (
-	currentState, nextMark *MerkleState, //          Return values
-	height int,
-	nextMarkIndex int64) {
-
-	elementIndex, err := manager.GetElementIndex(element[:])
-	if err != nil {
-		return nil, nil, -1, -1
-	}
-	// currentState is the state we are going to modify until it is the state we want
-	currentIndex := elementIndex & (manager.MarkMask ^ -1) // Find the mark previous to element (current if at mark)
-	if currentIndex == 0 {                                 // Get that state
-		currentState = manager.GetState(0)
+// stateAt
+// Returns the state of the Merkle Tree once count elements have been added to it.  The state is
+// built from the last mark at or before count, and the elements that follow that mark are added to it.
+// Returns nil if the state cannot be built.
+func stateAt(manager *MerkleManager, count int64) *MerkleState {
+	markCount := count &^ manager.MarkMask // Number of elements at the mark at or before count
+	var state *MerkleState                 // The state at a mark is saved at the index of the
+	if markCount == 0 {                    // element that completes the mark
+		state = new(MerkleState)
 	} else {
-		currentState = manager.GetState(currentIndex - 1)
-	}
-	currentState.InitSha256()                           // Use sha256
-	nextMarkIndex = currentIndex + manager.MarkFreq - 1 // Next mark has the list of elements to add
-	nextMark = manager.GetState(nextMarkIndex)          // Get that NextMark state. Now bracket element, search
-	if nextMark == nil {                                // If there is no state at the next mark,
-		nextMark = manager.MS.Copy() //                        then just use the last state of the Merkle Tree
-	}
-	for i, v1 := range nextMark.HashList { //                Go through the pending elements
-		nextMarkIndex = int64(i)      //                           Return location in this HashList for next step
-		if bytes.Equal(v1, element) { //                           If the element is found
-			return currentState, nextMark, height, int64(i) // Return location so following code can reuse it
+		state = manager.GetState(markCount - 1)
+		if state == nil {
+			return nil
 		}
-		currentState.AddToMerkleTree(v1) //                   Otherwise, just add value to merkle tree and keep looking
 	}
-	panic("the element must be a member of the NextMark.HashList")
-}
-
-// AdvanceToMark
-// Advance the currentState (from any element) to the next Mark.
-// Receipt is updated, where our proof is expected to be at height in the currentState
-// If advancing to the next mark would take the currentState past the AnchorState, then
-// AdvanceToMark only advances to the anchorState and returns true.
-//
-// Returns true (that currentState is now the anchorState) or false
-func (r *Receipt) AdvanceToMark(
-	manager *MerkleManager,
-	currentState, nextMark, anchorState *MerkleState,
-	height int,
-	markIndex int64,
-) (
-	atAnchor bool,
-	newHeight int) {
-
-	// Add all the hashes that remain in the HashList of the next Mark to the current state.
-	// The result will be a current state that is at the next mark.
-	for i, v := range nextMark.HashList[markIndex:] {
-		if currentState.Count == anchorState.Count { //           Sort to see if reached the anchor state
-			return true, height //                                 If at the anchor, signal currentState is at the anchor
-		}
-		if i == 0 && //                                           If adding the first hash of the hash list
-			currentState.Count&1 == 1 { //                         And at an odd number of elements in the Merkle Tree
-			height = r.AddAHash(currentState, height, false, v) // In that case, the hash will come from the left
-		} else { //                                                    Otherwise
-			height = r.AddAHash(currentState, height, true, v) //  the hash will come from the right
+	state.InitSha256()
+	for i := markCount; i < count; i++ { // Add the elements past the mark
+		hash, err := manager.Get(i)
+		if err != nil {
+			return nil
 		}
+		state.AddToMerkleTree(hash)
 	}
-	// If reaching the next mark doesn't reach the anchor state, signal that the search continues
-	return false, height
+	state.HashList = state.HashList[:0] // The HashList is of no use to the receipt
+	return state
 }
 
-// AdvanceMarkToMark
-// Once the currentState is at a Mark, it can be advanced directly to the next Mark.
-// The next Mark is a mark at a 2^n step forward through the elements. These states
-// can be combined directly without processing all the intermediate elements.
+// addHash
+// Adds a hash to the state, where the derivative of the element being proven is at height in the
+// pending list of the state.  If the hash is the element itself, then derivative must be true.  Any
+// combination with the derivative of the element is recorded in the receipt.
 //
-// Returns true (that the currentState is now the anchorState) or false
-func (r *Receipt) AdvanceMarkToMark(manager *MerkleManager, anchorState, currentState *MerkleState, height int) (
-	atAnchor bool, newHeight int) {
-	// Calculate the number of elements from the currentState at height to the next Mark State
-	markStep := int64(math.Pow(2, float64(height)))
-	// If the anchorState is between the currentState and the next MarkState, then none of the pending
-	// entries in the AnchorState will contribute to the receipt, so we can build our proof from the
-	// anchorStep directly.
-	if currentState.Count+markStep > anchorState.Count {
-		currentState = anchorState.Copy()
-		return true, height
-	}
-	// Set the currentState to the state just before the next mark.  None of the intermediate elements
-	// will contribute to the receipt, so they don't heave to be evaluated.  But the next element
-	// right before the mark does contribute.
-	currentState = manager.GetState(currentState.Count + markStep - 1)
-	// markNext is the next element to be added to the Merkle Tree.  It WILL carry into height, so
-	// we have to use AddAHash to add its contribution to the receipt.
-	markNext := manager.GetNext(currentState.Count + markStep - 1) // Get the the next element
-	height = r.AddAHash(currentState, height, true, markNext)      // Record its impact on the receipt
-	// Handle the special case where the Mark we jump to == the AnchorState
-	if currentState.Count == anchorState.Count {
-		return true, height
-	}
-	return false, height
-}
-
-func (r *Receipt) AddAHash(
-	CurrentState *MerkleState,
-	Height int,
-	Right bool,
-	v1 Hash,
-) (
-	height int) {
-	original := v1
-	CurrentState.PadPending() // Pending always has to end in a nil to ensure we handle the "carry" case
-	for j, v2 := range CurrentState.Pending {
-		if v2 == nil {
-			// If we find a nil spot, then we found where the hash will go.  To keep
-			// the accounting square, we won't add it ourselves, but will let the Merkle Tree
-			// library do the deed.  That keeps the Merkle Tree State square.
-			CurrentState.AddToMerkleTree(original)
-			if j == Height { // If we combine with our proof height, the NEXT combination will be
-				Right = true // from the right.
-			}
-			return Height
+// Returns the height of the derivative of the element once the hash has been added.
+func (r *Receipt) addHash(state *MerkleState, height int, derivative bool, hash Hash) int {
+	state.PadPending()
+	carry := hash
+	for j, v := range state.Pending {
+		if v == nil { //                               The carry stops here
+			break
 		}
-		// If this is the creation of a higher derivative of object, put it in our path
-		if j == Height {
+		if j == height { //                            The carry combines with the derivative
 			node := new(Node)
 			r.Nodes = append(r.Nodes, node)
-			node.Right = Right
-			if Right {
-				node.Hash = v1
-			} else {
-				node.Hash = v2
+			if derivative { //                         The carry is the derivative, so the
+				node.Hash = v //                       pending hash comes from the left
+			} else { //                                The pending hash is the derivative, so
+				node.Right = true //                   the carry comes from the right
+				node.Hash = carry
 			}
-			//fmt.Printf("Height %3d %x\n", Height, node.Hash)
-			// v1 becomes HashOf(pending[j]+v1)
-			Height++      // The Anchor will now move up one level in the Merkle Tree
-			Right = false // The Anchor hashes on the left are now added to the Anchor
+			derivative = true
+			height++
 		}
-		// v1 becomes HashOf(pending[j]+v1)
-		v1 = CurrentState.HashFunction(append(v2[:], v1[:]...))
+		carry = v.Combine(state.HashFunction, carry)
 	}
-	panic("failed to add the hash to the current state")
+	state.AddToMerkleTree(hash)
+	return height
 }
 
-func (r *Receipt) ComputeDag(manager *MerkleManager, currentState *MerkleState, height int, right bool) {
-	var anchor Hash
-	for i, v := range currentState.Pending {
-		if v == nil { // if v is nil, there is nothing to do regardless. Note i cannot == Height
-			continue // because the previous code tracks Height such that there is always a value.
-		}
-		if anchor == nil { // Find the first non nil in pending
-			anchor = v.Copy() // Copy the anchor
-			if i == height {  // Note that there is no way this code does not
-				right = false //      execute before the following code that adds
-			} else { //               applyHash records to the receipt
-				right = true
-			}
+// computeAnchor
+// Adds to the receipt the nodes that lead from the derivative of the element at height in the
+// pending list of the state to the root of the state, and sets the anchor to that root.
+func (r *Receipt) computeAnchor(state *MerkleState, height int) {
+	var lower Hash
+	for _, v := range state.Pending[:height] { // Hashes below the derivative combine to the right of it
+		if v == nil {
 			continue
 		}
-		if i >= height { // At this point, we have a hash in the anchor, and we are
-			node := new(Node) // combining hashes as we go.
-			r.Nodes = append(r.Nodes, node)
-			node.Right = right // We record if the proof path is in the anchor
-			if right {         // or in the pending array
-				node.Hash = anchor
-			} else {
-				node.Hash = v
-			}
-			// Note once the object derivative is in anchor, it never leaves. All combining
-			right = false // then comes from the left
-			// Combine all the pending hash values into the anchor
-			anchor = v.Combine(currentState.HashFunction, anchor)
+		if lower == nil {
+			lower = v
+		} else {
+			lower = v.Combine(state.HashFunction, lower)
 		}
 	}
-	// We are testing anchor, but it can't be nil
-	if anchor == nil {
-		panic("anchor was nil, and this should not be possible.")
+	if lower != nil {
+		r.Nodes = append(r.Nodes, &Node{Right: true, Hash: lower.Copy()})
 	}
-	// Put the resulting anchor into the receipt
-	r.Anchor = anchor
+	for _, v := range state.Pending[height+1:] { // Hashes above the derivative combine to the left of it
+		if v != nil {
+			r.Nodes = append(r.Nodes, &Node{Hash: v.Copy()})
+		}
+	}
+	r.Anchor = state.GetMDRoot()
 }
 
 // String
@@ -226,44 +126,59 @@ func (r *Receipt) String() string {
 // Given a merkle tree and two elements, produce a proof that the element was used to derive the DAG at the anchor
 // Note that the element must be added to the Merkle Tree before the anchor, but the anchor can be any element
 // after the element, or even the element itself.
+//
+// The proof is built by walking the derivative of the element up the Merkle Tree.  The derivative at height h
+// only changes when the next 2^h elements have been added, so the walk jumps from one such point to the next
+// using the states saved at marks rather than adding every element.
 func GetReceipt(manager *MerkleManager, element Hash, anchor Hash) *Receipt {
-	elementIndex, e1 := manager.GetElementIndex(element[:]) // Get the index of the element in the Merkle Tree; -1 if not found
-	anchorIndex, e2 := manager.GetElementIndex(anchor[:])   // Get the index of the anchor in the Merkle Tree; -1 if not found
+	elementIndex, e1 := manager.GetElementIndex(element[:]) // Get the index of the element in the Merkle Tree
+	anchorIndex, e2 := manager.GetElementIndex(anchor[:])   // Get the index of the anchor in the Merkle Tree
 	if e1 != nil || e2 != nil {                             // Both the element and the anchor must be in the Merkle Tree
 		return nil
 	}
 	if elementIndex > anchorIndex { // The element must be at the anchorIndex or before
 		return nil
 	}
+	anchorCount := anchorIndex + 1 // The number of elements in the Merkle Tree once the anchor is added
 
-	// Get the merkle state at the point where the anchor has been added to the Merkle tree.
-	// Note that getting the ElementState occurs one state BEFORE the anchor was added, so
-	// we will have to add the anchor
-	anchorState, _, _, _ := GetElementState(manager, anchor) // Get the state before the anchor is added,
-	anchorState.AddToMerkleTree(anchor)                      //  and add the anchor
-
-	// Get the state just before the element is added to the merkle tree
-	currentState, nextMark, height, nextMarkIndex := GetElementState(manager, element)
-	// Allocate our receipt and record our element
-	receipt := new(Receipt)   // Allocate a receipt
-	receipt.Element = element // Add the element to the receipt
-	// If the current state is the anchor state, then we are done.
-	if currentState.Count == anchorState.Count {
-		receipt.ComputeDag(manager, anchorState, height, true) // Compute the DAG and return
-		return receipt
+	receipt := new(Receipt)                 // Allocate a receipt
+	receipt.Element = element.Copy()        // Add the element to the receipt
+	state := stateAt(manager, elementIndex) // Get the state just before the element is added
+	if state == nil {
+		return nil
 	}
-	var atAnchor bool // Advance the current state to the first Mark at or past the current State
-	atAnchor, height = receipt.AdvanceToMark(manager, currentState, nextMark, anchorState, height, nextMarkIndex)
-	if atAnchor { // If we advanced the current state and the result is the Anchor State, then we are done
-		receipt.ComputeDag(manager, anchorState, height, true)
-		return receipt
+	height := receipt.addHash(state, 0, true, element)
+
+	for {
+		// The derivative at height next combines once the 2^height elements that follow it are added
+		next := state.Count&^(1<<height-1) + 1<<height
+		if next > anchorCount {
+			break
+		}
+		// Skip ahead to just before the element that completes the right sibling of the
+		// derivative. The elements before it do not contribute to the receipt.
+		if state.Count != next-1 {
+			state = stateAt(manager, next-1)
+			if state == nil {
+				return nil
+			}
+		}
+		hash, err := manager.Get(next - 1)
+		if err != nil {
+			return nil
+		}
+		height = receipt.addHash(state, height, false, hash)
 	}
-	for { // Push the Current State from mark to mark, at ever higher powers of 2 until we reach the anchor state
-		if atAnchor, height = receipt.AdvanceMarkToMark(manager, currentState, anchorState, height); atAnchor == true {
-			receipt.ComputeDag(manager, currentState, height, true) // We reach the anchor state, we are done
-			return receipt
+
+	if state.Count != anchorCount { // Bring the state up to the anchor
+		state = stateAt(manager, anchorCount)
+		if state == nil {
+			return nil
 		}
 	}
+	state.PadPending()
+	receipt.computeAnchor(state, height)
+	return receipt
 }
 
 // Receipt
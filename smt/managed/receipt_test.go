@@ -84,10 +84,15 @@ func TestReceiptAll(t *testing.T) {
 	if err != nil {
 		t.Fatalf("did not create a merkle manager: %v", err)
 	}
-	// populate the database
+	// populate the database, and keep the root of the Merkle Tree as each element is added
+	roots := make([]Hash, testMerkleTreeSize)
+	ms := new(MerkleState)
+	ms.InitSha256()
 	for i := 0; i < testMerkleTreeSize; i++ {
 		v := GetHash(i)
 		manager.AddHash(v)
+		ms.AddToMerkleTree(v)
+		roots[i] = ms.GetMDRoot()
 	}
 
 	for i := -10; i < testMerkleTreeSize+10; i++ {
@@ -109,6 +114,9 @@ func TestReceiptAll(t *testing.T) {
 				if !r.Validate() {
 					t.Fatal("Receipt fails for element ", i, " anchor ", j)
 				}
+				if !r.Anchor.Equal(roots[j]) {
+					t.Fatal("Receipt does not lead to the root for element ", i, " anchor ", j)
+				}
 			}
 		}
 	}
@@ -12,9 +12,11 @@ import (
 // BPT
 // Binary Patricia Tree.
 // Two types of Entry in the Tree:
-//    Node - a node in a binary tree that ends in Values (Left and Right)
-//    Value - a key / value pair where the key is a ChainID and the value
-//            is the hash of the state of the chain
+//
+//	Node - a node in a binary tree that ends in Values (Left and Right)
+//	Value - a key / value pair where the key is a ChainID and the value
+//	        is the hash of the state of the chain
+//
 // The BPT can be updated many times, then updated in batch (which reduces
 // the hashes that have to be performed to update the summary hash)
 type BPT struct {
@@ -260,6 +262,10 @@ func (b *BPT) Update() {
 	if b.manager != nil { //                             Root doesn't get flushed (has no parent)
 		b.manager.FlushNode(b.Root) //                    So flush it special
 	} //
+	b.DirtyMap = make(map[uint64]*Node) //               Maps don't shrink, so start over with an empty one
+	if b.manager != nil {               //                             Everything is clean, so the cache can be trimmed
+		b.manager.evict() //
+	} //
 }
 
 func (b *BPT) EnsureRootHash() {
@@ -340,7 +346,6 @@ func (b *BPT) MarshalEntry(entry Entry, data []byte) []byte { //
 }
 
 // UnMarshalByteBlock
-//
 func (b *BPT) UnMarshalByteBlock(borderNode *Node, data []byte) []byte {
 	if borderNode.Height&b.mask != 0 {
 		panic("cannot call UnMarshalByteBlock on non-boarder nodes")
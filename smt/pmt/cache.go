@@ -0,0 +1,125 @@
+package pmt
+
+import (
+	"container/list"
+	"sync/atomic"
+)
+
+// CacheStats
+// Counts the Byte Blocks the Manager holds in memory, and how often they are
+// found there.  The counts are updated atomically so they can be read while
+// the BPT is in use.
+type CacheStats struct {
+	Loaded    int64  // Number of Byte Blocks held in memory
+	Hits      uint64 // Number of loads satisfied from memory
+	Misses    uint64 // Number of loads that read the database
+	Evictions uint64 // Number of Byte Blocks unloaded to bound memory
+}
+
+// Snapshot
+// Returns a copy of the counts, read atomically
+func (s *CacheStats) Snapshot() CacheStats {
+	return CacheStats{
+		Loaded:    atomic.LoadInt64(&s.Loaded),
+		Hits:      atomic.LoadUint64(&s.Hits),
+		Misses:    atomic.LoadUint64(&s.Misses),
+		Evictions: atomic.LoadUint64(&s.Evictions),
+	}
+}
+
+// SetCacheSize
+// Sets the number of Byte Blocks, other than the root, kept in memory.  When
+// there are more, the least recently used are unloaded, leaving NotLoaded
+// placeholders that are loaded again on demand.  Zero keeps every Byte Block
+// that is loaded.
+//
+// Only clean Byte Blocks can be unloaded, so the cache is trimmed at the end
+// of Update, and may grow past its size while the BPT is being changed or
+// walked.
+func (m *Manager) SetCacheSize(size int) {
+	m.cacheSize = size
+	m.evict()
+}
+
+// touch
+// Records the border node of a Byte Block as the most recently used
+func (m *Manager) touch(node *Node) {
+	if node == m.Bpt.Root { //                              The root is never unloaded
+		return
+	}
+	if e, ok := m.lru[node.BBKey]; ok {
+		m.lruList.MoveToFront(e)
+		return
+	}
+	m.LoadedBB[node.BBKey] = node
+	m.lru[node.BBKey] = m.lruList.PushFront(node)
+	atomic.AddInt64(&m.Stats.Loaded, 1)
+}
+
+// forget
+// Drops the border node of a Byte Block from the cache
+func (m *Manager) forget(node *Node) {
+	if e, ok := m.lru[node.BBKey]; ok && e.Value == node {
+		m.lruList.Remove(e)
+		delete(m.lru, node.BBKey)
+		atomic.AddInt64(&m.Stats.Loaded, -1)
+	}
+	if m.LoadedBB[node.BBKey] == node {
+		delete(m.LoadedBB, node.BBKey)
+	}
+}
+
+// evict
+// Unloads the least recently used Byte Blocks until the cache fits its size.
+// Nothing is unloaded while any node is dirty, since a dirty node may be
+// anywhere in a Byte Block.
+func (m *Manager) evict() {
+	if m.cacheSize <= 0 || len(m.Bpt.DirtyMap) > 0 {
+		return
+	}
+	for m.lruList.Len() > m.cacheSize {
+		m.unload(m.lruList.Back().Value.(*Node))
+		atomic.AddUint64(&m.Stats.Evictions, 1)
+	}
+}
+
+// unload
+// Replaces the children of a border node with NotLoaded placeholders.  The
+// Byte Blocks loaded beyond it can no longer be reached, so they are unloaded
+// as well.
+func (m *Manager) unload(node *Node) {
+	m.unloadBeyond(node.Left)
+	m.unloadBeyond(node.Right)
+	node.Left = new(NotLoaded)
+	node.Right = new(NotLoaded)
+	m.forget(node)
+}
+
+// unloadBeyond
+// Unloads the border nodes in the Byte Block that holds the given entry
+func (m *Manager) unloadBeyond(e Entry) {
+	node, ok := e.(*Node)
+	if !ok {
+		return
+	}
+	if !m.IsByteBlock(node) {
+		m.unloadBeyond(node.Left)
+		m.unloadBeyond(node.Right)
+		return
+	}
+	if node.Left != nil && node.Left.T() == TNotLoaded || //   Already unloaded
+		node.Right != nil && node.Right.T() == TNotLoaded {
+		m.forget(node)
+		return
+	}
+	m.unload(node)
+}
+
+// newCache
+// Allocates the structures that track the Byte Blocks in memory
+func (m *Manager) newCache() {
+	m.LoadedBB = make(map[[32]byte]*Node)
+	m.lru = make(map[[32]byte]*list.Element)
+	m.lruList = list.New()
+	m.Stats = new(CacheStats)
+}
@@ -0,0 +1,73 @@
+package pmt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+)
+
+func TestCacheSize(t *testing.T) {
+	const cacheSize = 10
+
+	newManager := func() *Manager {
+		dbManager, err := database.NewDBManager("memory", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return NewBPTManager(dbManager)
+	}
+	insert := func(m *Manager, round, count int) {
+		for i := 0; i < count; i++ {
+			key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+			value := sha256.Sum256([]byte(fmt.Sprintf("value %d %d", round, i)))
+			m.InsertKV(key, value)
+		}
+		m.Bpt.Update()
+	}
+
+	bounded, unbounded := newManager(), newManager()
+	bounded.SetCacheSize(cacheSize)
+
+	// Change the values in rounds, some of which touch Byte Blocks that have
+	// been unloaded
+	for round, count := range []int{5000, 100, 5000, 2000} {
+		insert(bounded, round, count)
+		insert(unbounded, round, count)
+
+		if bounded.GetRootHash() != unbounded.GetRootHash() {
+			t.Fatalf("round %d: root hashes differ", round)
+		}
+		stats := bounded.Stats.Snapshot()
+		if stats.Loaded > cacheSize || int(stats.Loaded) != len(bounded.lru) {
+			t.Fatalf("round %d: %d Byte Blocks are loaded, want at most %d", round, stats.Loaded, cacheSize)
+		}
+	}
+
+	stats := bounded.Stats.Snapshot()
+	if stats.Evictions == 0 || stats.Misses == 0 {
+		t.Fatalf("expected evictions and misses, got %+v", stats)
+	}
+
+	// Unloaded Byte Blocks are loaded on demand
+	if err := bounded.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		proof, err := bounded.GetProof(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proof.Included() || !proof.Validate() {
+			t.Fatalf("invalid proof for key %d", i)
+		}
+	}
+
+	// Trimming happens at the end of Update
+	bounded.Bpt.Update()
+	if loaded := bounded.Stats.Snapshot().Loaded; loaded > cacheSize {
+		t.Fatalf("%d Byte Blocks are loaded after Update, want at most %d", loaded, cacheSize)
+	}
+}
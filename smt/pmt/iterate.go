@@ -0,0 +1,207 @@
+package pmt
+
+// Compare
+// Compares two keys in the order of the BPT, returning -1, 0, or 1.  The BPT
+// branches on the bits of a key from the lowest bit of the first byte up, so
+// that is the order in which the bits are compared.  Values are visited in
+// this order by Iterate, and ranges of keys are ranges in this order.
+func Compare(a, b [32]byte) int {
+	for i := range a {
+		if a[i] == b[i] {
+			continue
+		}
+		for bit := byte(1); bit != 0; bit <<= 1 { //     The first bit that differs decides
+			switch {
+			case a[i]&bit == b[i]&bit:
+			case b[i]&bit != 0:
+				return -1
+			default:
+				return 1
+			}
+		}
+	}
+	return 0
+}
+
+// Iterator
+// Visits the values of the BPT in key order.  Each step finds the next value
+// from the root, loading Byte Blocks from the database as needed, so an
+// Iterator holds no nodes between steps and can be resumed from its Cursor
+// after the BPT has been updated.
+type Iterator struct {
+	m      *Manager
+	cursor [32]byte // Key of the last value visited
+	start  bool     // True until the first value is visited
+	value  *Value   // The current value
+	err    error    // The error that stopped the iteration, if any
+}
+
+// Iterate
+// Returns an Iterator that starts from the first value of the BPT
+func (m *Manager) Iterate() *Iterator {
+	return &Iterator{m: m, start: true}
+}
+
+// IterateAfter
+// Returns an Iterator that starts from the first value whose key follows the
+// cursor, which is usually the Cursor of an earlier Iterator
+func (m *Manager) IterateAfter(cursor [32]byte) *Iterator {
+	return &Iterator{m: m, cursor: cursor}
+}
+
+// Next
+// Advances to the next value, and returns false when there are no more
+// values or the iteration failed.  Check Err after Next returns false.
+func (it *Iterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	v, err := it.m.seek(it.m.Bpt.Root, it.cursor, !it.start)
+	if err != nil {
+		it.err, it.value = err, nil
+		return false
+	}
+	if v == nil {
+		it.value = nil
+		return false
+	}
+	it.value = &Value{Key: v.Key, Hash: v.Hash}
+	it.cursor, it.start = v.Key, false
+	return true
+}
+
+// Value
+// Returns the current value
+func (it *Iterator) Value() *Value {
+	return it.value
+}
+
+// Cursor
+// Returns the key of the last value visited.  Passing it to IterateAfter
+// resumes the iteration with the next value.
+func (it *Iterator) Cursor() [32]byte {
+	return it.cursor
+}
+
+// Err
+// Returns the error that stopped the iteration, if any
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// seek
+// Returns the first value of the given node whose key is not before the given
+// key, or that is after the key if after is set.  Returns nil if there is no
+// such value.
+func (m *Manager) seek(node *Node, key [32]byte, after bool) (*Value, error) {
+	err := m.loadChildren(node)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keys on the Left come before keys on the Right.  If the key goes Right,
+	// nothing on the Left can follow it.
+	if bitOf(key, node.Height) {
+		return m.seekEntry(node.Right, key, after)
+	}
+	v, err := m.seekEntry(node.Left, key, after)
+	if v != nil || err != nil {
+		return v, err
+	}
+	return m.first(node.Right)
+}
+
+// seekEntry
+// Applies seek to an entry, which may be a value
+func (m *Manager) seekEntry(e Entry, key [32]byte, after bool) (*Value, error) {
+	switch e := e.(type) {
+	case *Node:
+		return m.seek(e, key, after)
+	case *Value:
+		c := Compare(e.Key, key)
+		if c > 0 || c == 0 && !after {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+// first
+// Returns the first value of an entry, or nil if the entry is nil
+func (m *Manager) first(e Entry) (*Value, error) {
+	for {
+		switch n := e.(type) {
+		case *Value:
+			return n, nil
+		case *Node:
+			err := m.loadChildren(n)
+			if err != nil {
+				return nil, err
+			}
+			if n.Left != nil {
+				e = n.Left
+			} else {
+				e = n.Right
+			}
+		default:
+			return nil, nil
+		}
+	}
+}
+
+// seekBefore
+// Returns the last value of the given node whose key is before the given key,
+// or nil if there is no such value
+func (m *Manager) seekBefore(node *Node, key [32]byte) (*Value, error) {
+	err := m.loadChildren(node)
+	if err != nil {
+		return nil, err
+	}
+
+	// If the key goes Left, nothing on the Right can precede it
+	if !bitOf(key, node.Height) {
+		return m.seekBeforeEntry(node.Left, key)
+	}
+	v, err := m.seekBeforeEntry(node.Right, key)
+	if v != nil || err != nil {
+		return v, err
+	}
+	return m.last(node.Left)
+}
+
+// seekBeforeEntry
+// Applies seekBefore to an entry, which may be a value
+func (m *Manager) seekBeforeEntry(e Entry, key [32]byte) (*Value, error) {
+	switch e := e.(type) {
+	case *Node:
+		return m.seekBefore(e, key)
+	case *Value:
+		if Compare(e.Key, key) < 0 {
+			return e, nil
+		}
+	}
+	return nil, nil
+}
+
+// last
+// Returns the last value of an entry, or nil if the entry is nil
+func (m *Manager) last(e Entry) (*Value, error) {
+	for {
+		switch n := e.(type) {
+		case *Value:
+			return n, nil
+		case *Node:
+			err := m.loadChildren(n)
+			if err != nil {
+				return nil, err
+			}
+			if n.Right != nil {
+				e = n.Right
+			} else {
+				e = n.Left
+			}
+		default:
+			return nil, nil
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package pmt
 
 import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"sync/atomic"
+
 	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
 )
 
@@ -9,6 +14,11 @@ type Manager struct {
 	Dirty     []*Node
 	Bpt       *BPT
 	LoadedBB  map[[32]byte]*Node
+	Stats     *CacheStats // Counts of the Byte Blocks held in memory
+
+	cacheSize int                        // Number of Byte Blocks kept in memory, zero for all of them
+	lru       map[[32]byte]*list.Element // Byte Blocks in memory, by BBKey
+	lruList   *list.List                 // Border nodes of the Byte Blocks in memory, most recently used first
 }
 
 // NewBPTManager
@@ -19,7 +29,7 @@ func NewBPTManager(dbManager *database.Manager) *Manager { // Return a new BPTMa
 	manager.DBManager = dbManager                 //            populate with pointer to the database manager
 	manager.Bpt = NewBPT()                        //            Allocate a new BPT
 	manager.Bpt.manager = manager                 //            Allow the Bpt to call back to the manager for db access
-	manager.LoadedBB = make(map[[32]byte]*Node)   //            Allocate an initial map
+	manager.newCache()                            //            Allocate the cache of Byte Blocks
 	data, e := dbManager.Key("BPT", "Root").Get() //            Get the BPT settings from disk
 	if e == nil {                                 //            If nothing is found, well this is a fresh instance
 		manager.Bpt.UnMarshal(data)        //                 But if data is found, then unmarshal
@@ -48,10 +58,14 @@ func (m *Manager) LoadNode(node *Node) *Node {
 		if e != nil {
 			return nil
 		}
-		m.Bpt.UnMarshalByteBlock(node, data) //                      unpack it
-		m.LoadedBB[node.BBKey] = node        //                      Save the root node of Byte Block
+		m.Bpt.UnMarshalByteBlock(node, data) //                    unpack it
+		m.LoadedBB[node.BBKey] = node        //                    Save the root node of Byte Block
+		m.touch(node)                        //                    and track it as recently used
+		atomic.AddUint64(&m.Stats.Misses, 1) //
 		return node
 	} else {
+		m.touch(n)
+		atomic.AddUint64(&m.Stats.Hits, 1)
 		return n
 	}
 }
@@ -61,6 +75,7 @@ func (m *Manager) LoadNode(node *Node) *Node {
 func (m *Manager) FlushNode(node *Node) { //   Flush a Byte Block
 	data := m.Bpt.MarshalByteBlock(node)                 //
 	m.DBManager.Key("BPT", node.BBKey[:]).PutBatch(data) //
+	m.touch(node)                                        // A Byte Block just written is recently used
 	if node.Height == 0 {
 		data = m.Bpt.Marshal()
 		m.DBManager.Key("BPT", "Root").PutBatch(data)
@@ -72,3 +87,99 @@ func (m *Manager) FlushNode(node *Node) { //   Flush a Byte Block
 func (m *Manager) InsertKV(key, value [32]byte) {
 	m.Bpt.Insert(key, value)
 }
+
+// IsByteBlock
+// Returns true if the node is the border node of a Byte Block, i.e. if its
+// children are persisted together under its BBKey
+func (m *Manager) IsByteBlock(node *Node) bool {
+	return node.Height&m.Bpt.mask == 0
+}
+
+// loadChildren
+// Replaces NotLoaded children of the given border node with the nodes and
+// values of the Byte Block persisted for it
+func (m *Manager) loadChildren(node *Node) error {
+	if node.Left == nil || node.Left.T() != TNotLoaded {
+		if node.Right == nil || node.Right.T() != TNotLoaded {
+			return nil
+		}
+	}
+	n := m.LoadNode(node)
+	if n == nil {
+		return fmt.Errorf("byte block %x is missing", node.BBKey)
+	}
+	node.Left = n.Left
+	node.Right = n.Right
+	return nil
+}
+
+// Walk
+// Calls fn for every node and value in the BPT, parents before children and
+// Left before Right.  Byte Blocks are loaded from the database as needed.
+func (m *Manager) Walk(fn func(Entry) error) error {
+	var walk func(e Entry) error
+	walk = func(e Entry) error {
+		if e == nil {
+			return nil
+		}
+		err := fn(e)
+		if err != nil {
+			return err
+		}
+		node, ok := e.(*Node)
+		if !ok {
+			return nil
+		}
+		err = m.loadChildren(node)
+		if err != nil {
+			return err
+		}
+		err = walk(node.Left)
+		if err != nil {
+			return err
+		}
+		return walk(node.Right)
+	}
+	return walk(m.Bpt.Root)
+}
+
+// Verify
+// Recomputes the hash of every node from the values up and returns an error
+// if any node does not hold the hash computed for it.  Byte Blocks are loaded
+// from the database as needed.
+func (m *Manager) Verify() error {
+	var verify func(node *Node) error
+	verify = func(node *Node) error {
+		err := m.loadChildren(node)
+		if err != nil {
+			return err
+		}
+		for _, e := range []Entry{node.Left, node.Right} {
+			if n, ok := e.(*Node); ok {
+				err = verify(n)
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		var hash [32]byte
+		L := GetHash(node.Left)
+		R := GetHash(node.Right)
+		switch {
+		case L != nil && R != nil:
+			hash = sha256.Sum256(append(L, R...))
+		case L != nil:
+			copy(hash[:], L)
+		case R != nil:
+			copy(hash[:], R)
+		case node.Parent != nil:
+			return fmt.Errorf("node %d has no children", node.ID)
+		}
+		if hash != node.Hash {
+			return fmt.Errorf("node %d has hash %x, expected %x", node.ID, node.Hash, hash)
+		}
+		return nil
+	}
+	return verify(m.Bpt.Root)
+}
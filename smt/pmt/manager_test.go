@@ -115,3 +115,43 @@ func TestManagerSeries(t *testing.T) {
 		}
 	}
 }
+
+func TestManagerWalkAndVerify(t *testing.T) {
+	d := 1000
+
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+	for i := 0; i < d; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		value := sha256.Sum256([]byte(fmt.Sprintf("value %d", i)))
+		bptManager.InsertKV(key, value)
+	}
+	bptManager.Bpt.Update()
+
+	// Reload from the database so the walk has to load the byte blocks
+	bptManager = NewBPTManager(dbManager)
+	var values []*Value
+	err = bptManager.Walk(func(e Entry) error {
+		if v, ok := e.(*Value); ok {
+			values = append(values, v)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != d {
+		t.Fatalf("expected %d values, walked %d", d, len(values))
+	}
+	if err := bptManager.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	values[d/2].Hash[0]++
+	if err := bptManager.Verify(); err == nil {
+		t.Fatal("verify should fail after a value is modified")
+	}
+}
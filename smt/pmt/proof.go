@@ -0,0 +1,472 @@
+package pmt
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+)
+
+// Proof
+// Proves that a key has a given hash in the BPT, or that the key is not in the
+// BPT.  A proof of inclusion follows the bits of the key from the root of the
+// BPT to Leaf, the value of the key, and holds the hash of every sibling along
+// that path.
+//
+// Nodes with a single child take the hash of that child, so only the siblings
+// that are not nil are kept.  That also means the hashes do not commit to the
+// height of a sibling, so a path that ends without the key cannot prove the
+// key is missing.  Instead a proof of exclusion holds proofs of inclusion of
+// the values on either side of the key, Before and After.  If the root can be
+// rebuilt with the two values next to each other, nothing lies between them.
+type Proof struct {
+	Key      [32]byte   // The key being proven
+	Root     [32]byte   // The root hash of the BPT the proof leads to
+	Depth    int        // The number of nodes from the root to Leaf, including the root
+	Leaf     *Value     // The value of the key, or nil if the key is not in the BPT
+	Siblings []*Sibling // The siblings along the path that are not nil, from the root out
+	Before   *Proof     // If Leaf is nil, the proof of the value before the key, or nil if there is none
+	After    *Proof     // If Leaf is nil, the proof of the value after the key, or nil if there is none
+}
+
+// Sibling
+// The hash of the other child of the node at Height on the path of a proof
+type Sibling struct {
+	Height int      // Height of the node whose other child this is
+	Hash   [32]byte // Hash of the other child
+}
+
+// bitOf
+// Returns true if the key goes Right at the given height, as Insert does
+func bitOf(key [32]byte, height int) bool {
+	return key[height>>3]&(1<<(height&7)) != 0
+}
+
+// GetProof
+// Returns a proof that the key is or is not in the BPT, as of the last
+// Update.  Byte Blocks are loaded from the database as needed.
+func (m *Manager) GetProof(key [32]byte) (*Proof, error) {
+	proof, err := m.getPath(key)
+	if err != nil || proof.Included() {
+		return proof, err
+	}
+
+	// Prove the key is missing with the values on either side of it
+	exclusion := new(Proof)
+	exclusion.Key = key
+	exclusion.Root = proof.Root
+	before, err := m.seekBefore(m.Bpt.Root, key)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil {
+		exclusion.Before, err = m.getPath(before.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	after, err := m.seek(m.Bpt.Root, key, true)
+	if err != nil {
+		return nil, err
+	}
+	if after != nil {
+		exclusion.After, err = m.getPath(after.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return exclusion, nil
+}
+
+// getPath
+// Returns the path of the key from the root to the value where the path
+// ends, which is a proof of inclusion if the value holds the key
+func (m *Manager) getPath(key [32]byte) (*Proof, error) {
+	proof := new(Proof)
+	proof.Key = key
+	proof.Root = m.Bpt.Root.Hash
+
+	node := m.Bpt.Root
+	for {
+		err := m.loadChildren(node)
+		if err != nil {
+			return nil, err
+		}
+
+		next, sibling := node.Left, node.Right // A zero bit goes Left
+		if bitOf(key, node.Height) {
+			next, sibling = node.Right, node.Left
+		}
+		if sibling != nil {
+			s := new(Sibling)
+			s.Height = node.Height
+			copy(s.Hash[:], sibling.GetHash())
+			proof.Siblings = append(proof.Siblings, s)
+		}
+
+		switch e := next.(type) {
+		case *Node:
+			node = e
+			continue
+		case *Value:
+			proof.Leaf = &Value{Key: e.Key, Hash: e.Hash}
+		}
+		proof.Depth = node.Height + 1
+		return proof, nil
+	}
+}
+
+// Included
+// Returns true if the proof shows the key is in the BPT.  The proof must also
+// be valid.
+func (p *Proof) Included() bool {
+	return p.Leaf != nil && p.Leaf.Key == p.Key
+}
+
+// Validate
+// Returns true if the proof leads to the root, so a valid proof proves either
+// inclusion or exclusion of the key.  A proof of exclusion is valid if its
+// bounds are valid proofs of inclusion of keys on either side of the key, and
+// the root can be rebuilt with nothing between them.  Only the proof of
+// exclusion from an empty BPT has no bounds.
+func (p *Proof) Validate() bool {
+	if p.Leaf == nil {
+		return p.validateExclusion()
+	}
+	if p.Leaf.Key != p.Key || p.Before != nil || p.After != nil {
+		return false
+	}
+	if p.Depth < 1 || p.Depth > 256 {
+		return false
+	}
+
+	current := p.Leaf.GetHash() // The hash of the entry on the path at each height
+
+	i := len(p.Siblings) - 1
+	for h := p.Depth - 1; h >= 0; h-- { //         Work from the leaf down to the root
+		var sibling []byte
+		if i >= 0 && p.Siblings[i].Height == h {
+			sibling = p.Siblings[i].Hash[:]
+			i--
+		}
+		switch {
+		case current != nil && sibling != nil: //  Hash in the order of the key's bit
+			var hash [32]byte
+			if bitOf(p.Key, h) {
+				hash = sha256.Sum256(append(append([]byte{}, sibling...), current...))
+			} else {
+				hash = sha256.Sum256(append(append([]byte{}, current...), sibling...))
+			}
+			current = hash[:]
+		case sibling != nil: //                    A single child just passes its hash down
+			current = sibling
+		}
+	}
+	if i >= 0 { //                                 Every sibling must be used, in order
+		return false
+	}
+	return bytes.Equal(current, p.Root[:])
+}
+
+// validateExclusion
+// Validates a proof of exclusion
+func (p *Proof) validateExclusion() bool {
+	if p.Depth != 0 || len(p.Siblings) > 0 {
+		return false
+	}
+	if p.Before == nil && p.After == nil { //       An empty BPT has a zero root
+		return p.Root == [32]byte{}
+	}
+
+	var values []*Value
+	for _, b := range []*Proof{p.Before, p.After} {
+		if b == nil {
+			continue
+		}
+		if b.Root != p.Root || !b.Included() || !b.Validate() {
+			return false
+		}
+		values = append(values, b.Leaf)
+	}
+	if p.Before != nil && Compare(p.Before.Key, p.Key) >= 0 {
+		return false
+	}
+	if p.After != nil && Compare(p.After.Key, p.Key) <= 0 {
+		return false
+	}
+
+	hash, err := rebuild(0, values, p.Before, p.After)
+	return err == nil && bytes.Equal(hash, p.Root[:])
+}
+
+// Receipt
+// Converts a proof of inclusion to a receipt, whose element is the hash of the
+// value and whose anchor is the root of the BPT.  Returns nil if the proof is
+// not a proof of inclusion.
+func (p *Proof) Receipt() *managed.Receipt {
+	if !p.Included() {
+		return nil
+	}
+	receipt := new(managed.Receipt)
+	receipt.Element = p.Leaf.GetHash()
+	receipt.Anchor = managed.Hash(p.Root[:]).Copy()
+	receipt.Nodes = make([]*managed.Node, len(p.Siblings))
+	for i, s := range p.Siblings { //              The receipt works from the value down to the root
+		node := new(managed.Node)
+		node.Right = !bitOf(p.Key, s.Height) //    If the key goes Left, the sibling is on the Right
+		node.Hash = managed.Hash(s.Hash[:]).Copy()
+		receipt.Nodes[len(p.Siblings)-1-i] = node
+	}
+	return receipt
+}
+
+// Equal
+// Returns true if the proofs are the same
+func (p *Proof) Equal(p2 *Proof) bool {
+	if p.Key != p2.Key || p.Root != p2.Root || p.Depth != p2.Depth {
+		return false
+	}
+	if (p.Leaf == nil) != (p2.Leaf == nil) || p.Leaf != nil && !p.Leaf.Equal(p2.Leaf) {
+		return false
+	}
+	if len(p.Siblings) != len(p2.Siblings) {
+		return false
+	}
+	for i, s := range p.Siblings {
+		if *s != *p2.Siblings[i] {
+			return false
+		}
+	}
+	for _, b := range [][2]*Proof{{p.Before, p2.Before}, {p.After, p2.After}} {
+		if (b[0] == nil) != (b[1] == nil) || b[0] != nil && !b[0].Equal(b[1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// MarshalBinary
+// Serializes the proof as the key, the root, the depth, the leaf (tagged as
+// TNil or TValue), then the count of siblings followed by the height and hash
+// of each.  A proof of exclusion ends with the proofs of its bounds, each
+// preceded by its length.  The length of a missing bound is zero.
+func (p *Proof) MarshalBinary() ([]byte, error) {
+	var data []byte
+	data = append(data, p.Key[:]...)
+	data = append(data, p.Root[:]...)
+	data = append(data, common.Uint64Bytes(uint64(p.Depth))...)
+	if p.Leaf == nil {
+		data = append(data, TNil)
+	} else {
+		data = append(data, TValue)
+		data = append(data, p.Leaf.Marshal()...)
+	}
+	data = append(data, common.Uint64Bytes(uint64(len(p.Siblings)))...)
+	for _, s := range p.Siblings {
+		data = append(data, common.Uint64Bytes(uint64(s.Height))...)
+		data = append(data, s.Hash[:]...)
+	}
+	if p.Leaf != nil {
+		return data, nil
+	}
+	for _, b := range []*Proof{p.Before, p.After} {
+		if b == nil {
+			data = append(data, common.Uint64Bytes(0)...)
+			continue
+		}
+		bb, err := b.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, common.Uint64Bytes(uint64(len(bb)))...)
+		data = append(data, bb...)
+	}
+	return data, nil
+}
+
+var errShortProof = errors.New("proof is truncated")
+
+// UnmarshalBinary
+// Deserializes a proof serialized by MarshalBinary
+func (p *Proof) UnmarshalBinary(data []byte) error {
+	readUint := func() (uint64, error) {
+		if len(data) == 0 {
+			return 0, errShortProof
+		}
+		var v uint64
+		v, data = common.BytesUint64(data)
+		return v, nil
+	}
+
+	if len(data) < 64 {
+		return errShortProof
+	}
+	copy(p.Key[:], data[:32])
+	copy(p.Root[:], data[32:64])
+	data = data[64:]
+
+	depth, err := readUint()
+	if err != nil {
+		return err
+	}
+	if depth > 256 {
+		return fmt.Errorf("invalid depth %d", depth)
+	}
+	p.Depth = int(depth)
+
+	if len(data) == 0 {
+		return errShortProof
+	}
+	switch data[0] {
+	case TNil:
+		p.Leaf, data = nil, data[1:]
+	case TValue:
+		if len(data) < 65 {
+			return errShortProof
+		}
+		p.Leaf = new(Value)
+		data = p.Leaf.UnMarshal(data[1:])
+	default:
+		return fmt.Errorf("invalid leaf type %d", data[0])
+	}
+
+	count, err := readUint()
+	if err != nil {
+		return err
+	}
+	if count > 256 {
+		return fmt.Errorf("invalid sibling count %d", count)
+	}
+	p.Siblings = make([]*Sibling, count)
+	for i := range p.Siblings {
+		height, err := readUint()
+		if err != nil {
+			return err
+		}
+		if len(data) < 32 {
+			return errShortProof
+		}
+		s := new(Sibling)
+		s.Height = int(height)
+		copy(s.Hash[:], data[:32])
+		data = data[32:]
+		p.Siblings[i] = s
+	}
+	p.Before, p.After = nil, nil
+	if p.Leaf == nil {
+		for _, b := range []**Proof{&p.Before, &p.After} {
+			size, err := readUint()
+			if err != nil {
+				return err
+			}
+			if size == 0 {
+				continue
+			}
+			if size > uint64(len(data)) {
+				return errShortProof
+			}
+			*b = new(Proof)
+			err = (*b).UnmarshalBinary(data[:size])
+			if err != nil {
+				return err
+			}
+			data = data[size:]
+		}
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("%d bytes left over after the proof", len(data))
+	}
+	return nil
+}
+
+// The JSON form of a proof, with hashes and keys as hex strings
+type proofJSON struct {
+	Key      string         `json:"key"`
+	Root     string         `json:"root"`
+	Depth    int            `json:"depth"`
+	Leaf     *valueJSON     `json:"leaf,omitempty"`
+	Siblings []*siblingJSON `json:"siblings,omitempty"`
+	Before   *Proof         `json:"before,omitempty"`
+	After    *Proof         `json:"after,omitempty"`
+}
+
+type valueJSON struct {
+	Key  string `json:"key"`
+	Hash string `json:"hash"`
+}
+
+type siblingJSON struct {
+	Height int    `json:"height"`
+	Hash   string `json:"hash"`
+}
+
+// MarshalJSON
+// Serializes the proof as JSON, with keys and hashes in hex
+func (p *Proof) MarshalJSON() ([]byte, error) {
+	v := new(proofJSON)
+	v.Key = hex.EncodeToString(p.Key[:])
+	v.Root = hex.EncodeToString(p.Root[:])
+	v.Depth = p.Depth
+	if p.Leaf != nil {
+		v.Leaf = &valueJSON{Key: hex.EncodeToString(p.Leaf.Key[:]), Hash: hex.EncodeToString(p.Leaf.Hash[:])}
+	}
+	for _, s := range p.Siblings {
+		v.Siblings = append(v.Siblings, &siblingJSON{Height: s.Height, Hash: hex.EncodeToString(s.Hash[:])})
+	}
+	v.Before, v.After = p.Before, p.After
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON
+// Deserializes a proof serialized by MarshalJSON
+func (p *Proof) UnmarshalJSON(data []byte) error {
+	v := new(proofJSON)
+	err := json.Unmarshal(data, v)
+	if err != nil {
+		return err
+	}
+
+	hash := func(s, name string, h *[32]byte) error {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %v", name, err)
+		}
+		if len(b) != 32 {
+			return fmt.Errorf("invalid %s: want 32 bytes, got %d", name, len(b))
+		}
+		copy(h[:], b)
+		return nil
+	}
+
+	if err := hash(v.Key, "key", &p.Key); err != nil {
+		return err
+	}
+	if err := hash(v.Root, "root", &p.Root); err != nil {
+		return err
+	}
+	p.Depth = v.Depth
+	p.Leaf = nil
+	if v.Leaf != nil {
+		p.Leaf = new(Value)
+		if err := hash(v.Leaf.Key, "leaf key", &p.Leaf.Key); err != nil {
+			return err
+		}
+		if err := hash(v.Leaf.Hash, "leaf hash", &p.Leaf.Hash); err != nil {
+			return err
+		}
+	}
+	p.Siblings = make([]*Sibling, len(v.Siblings))
+	for i, s := range v.Siblings {
+		p.Siblings[i] = &Sibling{Height: s.Height}
+		if err := hash(s.Hash, "sibling hash", &p.Siblings[i].Hash); err != nil {
+			return err
+		}
+	}
+	p.Before, p.After = v.Before, v.After
+	return nil
+}
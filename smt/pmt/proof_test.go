@@ -0,0 +1,185 @@
+package pmt
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+)
+
+func TestProof(t *testing.T) {
+	const count = 5000
+
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+	for i := 0; i < count; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		value := sha256.Sum256([]byte(fmt.Sprintf("value %d", i)))
+		bptManager.InsertKV(key, value)
+	}
+	bptManager.Bpt.Update()
+	bptManager = NewBPTManager(dbManager)
+
+	check := func(p *Proof) {
+		if !p.Validate() {
+			t.Fatalf("proof of %x is not valid", p.Key)
+		}
+		if p.Root != bptManager.Bpt.Root.Hash {
+			t.Fatalf("proof of %x does not lead to the root", p.Key)
+		}
+
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2 := new(Proof)
+		if err = p2.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if !p.Equal(p2) {
+			t.Fatalf("proof of %x does not survive binary encoding", p.Key)
+		}
+
+		data, err = json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p2 = new(Proof)
+		if err = json.Unmarshal(data, p2); err != nil {
+			t.Fatal(err)
+		}
+		if !p.Equal(p2) {
+			t.Fatalf("proof of %x does not survive JSON encoding", p.Key)
+		}
+	}
+
+	// Inclusion
+	for i := 0; i < count; i += 7 {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		value := sha256.Sum256([]byte(fmt.Sprintf("value %d", i)))
+		p, err := bptManager.GetProof(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(p)
+		if !p.Included() || p.Leaf.Hash != value {
+			t.Fatalf("proof %d does not include the value", i)
+		}
+	}
+
+	// Exclusion
+	for i := 0; i < 1000; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("missing %d", i)))
+		p, err := bptManager.GetProof(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(p)
+		if p.Included() {
+			t.Fatalf("proof of missing key %d includes it", i)
+		}
+		if p.Before == nil || p.After == nil {
+			t.Fatalf("proof of missing key %d is missing a bound", i)
+		}
+	}
+
+	// Exclusion before the first value and after the last
+	var first, last [32]byte
+	for i := range last {
+		last[i] = 0xFF
+	}
+	for _, key := range [][32]byte{first, last} {
+		p, err := bptManager.GetProof(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(p)
+		if p.Included() || (p.Before == nil) == (p.After == nil) {
+			t.Fatalf("expected a proof of exclusion of %x with one bound", key)
+		}
+	}
+
+	// A proof of inclusion cannot be turned into a proof of exclusion
+	key := sha256.Sum256([]byte("key 1"))
+	p, _ := bptManager.GetProof(key)
+	p.Leaf = nil
+	if p.Validate() {
+		t.Fatal("a proof with the leaf removed is valid")
+	}
+	p, _ = bptManager.GetProof(key)
+	p.Leaf.Hash[0]++
+	if p.Validate() {
+		t.Fatal("a proof with a different value is valid")
+	}
+	p, _ = bptManager.GetProof(key)
+	p.Depth--
+	if p.Validate() {
+		t.Fatal("a proof with a shortened path is valid")
+	}
+	p, _ = bptManager.GetProof(key)
+	p.Siblings = p.Siblings[1:]
+	if p.Validate() {
+		t.Fatal("a proof with a sibling removed is valid")
+	}
+
+	// A proof of inclusion cannot be turned into a proof of exclusion by
+	// passing the leaf off as a sibling on a longer path
+	p, _ = bptManager.GetProof(key)
+	p.Siblings = append(p.Siblings, &Sibling{Height: p.Depth})
+	copy(p.Siblings[len(p.Siblings)-1].Hash[:], p.Leaf.GetHash())
+	p.Leaf = nil
+	p.Depth++
+	if p.Validate() || p.Included() {
+		t.Fatal("a proof with the leaf moved to a sibling is valid")
+	}
+
+	// A proof of exclusion cannot skip over the key
+	it := bptManager.Iterate()
+	var before, after *Value
+	for it.Next() {
+		if it.Value().Key == key {
+			it.Next()
+			after = it.Value()
+			break
+		}
+		before = it.Value()
+	}
+	p = &Proof{Key: key, Root: bptManager.Bpt.Root.Hash}
+	p.Before, _ = bptManager.GetProof(before.Key)
+	p.After, _ = bptManager.GetProof(after.Key)
+	if p.Validate() {
+		t.Fatal("a proof of exclusion of a key that is in the BPT is valid")
+	}
+
+	// A proof of exclusion cannot use a bound from elsewhere in the BPT
+	p, _ = bptManager.GetProof(sha256.Sum256([]byte("missing 1")))
+	p.Before, _ = bptManager.GetProof(before.Key)
+	if p.Validate() {
+		t.Fatal("a proof with a bound from elsewhere is valid")
+	}
+	p, _ = bptManager.GetProof(sha256.Sum256([]byte("missing 1")))
+	p.Before = nil
+	if p.Validate() {
+		t.Fatal("a proof with a bound removed is valid")
+	}
+}
+
+func TestProofEmpty(t *testing.T) {
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+	p, err := bptManager.GetProof(sha256.Sum256([]byte("key")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Validate() || p.Included() {
+		t.Fatal("expected a valid proof of exclusion from an empty BPT")
+	}
+}
@@ -0,0 +1,334 @@
+package pmt
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+)
+
+// Range
+// The values of the BPT whose keys are in a range, with proofs of the values
+// on either side of the range.  The range starts at Start and ends before End,
+// in key order.  If After is nil the range runs to the end of the BPT.
+//
+// The proofs of the bounds hold the hashes of everything outside the range,
+// so the root of the BPT can be rebuilt from the proofs and the values.  If it
+// matches, the values are all the values between the bounds.
+type Range struct {
+	Root   [32]byte // The root hash of the BPT the range was exported from
+	Start  [32]byte // The first key of the range
+	End    [32]byte // The key that follows the range, if After is not nil
+	Values []*Value // The values of the range, in key order
+	Before *Proof   // The proof of the last value before Start, or nil if there is none
+	After  *Proof   // The proof of the first value at or after End, or nil if there is none
+}
+
+// GetRange
+// Exports the values whose keys are at least start and, if end is not nil,
+// before end.  If limit is positive and there are more than limit values, the
+// range is cut short so it holds limit values, and its End is the key of the
+// next value.  The rest of the range can be exported by calling GetRange
+// again, starting at End.
+func (m *Manager) GetRange(start [32]byte, end *[32]byte, limit int) (*Range, error) {
+	r := new(Range)
+	r.Root = m.Bpt.Root.Hash
+	r.Start = start
+
+	var next *Value                                   // The first value after the range
+	it := &Iterator{m: m, cursor: start, start: true} // Starts with the value of start, if any
+	for it.Next() {
+		v := it.Value()
+		if end != nil && Compare(v.Key, *end) >= 0 {
+			next = v
+			break
+		}
+		if limit > 0 && len(r.Values) == limit { //     Cut the range short before this value
+			end, next = &v.Key, v
+			break
+		}
+		r.Values = append(r.Values, v)
+	}
+	if it.Err() != nil {
+		return nil, it.Err()
+	}
+	if end != nil {
+		r.End = *end
+	}
+
+	before, err := m.seekBefore(m.Bpt.Root, start)
+	if err != nil {
+		return nil, err
+	}
+	if before != nil {
+		r.Before, err = m.GetProof(before.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if next != nil {
+		r.After, err = m.GetProof(next.Key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// Validate
+// Returns nil if the range proves its values are all the values of the BPT
+// with the root hash Root whose keys are in the range
+func (r *Range) Validate() error {
+	if r.Before != nil {
+		if r.Before.Root != r.Root || !r.Before.Included() || !r.Before.Validate() {
+			return fmt.Errorf("invalid proof of the value before the range")
+		}
+		if Compare(r.Before.Key, r.Start) >= 0 {
+			return fmt.Errorf("the value before the range is not before its start")
+		}
+	}
+	if r.After != nil {
+		if r.After.Root != r.Root || !r.After.Included() || !r.After.Validate() {
+			return fmt.Errorf("invalid proof of the value after the range")
+		}
+		if Compare(r.After.Key, r.End) < 0 {
+			return fmt.Errorf("the value after the range is before its end")
+		}
+		if Compare(r.Start, r.End) > 0 {
+			return fmt.Errorf("the range ends before it starts")
+		}
+	}
+	for i, v := range r.Values {
+		switch {
+		case !r.contains(v.Key):
+			return fmt.Errorf("value %d is outside of the range", i)
+		case i > 0 && Compare(r.Values[i-1].Key, v.Key) >= 0:
+			return fmt.Errorf("value %d is out of order", i)
+		}
+	}
+
+	// The leaves of the bounds go on either side of the values
+	values := r.Values
+	if r.Before != nil {
+		values = append([]*Value{r.Before.Leaf}, values...)
+	}
+	if r.After != nil {
+		values = append(append([]*Value{}, values...), r.After.Leaf)
+	}
+	hash, err := rebuild(0, values, r.Before, r.After)
+	if err != nil {
+		return err
+	}
+	var root [32]byte
+	copy(root[:], hash)
+	if root != r.Root {
+		return fmt.Errorf("the range does not match the root")
+	}
+	return nil
+}
+
+// rebuild
+// Computes the hash of the node at the given height that holds the given
+// values, which are in key order.  If before is not nil, its leaf is the first
+// of the values and the node also holds everything before it.  The hashes of
+// those entries are the siblings of the leaf on the Left, which come from the
+// proof.  Likewise, if after is not nil its leaf is the last of the values and
+// its siblings on the Right are the entries after it.
+//
+// The hashes do not commit to heights, so if the rebuilt hash matches, the
+// BPT holds the same entries in the same order: first the entries before the
+// leaf of before, then the values, then the entries after the leaf of after.
+// Since the BPT is in key order, there is nothing else between the bounds.
+func rebuild(height int, values []*Value, before, after *Proof) ([]byte, error) {
+	// A bound with no more siblings on the outside is just a value
+	if before != nil && !before.outside(height, true) {
+		before = nil
+	}
+	if after != nil && !after.outside(height, false) {
+		after = nil
+	}
+	if before == nil && after == nil {
+		switch len(values) {
+		case 0:
+			return nil, nil
+		case 1:
+			return values[0].GetHash(), nil
+		}
+	}
+	if height >= 256 {
+		return nil, fmt.Errorf("the values cannot be placed in the BPT")
+	}
+
+	var left, right []*Value
+	for _, v := range values {
+		if bitOf(v.Key, height) {
+			right = append(right, v)
+		} else {
+			left = append(left, v)
+		}
+	}
+
+	// Each bound follows its leaf to one of the children
+	var beforeL, beforeR, afterL, afterR *Proof
+	if before != nil && bitOf(before.Leaf.Key, height) {
+		beforeR = before
+	} else {
+		beforeL = before
+	}
+	if after != nil && bitOf(after.Leaf.Key, height) {
+		afterR = after
+	} else {
+		afterL = after
+	}
+
+	// A child on the outside of a bound comes from its proof
+	var L, R []byte
+	var err error
+	if beforeR != nil {
+		if len(left) > 0 {
+			return nil, fmt.Errorf("the values do not match the proof of key %X", before.Key)
+		}
+		L = before.sibling(height)
+	} else {
+		L, err = rebuild(height+1, left, beforeL, afterL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if afterL != nil {
+		if len(right) > 0 {
+			return nil, fmt.Errorf("the values do not match the proof of key %X", after.Key)
+		}
+		R = after.sibling(height)
+	} else {
+		R, err = rebuild(height+1, right, beforeR, afterR)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case L != nil && R != nil:
+		h := sha256.Sum256(append(append([]byte{}, L...), R...))
+		return h[:], nil
+	case L != nil:
+		return L, nil
+	default:
+		return R, nil
+	}
+}
+
+// outside
+// Returns true if the proof has a sibling at or past the given height on the
+// Left of its leaf, if left is set, or on the Right
+func (p *Proof) outside(height int, left bool) bool {
+	for _, s := range p.Siblings {
+		if s.Height >= height && bitOf(p.Leaf.Key, s.Height) == left {
+			return true
+		}
+	}
+	return false
+}
+
+// contains
+// Returns true if the key is in the range
+func (r *Range) contains(key [32]byte) bool {
+	return Compare(key, r.Start) >= 0 && (r.After == nil || Compare(key, r.End) < 0)
+}
+
+// sibling
+// Returns the hash of the sibling at the given height, or nil
+func (p *Proof) sibling(height int) []byte {
+	for _, s := range p.Siblings {
+		if s.Height == height {
+			return s.Hash[:]
+		}
+	}
+	return nil
+}
+
+// MarshalBinary
+// Serializes the range as the root, the start, the end, the count of values
+// followed by each value, then the proofs of the bounds, each preceded by its
+// length.  The length of a missing bound is zero.
+func (r *Range) MarshalBinary() ([]byte, error) {
+	var data []byte
+	data = append(data, r.Root[:]...)
+	data = append(data, r.Start[:]...)
+	data = append(data, r.End[:]...)
+	data = append(data, common.Uint64Bytes(uint64(len(r.Values)))...)
+	for _, v := range r.Values {
+		data = append(data, v.Marshal()...)
+	}
+	for _, p := range []*Proof{r.Before, r.After} {
+		if p == nil {
+			data = append(data, common.Uint64Bytes(0)...)
+			continue
+		}
+		b, err := p.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		data = append(data, common.Uint64Bytes(uint64(len(b)))...)
+		data = append(data, b...)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary
+// Deserializes a range serialized by MarshalBinary
+func (r *Range) UnmarshalBinary(data []byte) error {
+	readUint := func() (uint64, error) {
+		if len(data) == 0 {
+			return 0, errShortProof
+		}
+		var v uint64
+		v, data = common.BytesUint64(data)
+		return v, nil
+	}
+
+	if len(data) < 96 {
+		return errShortProof
+	}
+	copy(r.Root[:], data[:32])
+	copy(r.Start[:], data[32:64])
+	copy(r.End[:], data[64:96])
+	data = data[96:]
+
+	count, err := readUint()
+	if err != nil {
+		return err
+	}
+	if count > uint64(len(data))/64 {
+		return errShortProof
+	}
+	r.Values = make([]*Value, count)
+	for i := range r.Values {
+		r.Values[i] = new(Value)
+		data = r.Values[i].UnMarshal(data)
+	}
+
+	r.Before, r.After = nil, nil
+	for _, p := range []**Proof{&r.Before, &r.After} {
+		size, err := readUint()
+		if err != nil {
+			return err
+		}
+		if size == 0 {
+			continue
+		}
+		if size > uint64(len(data)) {
+			return errShortProof
+		}
+		*p = new(Proof)
+		err = (*p).UnmarshalBinary(data[:size])
+		if err != nil {
+			return err
+		}
+		data = data[size:]
+	}
+	if len(data) > 0 {
+		return fmt.Errorf("%d bytes left over after the range", len(data))
+	}
+	return nil
+}
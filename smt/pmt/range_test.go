@@ -0,0 +1,184 @@
+package pmt
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+)
+
+func TestIterate(t *testing.T) {
+	const count = 3000
+
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+	var keys [][32]byte
+	for i := 0; i < count; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		bptManager.InsertKV(key, sha256.Sum256(key[:]))
+		keys = append(keys, key)
+	}
+	bptManager.Bpt.Update()
+	sort.Slice(keys, func(i, j int) bool { return Compare(keys[i], keys[j]) < 0 })
+
+	// Iterate from a fresh Manager so every Byte Block is loaded from disk
+	bptManager = NewBPTManager(dbManager)
+	bptManager.SetCacheSize(4)
+	it := bptManager.Iterate()
+	for i := 0; i < count/2; i++ {
+		if !it.Next() {
+			t.Fatalf("iteration stopped at %d: %v", i, it.Err())
+		}
+		if it.Value().Key != keys[i] {
+			t.Fatalf("value %d is out of order", i)
+		}
+	}
+
+	// Resume from the cursor after an update
+	cursor := it.Cursor()
+	extra := sha256.Sum256([]byte("extra"))
+	bptManager.InsertKV(extra, extra)
+	bptManager.Bpt.Update()
+	if Compare(extra, cursor) > 0 {
+		keys = append(keys, extra)
+		sort.Slice(keys, func(i, j int) bool { return Compare(keys[i], keys[j]) < 0 })
+	}
+
+	it = bptManager.IterateAfter(cursor)
+	i := count / 2
+	for ; it.Next(); i++ {
+		if it.Value().Key != keys[i] {
+			t.Fatalf("value %d is out of order", i)
+		}
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if i != len(keys) {
+		t.Fatalf("visited %d values, want %d", i, len(keys))
+	}
+}
+
+func TestRange(t *testing.T) {
+	const count = 3000
+	const limit = 700
+
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+
+	// An empty BPT has an empty range
+	r, err := bptManager.GetRange([32]byte{}, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Validate(); err != nil || len(r.Values) != 0 {
+		t.Fatalf("invalid empty range: %v", err)
+	}
+
+	for i := 0; i < count; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		bptManager.InsertKV(key, sha256.Sum256(key[:]))
+	}
+	bptManager.Bpt.Update()
+	bptManager = NewBPTManager(dbManager)
+
+	// Export the BPT in chunks
+	var start [32]byte
+	var total int
+	for {
+		r, err := bptManager.GetRange(start, nil, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err = r.Validate(); err != nil {
+			t.Fatalf("range from %x: %v", start, err)
+		}
+
+		data, err := r.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		r2 := new(Range)
+		if err = r2.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+		if err = r2.Validate(); err != nil || len(r2.Values) != len(r.Values) {
+			t.Fatalf("range from %x does not survive binary encoding: %v", start, err)
+		}
+
+		total += len(r.Values)
+		if r.After == nil {
+			break
+		}
+		if len(r.Values) != limit {
+			t.Fatalf("range from %x has %d values, want %d", start, len(r.Values), limit)
+		}
+		start = r.End
+	}
+	if total != count {
+		t.Fatalf("ranges hold %d values, want %d", total, count)
+	}
+
+	// A range between two keys that may not be in the BPT
+	start = sha256.Sum256([]byte("start"))
+	end := sha256.Sum256([]byte("end"))
+	if Compare(start, end) > 0 {
+		start, end = end, start
+	}
+	r, err = bptManager.GetRange(start, &end, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Values) < 2 {
+		t.Fatalf("expected values between %x and %x", start, end)
+	}
+
+	// Tampering is detected
+	dropped := *r
+	dropped.Values = append(append([]*Value{}, r.Values[:1]...), r.Values[2:]...)
+	if dropped.Validate() == nil {
+		t.Fatal("a range missing a value is valid")
+	}
+	changed := *r
+	changed.Values = append([]*Value{}, r.Values...)
+	changed.Values[1] = &Value{Key: r.Values[1].Key, Hash: sha256.Sum256(nil)}
+	if changed.Validate() == nil {
+		t.Fatal("a range with a changed value is valid")
+	}
+	moved := *r
+	moved.End = r.Values[len(r.Values)-1].Key
+	if moved.Validate() == nil {
+		t.Fatal("a range with the wrong end is valid")
+	}
+
+	// The first value cannot be dropped by passing it off as a sibling of a
+	// forged bound, or by using its proof as the bound
+	forged := *r
+	forged.Values = r.Values[1:]
+	forged.Before, _ = bptManager.GetProof(r.Values[0].Key)
+	if forged.Validate() == nil {
+		t.Fatal("a range with the first value as its bound is valid")
+	}
+	forged.Before.Siblings = append(forged.Before.Siblings, &Sibling{Height: forged.Before.Depth})
+	copy(forged.Before.Siblings[len(forged.Before.Siblings)-1].Hash[:], r.Values[0].GetHash())
+	forged.Before.Key, forged.Before.Leaf = start, nil
+	forged.Before.Depth++
+	if forged.Validate() == nil {
+		t.Fatal("a range with a forged bound and the first value dropped is valid")
+	}
+	forged.Before = r.Before
+	if forged.Validate() == nil {
+		t.Fatal("a range with the first value dropped is valid")
+	}
+}
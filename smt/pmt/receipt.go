@@ -0,0 +1,25 @@
+package pmt
+
+import (
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// GetReceipt
+// Returns a receipt that proves that the value with the given key is in the
+// BPT.  The element of the receipt is the hash of the value (see
+// Value.GetHash) and the anchor is the root hash of the BPT, so the receipt is
+// only good as of the last Update.  Byte Blocks are loaded from the database
+// as needed.
+func (m *Manager) GetReceipt(key [32]byte) (*managed.Receipt, error) {
+	proof, err := m.GetProof(key)
+	if err != nil {
+		return nil, err
+	}
+	if !proof.Included() {
+		return nil, fmt.Errorf("%w: key %X is not in the BPT", storage.ErrNotFound, key)
+	}
+	return proof.Receipt(), nil
+}
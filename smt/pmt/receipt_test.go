@@ -0,0 +1,54 @@
+package pmt
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/smt/storage/database"
+)
+
+func TestGetReceipt(t *testing.T) {
+	const count = 5000
+
+	dbManager, err := database.NewDBManager("memory", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bptManager := NewBPTManager(dbManager)
+	for i := 0; i < count; i++ {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		value := sha256.Sum256([]byte(fmt.Sprintf("value %d", i)))
+		bptManager.InsertKV(key, value)
+	}
+	bptManager.Bpt.Update()
+	dbManager.EndBatch()
+
+	// Reload the BPT so the receipts have to load Byte Blocks
+	bptManager = NewBPTManager(dbManager)
+	for i := 0; i < count; i += 7 {
+		key := sha256.Sum256([]byte(fmt.Sprintf("key %d", i)))
+		value := sha256.Sum256([]byte(fmt.Sprintf("value %d", i)))
+		r, err := bptManager.GetReceipt(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf := sha256.Sum256(append(key[:], value[:]...))
+		if !r.Element.Equal(leaf[:]) {
+			t.Fatalf("receipt %d does not start at the value", i)
+		}
+		if !r.Anchor.Equal(bptManager.Bpt.Root.Hash[:]) {
+			t.Fatalf("receipt %d does not lead to the root", i)
+		}
+		if !r.Validate() {
+			t.Fatalf("receipt %d is not valid", i)
+		}
+	}
+
+	_, err = bptManager.GetReceipt(sha256.Sum256([]byte("missing")))
+	if !errors.Is(err, storage.ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
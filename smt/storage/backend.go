@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Options tunes a storage backend. A backend ignores the options it does not
+// support, and zero sizes select the backend's defaults.
+type Options struct {
+	CacheSize        int64 // Bytes of memory the backend may use to cache data
+	SyncWrites       bool  // Sync every write to disk before returning
+	ValueLogFileSize int64 // Size of each value log file, for backends that have them
+}
+
+// DefaultOptions returns the options backends are opened with unless the
+// caller specifies otherwise.
+func DefaultOptions() Options {
+	return Options{SyncWrites: true}
+}
+
+// Backend opens or creates a database at the path with the given options.
+type Backend func(path string, opts Options) (KeyValueDB, error)
+
+var backends = map[string]Backend{}
+var backendsMu sync.RWMutex
+
+// RegisterBackend makes a backend available to Open. RegisterBackend is meant
+// to be called from the init function of the package implementing the
+// backend, and panics if the name is already taken.
+func RegisterBackend(name string, backend Backend) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+	if _, ok := backends[name]; ok {
+		panic(fmt.Errorf("storage backend %q is already registered", name))
+	}
+	backends[name] = backend
+}
+
+// Backends returns the names of the registered backends, sorted.
+func Backends() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+	names := make([]string, 0, len(backends))
+	for name := range backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Open opens a database with the named backend.
+func Open(backend, path string, opts Options) (KeyValueDB, error) {
+	backendsMu.RLock()
+	open, ok := backends[backend]
+	backendsMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown storage backend %q, expected one of %v", backend, Backends())
+	}
+	return open(path, opts)
+}
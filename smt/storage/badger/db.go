@@ -2,6 +2,7 @@ package badger
 
 import (
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
@@ -14,6 +15,21 @@ type DB struct {
 }
 
 var _ storage.KeyValueDB = (*DB)(nil)
+var _ storage.Viewer = (*DB)(nil)
+
+func init() {
+	storage.RegisterBackend("badger", func(path string, opts storage.Options) (storage.KeyValueDB, error) {
+		db := new(DB)
+		err := db.InitDBWithOptions(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	})
+}
+
+// journalKey is where a batch is recorded while it is being written in parts
+var journalKey = storage.ComputeKey("Journal")
 
 // Close
 // Close the underlying database
@@ -26,18 +42,35 @@ func (d *DB) Close() error {
 // This will certainly open an existing database, but will also initialize
 // an new, empty database.
 func (d *DB) InitDB(filepath string) error {
+	return d.InitDBWithOptions(filepath, storage.DefaultOptions())
+}
+
+// InitDBWithOptions
+// Initialize the database like InitDB, tuned by the given options.  This
+// version of Badger memory maps its tables and has no block or index cache,
+// so the cache size is ignored.
+func (d *DB) InitDBWithOptions(filepath string, opts storage.Options) error {
 	// Make sure all directories exist
 	err := os.MkdirAll(filepath, 0777)
 	if err != nil {
 		return errors.New("failed to create home directory")
 	}
 	d.DBHome = filepath
+
+	bopts := badger.DefaultOptions(d.DBHome)
+	bopts.SyncWrites = opts.SyncWrites
+	if opts.ValueLogFileSize > 0 {
+		bopts.ValueLogFileSize = opts.ValueLogFileSize
+	}
+
 	// Open Badger
-	d.badgerDB, err = badger.Open(badger.DefaultOptions(d.DBHome))
+	d.badgerDB, err = badger.Open(bopts)
 	if err != nil { // Panic if we can't open Badger
 		return err
 	}
-	return nil
+
+	// Complete the last batch if it was interrupted
+	return d.recoverJournal()
 }
 
 // Get
@@ -45,28 +78,25 @@ func (d *DB) InitDB(filepath string) error {
 // is found for the given key
 func (d *DB) Get(key storage.Key) (value []byte, err error) {
 	err = d.badgerDB.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key[:])
-		if err != nil {
-			return err
-		}
-		err = item.Value(func(val []byte) error {
-			value = append(value, val...)
-			return nil
-		})
+		value, err = get(txn, key)
 		return err
 	})
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
 
-	// If we didn't find the value, return ErrNotFound
+// get returns a copy of the value of the key, or storage.ErrNotFound if the
+// key is not found
+func get(txn *badger.Txn, key storage.Key) ([]byte, error) {
+	item, err := txn.Get(key[:])
 	if errors.Is(err, badger.ErrKeyNotFound) {
 		return nil, storage.ErrNotFound
-	}
-
-	// If anything goes wrong, return nil
-	if err != nil {
+	} else if err != nil {
 		return nil, err
 	}
-
-	return value, nil
+	return item.ValueCopy(nil)
 }
 
 // Put
@@ -81,10 +111,99 @@ func (d *DB) Put(key storage.Key, value []byte) error {
 	return err
 }
 
-// PutBatch
-// Write all the transactions in a given batch of pending transactions.  The
-// batch is emptied, so it can be reused.
+// Delete
+// Delete a key from the database.  Deleting a key that does not exist is not
+// an error.
+func (d *DB) Delete(key storage.Key) error {
+	return d.badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(key[:])
+	})
+}
+
+// Iterate
+// Call fn for each key that starts with the prefix.  Badger keeps keys sorted,
+// so the keys are visited in order.  The value passed to fn is a copy.
+func (d *DB) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	return d.badgerDB.View(func(txn *badger.Txn) error {
+		return iterate(txn, prefix, fn)
+	})
+}
+
+func iterate(txn *badger.Txn, prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+
+		// Skip anything that is not a storage key, such as the keys Badger
+		// uses internally
+		if len(item.Key()) != storage.KeyLength {
+			continue
+		}
+
+		var key storage.Key
+		copy(key[:], item.Key())
+		value, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// View
+// Take a read-only view of the database, backed by a Badger read transaction.
+// Badger cannot garbage collect the value log entries a view may read, so a
+// view should not be kept open longer than needed.
+func (d *DB) View() (storage.KeyValueDB, error) {
+	return &view{d.badgerDB.NewTransaction(false)}, nil
+}
+
+// view is a read-only view of the database
+type view struct {
+	txn *badger.Txn
+}
+
+func (v *view) Close() error                          { v.txn.Discard(); return nil }
+func (v *view) InitDB(string) error                   { return storage.ErrReadOnly }
+func (v *view) Get(key storage.Key) ([]byte, error)   { return get(v.txn, key) }
+func (v *view) Put(storage.Key, []byte) error         { return storage.ErrReadOnly }
+func (v *view) Delete(storage.Key) error              { return storage.ErrReadOnly }
+func (v *view) EndBatch(map[storage.Key][]byte) error { return storage.ErrReadOnly }
+
+func (v *view) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	return iterate(v.txn, prefix, fn)
+}
+
+// EndBatch
+// Write all the transactions in a given batch of pending transactions
+// atomically.  A batch is normally written in a single Badger transaction.  If
+// the batch is too big for one transaction, it is first written to a journal,
+// then written in parts, then the journal is removed.  If the process stops
+// before the journal is removed, InitDB completes the batch from the journal.
 func (d *DB) EndBatch(TXCache map[storage.Key][]byte) error {
+	err := d.endBatch(TXCache)
+	if !errors.Is(err, badger.ErrTxnTooBig) {
+		return err
+	}
+
+	err = d.Put(journalKey, storage.MarshalBatch(TXCache))
+	if err != nil {
+		return err
+	}
+
+	return d.replayJournal(TXCache)
+}
+
+// endBatch writes the batch in a single transaction
+func (d *DB) endBatch(TXCache map[storage.Key][]byte) error {
 	txn := d.badgerDB.NewTransaction(true)
 	defer txn.Discard()
 
@@ -104,6 +223,12 @@ func (d *DB) EndBatch(TXCache map[storage.Key][]byte) error {
 		// copy instead of the original. See also:
 		// https://github.com/golang/go/wiki/CommonMistakes#using-reference-to-loop-iterator-variable
 		k := k
+		if v == nil {
+			if err := txn.Delete(k[:]); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := txn.Set(k[:], v); err != nil {
 			return err
 		}
@@ -115,3 +240,50 @@ func (d *DB) EndBatch(TXCache map[storage.Key][]byte) error {
 
 	return nil
 }
+
+// replayJournal writes a journaled batch in as many transactions as needed and
+// then removes the journal.  Writing a batch twice has the same effect as
+// writing it once, so an interrupted replay can be repeated.
+func (d *DB) replayJournal(batch map[storage.Key][]byte) error {
+	wb := d.badgerDB.NewWriteBatch()
+	defer wb.Cancel()
+
+	for k, v := range batch {
+		k := k // See endBatch
+		var err error
+		if v == nil {
+			err = wb.Delete(k[:])
+		} else {
+			err = wb.Set(k[:], v)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	err := wb.Flush()
+	if err != nil {
+		return err
+	}
+
+	return d.badgerDB.Update(func(txn *badger.Txn) error {
+		return txn.Delete(journalKey[:])
+	})
+}
+
+// recoverJournal completes a batch that was interrupted
+func (d *DB) recoverJournal() error {
+	data, err := d.Get(journalKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	batch, err := storage.UnmarshalBatch(data)
+	if err != nil {
+		return fmt.Errorf("failed to recover interrupted batch: %v", err)
+	}
+
+	return d.replayJournal(batch)
+}
@@ -1,12 +1,16 @@
 package badger
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
 
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/dgraph-io/badger/v3"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDatabase(t *testing.T) {
@@ -102,3 +106,80 @@ func TestDatabase2(t *testing.T) {
 		}
 	}
 }
+
+func TestEndBatchTooBig(t *testing.T) {
+	db := new(DB)
+	require.NoError(t, db.InitDB(t.TempDir()))
+	defer db.Close()
+
+	// More entries than fit in a single Badger transaction
+	batch := map[storage.Key][]byte{}
+	for i := int64(0); i < db.badgerDB.MaxBatchCount()+10; i++ {
+		batch[storage.ComputeKey("key", i)] = common.Int64Bytes(i)
+	}
+	require.NoError(t, db.EndBatch(batch))
+
+	for k, v := range batch {
+		u, err := db.Get(k)
+		require.NoError(t, err)
+		require.Equal(t, v, u)
+	}
+
+	_, err := db.Get(journalKey)
+	require.True(t, errors.Is(err, storage.ErrNotFound), "the journal should be removed")
+}
+
+func TestRecoverJournal(t *testing.T) {
+	dir := t.TempDir()
+	db := new(DB)
+	require.NoError(t, db.InitDB(dir))
+
+	keep, drop, empty := storage.ComputeKey("keep"), storage.ComputeKey("drop"), storage.ComputeKey("empty")
+	require.NoError(t, db.Put(drop, []byte{1}))
+
+	// Simulate a batch that was interrupted after the journal was written
+	batch := map[storage.Key][]byte{keep: {2}, drop: nil, empty: {}}
+	require.NoError(t, db.Put(journalKey, storage.MarshalBatch(batch)))
+	require.NoError(t, db.Close())
+
+	db = new(DB)
+	require.NoError(t, db.InitDB(dir))
+	defer db.Close()
+
+	v, err := db.Get(keep)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, v)
+	_, err = db.Get(empty)
+	require.NoError(t, err)
+	_, err = db.Get(drop)
+	require.True(t, errors.Is(err, storage.ErrNotFound), "the key should be deleted")
+	_, err = db.Get(journalKey)
+	require.True(t, errors.Is(err, storage.ErrNotFound), "the journal should be removed")
+}
+
+func TestView(t *testing.T) {
+	db := new(DB)
+	require.NoError(t, db.InitDB(t.TempDir()))
+	defer db.Close()
+
+	key := storage.ComputeKey("key")
+	require.NoError(t, db.Put(key, []byte{1}))
+
+	view, err := db.View()
+	require.NoError(t, err)
+	defer view.Close()
+
+	// Writes after the view is taken are not seen by the view
+	require.NoError(t, db.EndBatch(map[storage.Key][]byte{key: {2}, storage.ComputeKey("other"): {3}}))
+
+	v, err := view.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+	_, err = view.Get(storage.ComputeKey("other"))
+	require.True(t, errors.Is(err, storage.ErrNotFound), "expected not found, got %v", err)
+	require.True(t, errors.Is(view.Put(key, []byte{4}), storage.ErrReadOnly))
+
+	v, err = db.Get(key)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, v)
+}
@@ -0,0 +1,56 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/internal/encoding"
+)
+
+// MarshalBatch serializes a batch of writes. Each entry is the key, a flag that
+// is set if the key is deleted, and the value.
+func MarshalBatch(batch map[Key][]byte) []byte {
+	var data []byte
+	for k, v := range batch {
+		data = append(data, k[:]...)
+		if v == nil {
+			data = append(data, 1)
+		} else {
+			data = append(data, 0)
+		}
+		data = append(data, encoding.BytesMarshalBinary(v)...)
+	}
+	return data
+}
+
+// UnmarshalBatch deserializes a batch of writes serialized by MarshalBatch.
+// Deleted keys have a nil value.
+func UnmarshalBatch(data []byte) (map[Key][]byte, error) {
+	batch := map[Key][]byte{}
+	for len(data) > 0 {
+		if len(data) < KeyLength+1 {
+			return nil, errors.New("batch is truncated")
+		}
+
+		var k Key
+		copy(k[:], data)
+		deleted := data[KeyLength] == 1
+		data = data[KeyLength+1:]
+
+		v, err := encoding.BytesUnmarshalBinary(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch entry %X: %v", k, err)
+		}
+		data = data[encoding.BytesBinarySize(v):]
+
+		switch {
+		case deleted:
+			batch[k] = nil
+		case v == nil:
+			batch[k] = []byte{}
+		default:
+			batch[k] = v
+		}
+	}
+	return batch, nil
+}
@@ -0,0 +1,182 @@
+package bolt
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/boltdb/bolt"
+)
+
+func init() {
+	storage.RegisterBackend("bolt", func(path string, opts storage.Options) (storage.KeyValueDB, error) {
+		db := new(DB)
+		err := db.InitDBWithOptions(path, opts)
+		if err != nil {
+			return nil, err
+		}
+		return db, nil
+	})
+}
+
+// bucket is the Bolt bucket every key is stored in
+var bucket = []byte("accumulate")
+
+// DB
+// Implements a key value store on a Bolt B-tree.  Bolt keeps the database in a
+// single memory mapped file and commits each transaction atomically.
+type DB struct {
+	DBHome string
+	boltDB *bolt.DB
+}
+
+var _ storage.KeyValueDB = (*DB)(nil)
+var _ storage.Viewer = (*DB)(nil)
+
+// Close
+// Close the underlying database
+func (d *DB) Close() error {
+	return d.boltDB.Close()
+}
+
+// InitDB
+// Open the database file at the given path, creating it if it does not exist.
+func (d *DB) InitDB(filepath string) error {
+	return d.InitDBWithOptions(filepath, storage.DefaultOptions())
+}
+
+// InitDBWithOptions
+// Open the database like InitDB, tuned by the given options.  The cache size is
+// the initial size of the memory map.
+func (d *DB) InitDBWithOptions(path string, opts storage.Options) error {
+	// Make sure the directory exists
+	err := os.MkdirAll(filepath.Dir(path), 0777)
+	if err != nil {
+		return errors.New("failed to create home directory")
+	}
+	d.DBHome = path
+
+	bopts := new(bolt.Options)
+	*bopts = *bolt.DefaultOptions
+	bopts.InitialMmapSize = int(opts.CacheSize)
+	d.boltDB, err = bolt.Open(path, 0600, bopts)
+	if err != nil {
+		return err
+	}
+	d.boltDB.NoSync = !opts.SyncWrites
+
+	return d.boltDB.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	})
+}
+
+// Get
+// Returns the value for a key, or storage.ErrNotFound if the key is not found
+func (d *DB) Get(key storage.Key) (value []byte, err error) {
+	err = d.boltDB.View(func(tx *bolt.Tx) error {
+		value, err = get(tx, key)
+		return err
+	})
+	return value, err
+}
+
+func get(tx *bolt.Tx, key storage.Key) ([]byte, error) {
+	v := tx.Bucket(bucket).Get(key[:])
+	if v == nil {
+		return nil, storage.ErrNotFound
+	}
+	// Bolt values are only valid for the life of the transaction
+	return append([]byte{}, v...), nil
+}
+
+// Put
+// Put a key/value in the database
+func (d *DB) Put(key storage.Key, value []byte) error {
+	return d.boltDB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Put(key[:], value)
+	})
+}
+
+// Delete
+// Delete a key from the database.  Deleting a key that does not exist is not
+// an error.
+func (d *DB) Delete(key storage.Key) error {
+	return d.boltDB.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucket).Delete(key[:])
+	})
+}
+
+// EndBatch
+// Write a batch in a single transaction.  Bolt transactions are not limited in
+// size, so every batch is atomic.
+func (d *DB) EndBatch(batch map[storage.Key][]byte) error {
+	return d.boltDB.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucket)
+		for k, v := range batch {
+			k := k // Bolt keeps a reference to the key until the commit
+			var err error
+			if v == nil {
+				err = b.Delete(k[:])
+			} else {
+				err = b.Put(k[:], v)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Iterate
+// Call fn for each key that starts with the prefix.  Bolt keeps keys sorted,
+// so the keys are visited in order.  The value passed to fn is a copy.
+func (d *DB) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	return d.boltDB.View(func(tx *bolt.Tx) error {
+		return iterate(tx, prefix, fn)
+	})
+}
+
+func iterate(tx *bolt.Tx, prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	c := tx.Bucket(bucket).Cursor()
+	for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+		var key storage.Key
+		copy(key[:], k)
+		if err := fn(key, append([]byte{}, v...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// View
+// Take a read-only view of the database, backed by a Bolt read transaction.
+// Bolt cannot grow its memory map while a read transaction is open, so a write
+// that needs a bigger file waits until the view is closed.  The cache size
+// should leave room for the writes made while a view is open.
+func (d *DB) View() (storage.KeyValueDB, error) {
+	tx, err := d.boltDB.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &view{tx}, nil
+}
+
+// view is a read-only view of the database
+type view struct {
+	tx *bolt.Tx
+}
+
+func (v *view) Close() error                          { return v.tx.Rollback() }
+func (v *view) InitDB(string) error                   { return storage.ErrReadOnly }
+func (v *view) Get(key storage.Key) ([]byte, error)   { return get(v.tx, key) }
+func (v *view) Put(storage.Key, []byte) error         { return storage.ErrReadOnly }
+func (v *view) Delete(storage.Key) error              { return storage.ErrReadOnly }
+func (v *view) EndBatch(map[storage.Key][]byte) error { return storage.ErrReadOnly }
+
+func (v *view) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	return iterate(v.tx, prefix, fn)
+}
@@ -2,12 +2,17 @@ package database
 
 import (
 	"bytes"
+	"errors"
+	"sort"
 	"sync"
 
 	"github.com/AccumulateNetwork/accumulate/smt/common"
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
-	"github.com/AccumulateNetwork/accumulate/smt/storage/badger"
-	"github.com/AccumulateNetwork/accumulate/smt/storage/memory"
+
+	// Register the storage backends
+	_ "github.com/AccumulateNetwork/accumulate/smt/storage/badger"
+	_ "github.com/AccumulateNetwork/accumulate/smt/storage/bolt"
+	_ "github.com/AccumulateNetwork/accumulate/smt/storage/memory"
 )
 
 // Manager
@@ -73,20 +78,21 @@ func (m *Manager) init() {
 
 // Init
 // Initialize the Manager with a specified underlying database. databaseTag
-// can currently be either badger or memory.  The filename indicates where
-// the database is persisted (ignored by memory).PendingChain
+// is the name of a registered storage backend, such as badger, bolt, or
+// memory.  The filename indicates where the database is persisted (ignored by
+// memory).
 func (m *Manager) Init(databaseTag, filename string) error {
-	m.init()
-	switch databaseTag { //                              match with a supported databaseTag
-	case "badger": //                                    Badger database indicated
-		m.DB = new(badger.DB)                         // Create a badger struct
-		if err := m.DB.InitDB(filename); err != nil { // Initialize it with the given filename
-			return err
-		}
-	case "memory": //                                    memory database indicated
-		m.DB = new(memory.DB)     //                     Allocate the structure
-		_ = m.DB.InitDB(filename) //                     filename is ignored, but must allocate the underlying map
+	return m.InitWithOptions(databaseTag, filename, storage.DefaultOptions())
+}
+
+// InitWithOptions
+// Initialize the Manager like Init, with the backend tuned by the options.
+func (m *Manager) InitWithOptions(databaseTag, filename string, opts storage.Options) error {
+	db, err := storage.Open(databaseTag, filename, opts)
+	if err != nil {
+		return err
 	}
+	m.InitWithDB(db)
 	return nil
 }
 
@@ -124,6 +130,11 @@ func (k KeyRef) Get() ([]byte, error) {
 	k.M.cacheMu.RLock()
 	defer k.M.cacheMu.RUnlock()
 	if v, ok := k.M.txCache[k.K]; ok {
+		// A nil value is a pending delete
+		if v == nil {
+			return nil, storage.ErrNotFound
+		}
+
 		// Return a copy. Otherwise the caller could change it, and that would
 		// change what's in the cache.
 		u := make([]byte, len(v))
@@ -134,6 +145,12 @@ func (k KeyRef) Get() ([]byte, error) {
 	return k.M.DB.Get(k.K)
 }
 
+// Delete
+// Remove the key from the underlying database
+func (k KeyRef) Delete() error {
+	return k.M.DB.Delete(k.K)
+}
+
 func (k KeyRef) PutBatch(value []byte) {
 	k.M.cacheMu.Lock()
 	defer k.M.cacheMu.Unlock()
@@ -144,6 +161,97 @@ func (k KeyRef) PutBatch(value []byte) {
 	k.M.txCache[k.K] = u
 }
 
+// DeleteBatch
+// Queue the removal of the key from the database. The key is removed when the
+// batch is flushed.
+func (k KeyRef) DeleteBatch() {
+	k.M.cacheMu.Lock()
+	defer k.M.cacheMu.Unlock()
+	k.M.txCache[k.K] = nil
+}
+
+// Iterate
+// Calls fn for every key that starts with the prefix, in ascending order. Like
+// Get, Iterate sees the pending batch: keys written by the batch are visited
+// with their pending values and keys deleted by the batch are skipped.
+func (m *Manager) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	// Take a sorted copy of the pending keys that match
+	m.cacheMu.RLock()
+	var pending []storage.Key
+	values := map[storage.Key][]byte{}
+	for k, v := range m.txCache {
+		if bytes.HasPrefix(k[:], prefix) {
+			pending = append(pending, k)
+			values[k] = v
+		}
+	}
+	m.cacheMu.RUnlock()
+	sort.Slice(pending, func(i, j int) bool {
+		return bytes.Compare(pending[i][:], pending[j][:]) < 0
+	})
+
+	// Visit pending keys up to (and excluding) the given key
+	flush := func(until *storage.Key) error {
+		for len(pending) > 0 && (until == nil || bytes.Compare(pending[0][:], until[:]) < 0) {
+			k := pending[0]
+			pending = pending[1:]
+			if values[k] == nil {
+				continue
+			}
+			if err := fn(k, values[k]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err := m.DB.Iterate(prefix, func(key storage.Key, value []byte) error {
+		if err := flush(&key); err != nil {
+			return err
+		}
+
+		// The pending value replaces the stored value
+		if len(pending) > 0 && pending[0] == key {
+			pending = pending[1:]
+			value = values[key]
+			if value == nil {
+				return nil
+			}
+		}
+		return fn(key, value)
+	})
+	if err != nil {
+		return err
+	}
+	return flush(nil)
+}
+
+// UndoBatch
+// Returns a batch that reverts the pending batch, i.e. the value every key of
+// the pending batch has in the database, or nil if the key is not in the
+// database.
+func (m *Manager) UndoBatch() (map[storage.Key][]byte, error) {
+	m.cacheMu.RLock()
+	defer m.cacheMu.RUnlock()
+
+	undo := make(map[storage.Key][]byte, len(m.txCache))
+	for k := range m.txCache {
+		v, err := m.DB.Get(k)
+		switch {
+		case err == nil:
+			if v == nil {
+				v = []byte{}
+			}
+			undo[k] = v
+		case errors.Is(err, storage.ErrNotFound):
+			undo[k] = nil
+		default:
+			return nil, err
+		}
+	}
+	return undo, nil
+}
+
 // EndBatch
 // Flush anything in the batch list to the database.
 func (m *Manager) EndBatch() {
@@ -8,9 +8,11 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/exp/rand"
 )
@@ -32,16 +34,34 @@ func TestDBManager_TransactionsBadger(t *testing.T) {
 	} else {
 		writeAndRead(t, dbManager)
 		writeAndReadBatch(t, dbManager)
-
+		deleteBatch(t, dbManager)
+		iterate(t, dbManager)
 	}
 }
 
+func TestDBManager_TransactionsBolt(t *testing.T) {
+	dbManager := new(Manager)
+	require.NoError(t, dbManager.Init("bolt", filepath.Join(t.TempDir(), "bolt.db")))
+	defer dbManager.Close()
+
+	writeAndRead(t, dbManager)
+	writeAndReadBatch(t, dbManager)
+	deleteBatch(t, dbManager)
+	iterate(t, dbManager)
+}
+
+func TestDBManager_UnknownBackend(t *testing.T) {
+	require.Error(t, new(Manager).Init("nope", ""))
+}
+
 func TestDBManager_TransactionsMemory(t *testing.T) {
 
 	dbManager := new(Manager)
 	_ = dbManager.Init("memory", "")
 	writeAndReadBatch(t, dbManager)
 	writeAndRead(t, dbManager)
+	deleteBatch(t, dbManager)
+	iterate(t, dbManager)
 	dbManager.Close()
 }
 
@@ -125,3 +145,57 @@ func writeAndRead(t *testing.T, dbManager *Manager) {
 	}
 
 }
+
+func deleteBatch(t *testing.T, dbManager *Manager) {
+	require.NoError(t, dbManager.Key("d", "keep").Put([]byte{1}))
+	require.NoError(t, dbManager.Key("d", "drop").Put([]byte{2}))
+
+	// The delete is visible before the batch is flushed
+	dbManager.Key("d", "drop").DeleteBatch()
+	_, err := dbManager.Key("d", "drop").Get()
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	dbManager.EndBatch()
+	_, err = dbManager.DB.Get(dbManager.Key("d", "drop").K)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	v, err := dbManager.Key("d", "keep").Get()
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, v)
+}
+
+func iterate(t *testing.T, dbManager *Manager) {
+	key := func(ns string, i uint64) KeyRef {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], i)
+		return dbManager.Key(storage.NamespacedKey(ns, b[:]))
+	}
+	list := func() []string {
+		var entries []string
+		err := dbManager.Iterate(storage.Namespace("e"), func(k storage.Key, v []byte) error {
+			entries = append(entries, string(v))
+			return nil
+		})
+		require.NoError(t, err)
+		return entries
+	}
+
+	// Keys are visited in the order of their suffixes
+	for _, i := range []uint64{3, 1, 2, 300} {
+		require.NoError(t, key("e", i).Put([]byte(fmt.Sprint(i))))
+	}
+	require.NoError(t, key("f", 0).Put([]byte("other namespace")))
+	require.Equal(t, []string{"1", "2", "3", "300"}, list())
+
+	// The pending batch is merged with the database
+	key("e", 0).PutBatch([]byte("0"))
+	key("e", 2).PutBatch([]byte("two"))
+	key("e", 3).DeleteBatch()
+	key("e", 400).PutBatch([]byte("400"))
+	require.Equal(t, []string{"0", "1", "two", "300", "400"}, list())
+
+	dbManager.EndBatch()
+	require.Equal(t, []string{"0", "1", "two", "300", "400"}, list())
+
+	require.NoError(t, key("e", 1).Delete())
+	require.Equal(t, []string{"0", "two", "300", "400"}, list())
+}
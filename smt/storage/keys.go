@@ -9,7 +9,8 @@ import (
 )
 
 const (
-	KeyLength = 32 // Total bytes used for keys
+	KeyLength       = 32 // Total bytes used for keys
+	NamespaceLength = 8  // Bytes of a namespaced key that identify the namespace
 )
 
 // var ElementIndexKey = []byte("ElementIndex")
@@ -87,3 +88,26 @@ func ComputeKey(keys ...interface{}) Key {
 
 	return sha256.Sum256(composite)
 }
+
+// Namespace returns the prefix shared by all the keys of the namespace. Use it
+// with KeyValueDB.Iterate to enumerate the namespace.
+func Namespace(name string) []byte {
+	h := sha256.Sum256([]byte(name))
+	return h[:NamespaceLength]
+}
+
+// NamespacedKey returns the key for the suffix in the namespace. A suffix of up
+// to KeyLength-NamespaceLength bytes is stored as is, so the keys of a
+// namespace iterate in the order of their suffixes. Longer suffixes are
+// hashed. Suffixes are zero-padded, so a namespace that must be ordered should
+// use suffixes of a fixed length, such as big-endian integers.
+func NamespacedKey(name string, suffix []byte) Key {
+	var k Key
+	copy(k[:], Namespace(name))
+	if len(suffix) > KeyLength-NamespaceLength {
+		h := sha256.Sum256(suffix)
+		suffix = h[:KeyLength-NamespaceLength]
+	}
+	copy(k[NamespaceLength:], suffix)
+	return k
+}
@@ -5,10 +5,27 @@ import "errors"
 // ErrNotFound is returned by KeyValueDB.Get if the key is not found
 var ErrNotFound = errors.New("not found")
 
+// ErrReadOnly is returned by the writes of a read-only view of a database
+var ErrReadOnly = errors.New("read-only")
+
 type KeyValueDB interface {
 	Close() error                          // Returns an error if the close fails
 	InitDB(filepath string) error          // Sets up the database, returns error if it fails
 	Get(key Key) (value []byte, err error) // Get key from database, returns ErrNotFound if the key is not found
 	Put(key Key, value []byte) error       // Put the value in the database, throws an error if fails
-	EndBatch(map[Key][]byte) error         // End and commit a batch of transactions
+	Delete(key Key) error                  // Delete the key from the database, does nothing if the key is not found
+	EndBatch(map[Key][]byte) error         // End and atomically commit a batch of transactions, a nil value deletes the key
+
+	// Iterate calls fn for every key that starts with the prefix, in ascending
+	// order of the keys. Iteration stops at the first error returned by fn,
+	// which is returned by Iterate. fn must not write to the database.
+	Iterate(prefix []byte, fn func(key Key, value []byte) error) error
+}
+
+// A Viewer is a KeyValueDB that can take a consistent, read-only view of its
+// contents. Writes to the database after the view is taken are not seen by
+// the view, and writes to the view return ErrReadOnly. A view must be closed
+// once it is no longer needed, and closing it does not close the database.
+type Viewer interface {
+	View() (KeyValueDB, error)
 }
@@ -9,6 +9,13 @@ import (
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
 )
 
+func init() {
+	storage.RegisterBackend("memory", func(_ string, _ storage.Options) (storage.KeyValueDB, error) {
+		db := new(DB)
+		return db, db.InitDB("")
+	})
+}
+
 // DB
 // Implements a key value store in memory.  Very basic, assumes no initial
 // state for the database That must be handled by the caller, but see the
@@ -21,11 +28,16 @@ type DB struct {
 // EndBatch
 // Takes all the key value pairs collected in a cache of mapped values and
 // adds them to the database.  The assumption here is that the order in which
-// the cache is applied to a key value store does not matter.
+// the cache is applied to a key value store does not matter.  A nil value
+// removes the key.
 func (m *DB) EndBatch(txCache map[storage.Key][]byte) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 	for k, v := range txCache {
+		if v == nil {
+			delete(m.entries, k)
+			continue
+		}
 		m.entries[k] = v
 	}
 	return nil
@@ -65,6 +77,13 @@ func (m *DB) Copy() *DB {
 	return db
 }
 
+// View
+// Take a view of the database.  The view of a memory database is a copy,
+// which is not read-only.
+func (m *DB) View() (storage.KeyValueDB, error) {
+	return m.Copy(), nil
+}
+
 // Close
 // Nothing really to do but to clear the Entries map
 func (m *DB) Close() error {
@@ -118,6 +137,41 @@ func (m *DB) Put(key storage.Key, value []byte) error {
 	return nil
 }
 
+// Delete
+// Removes a key from the database
+func (m *DB) Delete(key storage.Key) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+// Iterate
+// Calls fn for each key that starts with the prefix, in order. The matching
+// entries are copied before fn is called, so the lock is not held by fn.
+func (m *DB) Iterate(prefix []byte, fn func(key storage.Key, value []byte) error) error {
+	m.mutex.Lock()
+	var keys []storage.Key
+	values := map[storage.Key][]byte{}
+	for k, v := range m.entries {
+		if bytes.HasPrefix(k[:], prefix) {
+			keys = append(keys, k)
+			values[k] = v
+		}
+	}
+	m.mutex.Unlock()
+
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i][:], keys[j][:]) < 0
+	})
+	for _, k := range keys {
+		if err := fn(k, values[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (m *DB) MarshalBinary() ([]byte, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
@@ -3,12 +3,17 @@ package query
 import (
 	"fmt"
 
+	"github.com/AccumulateNetwork/accumulate/smt/common"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 type RequestByUrl struct {
 	Url types.String
+
+	// Height is the block height to query the state at. Zero queries the
+	// latest committed state.
+	Height int64
 }
 
 type ResponseByUrl struct {
@@ -18,7 +23,11 @@ type ResponseByUrl struct {
 func (*RequestByUrl) Type() types.QueryType { return types.QueryTypeUrl }
 
 func (r *RequestByUrl) MarshalBinary() ([]byte, error) {
-	return r.Url.MarshalBinary()
+	data, err := r.Url.MarshalBinary()
+	if err != nil || r.Height == 0 {
+		return data, err
+	}
+	return append(data, common.Int64Bytes(r.Height)...), nil
 }
 
 func (r *RequestByUrl) UnmarshalBinary(data []byte) (err error) {
@@ -27,7 +36,18 @@ func (r *RequestByUrl) UnmarshalBinary(data []byte) (err error) {
 			err = fmt.Errorf("error unmarshaling RequestByUrl data %v", r)
 		}
 	}()
-	return r.Url.UnmarshalBinary(data)
+	err = r.Url.UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+
+	// The height is optional
+	r.Height = 0
+	var buf [8]byte
+	if data = data[r.Url.Size(&buf):]; len(data) > 0 {
+		r.Height, _ = common.BytesInt64(data)
+	}
+	return nil
 }
 
 func (r *ResponseByUrl) MarshalBinary() ([]byte, error) {
@@ -2,23 +2,38 @@ package query
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/AccumulateNetwork/accumulate/internal/encoding"
+	"github.com/AccumulateNetwork/accumulate/smt/common"
 	"github.com/AccumulateNetwork/accumulate/types"
 	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 type RequestByChainId struct {
 	ChainId types.Bytes32
+
+	// Height is the block height to query the state at. Zero queries the
+	// latest committed state.
+	Height int64
 }
 
 type ResponseByChainId struct {
 	state.Object
+
+	// BlockTime is the time of the block the state is as of. It is the clock
+	// time-dependent rules, such as vesting, are evaluated against.
+	BlockTime time.Time
 }
 
 func (*RequestByChainId) Type() types.QueryType { return types.QueryTypeChainId }
 
 func (r *RequestByChainId) MarshalBinary() ([]byte, error) {
-	return r.ChainId[:], nil
+	data := append([]byte{}, r.ChainId[:]...)
+	if r.Height != 0 {
+		data = append(data, common.Int64Bytes(r.Height)...)
+	}
+	return data, nil
 }
 
 func (r *RequestByChainId) UnmarshalBinary(data []byte) (err error) {
@@ -31,13 +46,27 @@ func (r *RequestByChainId) UnmarshalBinary(data []byte) (err error) {
 	if len(data) < 32 {
 		return fmt.Errorf("insufficient data for chain id")
 	}
-	r.ChainId.FromBytes(data)
+	r.ChainId.FromBytes(data[:32])
 
+	// The height is optional
+	r.Height = 0
+	if data = data[32:]; len(data) > 0 {
+		r.Height, _ = common.BytesInt64(data)
+	}
 	return nil
 }
 
 func (r *ResponseByChainId) MarshalBinary() ([]byte, error) {
-	return r.Object.MarshalBinary()
+	data, err := r.Object.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	// The block time is optional, so the response can be read as an Object
+	if !r.BlockTime.IsZero() {
+		data = append(data, encoding.TimeMarshalBinary(r.BlockTime)...)
+	}
+	return data, nil
 }
 
 func (r *ResponseByChainId) UnmarshalBinary(data []byte) (err error) {
@@ -46,5 +75,18 @@ func (r *ResponseByChainId) UnmarshalBinary(data []byte) (err error) {
 			err = fmt.Errorf("error unmarshaling ResponseByChainId data %v", r)
 		}
 	}()
-	return r.Object.UnmarshalBinary(data)
+	err = r.Object.UnmarshalBinary(data)
+	if err != nil {
+		return err
+	}
+
+	// The block time is optional
+	r.BlockTime = time.Time{}
+	if data = data[r.Object.BinarySize():]; len(data) > 0 {
+		r.BlockTime, err = encoding.TimeUnmarshalBinary(data)
+		if err != nil {
+			return fmt.Errorf("error decoding BlockTime: %w", err)
+		}
+	}
+	return nil
 }
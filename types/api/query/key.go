@@ -0,0 +1,21 @@
+package query
+
+import (
+	"github.com/AccumulateNetwork/accumulate/types"
+)
+
+// RequestKeyIndex requests the key pages that contain a public key
+type RequestKeyIndex struct {
+	Key types.Bytes
+}
+
+func (*RequestKeyIndex) Type() types.QueryType { return types.QueryTypeKeyIndex }
+
+func (r *RequestKeyIndex) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, r.Key...), nil
+}
+
+func (r *RequestKeyIndex) UnmarshalBinary(data []byte) error {
+	r.Key = append(types.Bytes{}, data...)
+	return nil
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/types/state"
 )
 
 func TestQuery(t *testing.T) {
@@ -33,3 +36,61 @@ func TestQuery(t *testing.T) {
 	}
 
 }
+
+func TestRequestHeight(t *testing.T) {
+	byUrl := RequestByUrl{Url: "RedWagon/myAccount", Height: 10}
+	data, err := byUrl.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	byUrl2 := RequestByUrl{}
+	if err := byUrl2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if byUrl2 != byUrl {
+		t.Fatalf("want %v, got %v", byUrl, byUrl2)
+	}
+
+	byChainId := RequestByChainId{ChainId: sha256.Sum256([]byte("RedWagon/myAccount"))}
+	data, err = byChainId.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 32 {
+		t.Fatal("a request without a height should only contain the chain ID")
+	}
+	byChainId2 := RequestByChainId{Height: 1}
+	if err := byChainId2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if byChainId2 != byChainId {
+		t.Fatalf("want %v, got %v", byChainId, byChainId2)
+	}
+}
+
+func TestResponseBlockTime(t *testing.T) {
+	res := ResponseByChainId{}
+	res.Entry = []byte("entry")
+	res.BlockTime = time.Unix(100, 0)
+	data, err := res.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res2 := ResponseByChainId{}
+	if err := res2.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !res2.BlockTime.Equal(res.BlockTime) || !bytes.Equal(res2.Entry, res.Entry) {
+		t.Fatalf("want %v, got %v", res, res2)
+	}
+
+	// The response can be read as a state object
+	obj := state.Object{}
+	if err := obj.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(obj.Entry, res.Entry) {
+		t.Fatal("entry not equal")
+	}
+}
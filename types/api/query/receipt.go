@@ -0,0 +1,43 @@
+package query
+
+import (
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/types"
+)
+
+// RequestReceipt requests a receipt that proves that an element is in the
+// Merkle chain of an account. If the anchor is nil, the receipt leads to the
+// current root of the chain.
+type RequestReceipt struct {
+	ChainId types.Bytes32
+	Element types.Bytes32
+	Anchor  *types.Bytes32
+}
+
+func (*RequestReceipt) Type() types.QueryType { return types.QueryTypeReceipt }
+
+func (r *RequestReceipt) MarshalBinary() ([]byte, error) {
+	data := make([]byte, 0, 96)
+	data = append(data, r.ChainId[:]...)
+	data = append(data, r.Element[:]...)
+	if r.Anchor != nil {
+		data = append(data, r.Anchor[:]...)
+	}
+	return data, nil
+}
+
+func (r *RequestReceipt) UnmarshalBinary(data []byte) error {
+	switch len(data) {
+	case 64:
+		r.Anchor = nil
+	case 96:
+		r.Anchor = new(types.Bytes32)
+		r.Anchor.FromBytes(data[64:])
+	default:
+		return fmt.Errorf("invalid length for RequestReceipt: want 64 or 96, got %d", len(data))
+	}
+	r.ChainId.FromBytes(data[:32])
+	r.Element.FromBytes(data[32:64])
+	return nil
+}
@@ -4,32 +4,44 @@ type QueryType uint64
 
 //QueryType enumeration order matters, do not change order when adding new enums.
 const (
-	QueryTypeUnknown      = QueryType(iota)
-	QueryTypeUrl          // Query by Url
-	QueryTypeChainId      // Query by chain id
-	QueryTypeTxId         // Query tx and pending chains By TxId
-	QueryTypeTxHistory    // Query transaction history
-	QueryTypeDirectoryUrl // Query directory by URL
+	QueryTypeUnknown         = QueryType(iota)
+	QueryTypeUrl             // Query by Url
+	QueryTypeChainId         // Query by chain id
+	QueryTypeTxId            // Query tx and pending chains By TxId
+	QueryTypeTxHistory       // Query transaction history
+	QueryTypeDirectoryUrl    // Query directory by URL
+	QueryTypeMetadataUrl     // Query the metadata of a chain by URL
+	QueryTypeKeyIndex        // Query the key pages of a public key
+	QueryTypeTransferHistory // Query the transfers of an account or an ADI
+	QueryTypeReceipt         // Query a receipt for an element of a Merkle chain
 
 )
 
 // Enum value maps for QueryType.
 var (
 	QueryTypeName = map[QueryType]string{
-		QueryTypeUnknown:      "QueryTypeUnknown",
-		QueryTypeUrl:          "QueryTypeUrl",
-		QueryTypeChainId:      "QueryTypeChainId",
-		QueryTypeTxId:         "QueryTypeTxId",
-		QueryTypeTxHistory:    "QueryTypeTxHistory",
-		QueryTypeDirectoryUrl: "QueryTypeDirectoryUrl",
+		QueryTypeUnknown:         "QueryTypeUnknown",
+		QueryTypeUrl:             "QueryTypeUrl",
+		QueryTypeChainId:         "QueryTypeChainId",
+		QueryTypeTxId:            "QueryTypeTxId",
+		QueryTypeTxHistory:       "QueryTypeTxHistory",
+		QueryTypeDirectoryUrl:    "QueryTypeDirectoryUrl",
+		QueryTypeMetadataUrl:     "QueryTypeMetadataUrl",
+		QueryTypeKeyIndex:        "QueryTypeKeyIndex",
+		QueryTypeTransferHistory: "QueryTypeTransferHistory",
+		QueryTypeReceipt:         "QueryTypeReceipt",
 	}
 	QueryTypeValue = map[string]QueryType{
-		"QueryTypeUnknown":      QueryTypeUnknown,
-		"QueryTypeUrl":          QueryTypeUrl,
-		"QueryTypeChainId":      QueryTypeChainId,
-		"QueryTypeTxId":         QueryTypeTxId,
-		"QueryTypeTxHistory":    QueryTypeTxHistory,
-		"QueryTypeDirectoryUrl": QueryTypeDirectoryUrl,
+		"QueryTypeUnknown":         QueryTypeUnknown,
+		"QueryTypeUrl":             QueryTypeUrl,
+		"QueryTypeChainId":         QueryTypeChainId,
+		"QueryTypeTxId":            QueryTypeTxId,
+		"QueryTypeTxHistory":       QueryTypeTxHistory,
+		"QueryTypeDirectoryUrl":    QueryTypeDirectoryUrl,
+		"QueryTypeMetadataUrl":     QueryTypeMetadataUrl,
+		"QueryTypeKeyIndex":        QueryTypeKeyIndex,
+		"QueryTypeTransferHistory": QueryTypeTransferHistory,
+		"QueryTypeReceipt":         QueryTypeReceipt,
 	}
 )
 
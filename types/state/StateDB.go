@@ -9,6 +9,7 @@ import (
 	"math/bits"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/logging"
@@ -50,6 +51,10 @@ const (
 	bucketStagedSynthTx    = bucket("StagedSynthTx") //store the staged synthetic transactions
 	bucketTxToSynthTx      = bucket("TxToSynthTx")   //TXID to synthetic TXID
 	bucketMinorAnchorChain = bucket("MinorAnchorChain")
+	bucketMajorAnchorChain = bucket("MajorAnchorChain")
+	bucketCommit           = bucket("Commit") //height and root hash of the last block
+	bucketUndo             = bucket("Undo")   //per block batches that revert the block
+	bucketRecord           = bucket("Records") //state records that do not belong to a chain
 
 	markPower = int64(8)
 )
@@ -75,6 +80,13 @@ type StateDB struct {
 	mutex      sync.Mutex
 	sync       sync.WaitGroup
 	logger     log.Logger
+
+	pendingRetention int64 // number of blocks pending transactions are kept for, zero keeps them forever
+	undoRetention    int64 // number of blocks that can be rolled back, zero disables rollback
+	historyRetention int64 // number of blocks the versions of state entries are kept for, zero keeps them forever
+
+	bptCacheSize int             // number of BPT byte blocks kept in memory, zero keeps them all
+	bptStats     *pmt.CacheStats // counts of the BPT cache, kept when the BPT is reloaded
 }
 
 func (s *StateDB) SetLogger(logger log.Logger) {
@@ -91,32 +103,70 @@ func (s *StateDB) logInfo(msg string, keyVals ...interface{}) {
 	}
 }
 
-func (s *StateDB) init(debug bool) {
+func (s *StateDB) init(debug bool) error {
 	s.debug = debug
 
-	s.bpt = pmt.NewBPTManager(s.db)
+	s.bptStats = new(pmt.CacheStats)
+	s.loadBPT()
 	managed.NewMerkleManager(s.db, markPower)
+	return s.checkCommit()
 }
 
-// Open database to manage the smt and chain states
-func (s *StateDB) Open(dbFilename string, useMemDB bool, debug bool) (err error) {
-	dbType := "badger"
-	if useMemDB {
-		dbType = "memory"
+// loadBPT loads the BPT from the database, discarding any changes that have
+// not been written.
+func (s *StateDB) loadBPT() {
+	s.bpt = pmt.NewBPTManager(s.db)
+	atomic.StoreInt64(&s.bptStats.Loaded, 0) // Nothing but the root is loaded yet
+	s.bpt.Stats = s.bptStats
+	s.bpt.SetCacheSize(s.bptCacheSize)
+}
+
+// SetBPTCacheSize sets the number of BPT byte blocks kept in memory. The least
+// recently used byte blocks are unloaded after each block and loaded again
+// when they are needed. Zero keeps every byte block that is loaded.
+func (s *StateDB) SetBPTCacheSize(size int) {
+	s.bptCacheSize = size
+	s.bpt.SetCacheSize(size)
+}
+
+// BPTCacheStats returns the counts of the BPT byte blocks held in memory.
+func (s *StateDB) BPTCacheStats() pmt.CacheStats {
+	return s.bptStats.Snapshot()
+}
+
+// checkCommit verifies that the latest block and the BPT match the commit
+// marker written with the last block. Each block is written to the database
+// in a single batch, which the database writes atomically, so a mismatch means
+// the database is corrupt and must not be used to serve the app hash.
+func (s *StateDB) checkCommit() error {
+	b, err := s.db.Key(bucketCommit).Get()
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to load the commit marker: %v", err)
 	}
 
-	s.db, err = database.NewDBManager(dbType, dbFilename)
+	height, root := common.BytesInt64(b)
+	index, err := s.BlockIndex()
 	if err != nil {
-		return err
+		return fmt.Errorf("database is inconsistent: the last commit was block %d but the block index cannot be loaded: %v", height, err)
 	}
+	if index != height || !bytes.Equal(root, s.RootHash()) {
+		return fmt.Errorf("database is inconsistent: the last commit was block %d with root %X but the database is at block %d with root %X",
+			height, root, index, s.RootHash())
+	}
+	return nil
+}
 
-	s.mm, err = managed.NewMerkleManager(s.db, markPower)
+// Open database to manage the smt and chain states. The backend is the name of
+// a registered storage backend, such as badger, bolt, or memory.
+func (s *StateDB) Open(backend, dbFilename string, opts storage.Options, debug bool) error {
+	db, err := storage.Open(backend, dbFilename, opts)
 	if err != nil {
 		return err
 	}
 
-	s.init(debug)
-	return nil
+	return s.Load(db, debug)
 }
 
 func (s *StateDB) Load(db storage.KeyValueDB, debug bool) (err error) {
@@ -127,8 +177,7 @@ func (s *StateDB) Load(db storage.KeyValueDB, debug bool) (err error) {
 		return err
 	}
 
-	s.init(debug)
-	return nil
+	return s.init(debug)
 }
 
 func (s *StateDB) GetDB() *database.Manager {
@@ -156,6 +205,44 @@ func (s *StateDB) GetTxRange(chainId *types.Bytes32, start int64, end int64) (ha
 	return hashes, maxAvailable, nil
 }
 
+// StreamTxRange calls fn with the IDs of the transactions of the chain with
+// indexes from start up to but not including end, a mark at a time, and
+// returns the number of transactions on the chain. The parts of the range
+// past the end of the chain are ignored. The range is read while the StateDB
+// is locked and fn is called after it is unlocked, so fn may call back into
+// the StateDB. Callers must bound the range.
+func (s *StateDB) StreamTxRange(chainId *types.Bytes32, start int64, end int64, fn func(start int64, txids []types.Bytes32) error) (int64, error) {
+	type chunk struct {
+		start int64
+		txids []types.Bytes32
+	}
+	var chunks []chunk
+
+	s.Sync()
+	s.mutex.Lock()
+	err := s.mm.ReadRange(chainId[:], start, end, func(start int64, h []managed.Hash) error {
+		txids := make([]types.Bytes32, len(h))
+		for i := range h {
+			txids[i] = h[i].Bytes32()
+		}
+		chunks = append(chunks, chunk{start, txids})
+		return nil
+	})
+	count := s.mm.GetElementCount()
+	s.mutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, c := range chunks {
+		err = fn(c.start, c.txids)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return count, nil
+}
+
 //GetTx get the transaction by transaction ID
 func (s *StateDB) GetTx(txId []byte) (tx []byte, err error) {
 	tx, err = s.db.Key(bucketTx, txId).Get()
@@ -169,8 +256,8 @@ func (s *StateDB) GetTx(txId []byte) (tx []byte, err error) {
 //GetPendingTx get the pending transactions by primary transaction ID
 func (s *StateDB) GetPendingTx(txId []byte) (pendingTx []byte, err error) {
 
-	pendingTxId, e := s.db.Key(bucketMainToPending, txId).Get()
-	if e != nil {
+	pendingTxId, err := s.db.Key(bucketMainToPending, txId).Get()
+	if err != nil {
 		return nil, err
 	}
 	pendingTx, err = s.db.Key(bucketPendingTx, pendingTxId).Get()
@@ -353,7 +440,7 @@ func (tx *DBTransaction) AddStateEntry(chainId *types.Bytes32, txHash *types.Byt
 	updates.stateData = object
 }
 
-func (tx *DBTransaction) writeTxs(mutex *sync.Mutex, group *sync.WaitGroup) error {
+func (tx *DBTransaction) writeTxs(mutex *sync.Mutex, group *sync.WaitGroup, blockHeight int64) error {
 	defer group.Done()
 	//record transactions
 	for _, txn := range tx.transactions.validatedTx {
@@ -388,6 +475,7 @@ func (tx *DBTransaction) writeTxs(mutex *sync.Mutex, group *sync.WaitGroup) erro
 	}
 
 	// record pending transactions
+	var pendingRecords []byte
 	for _, txn := range tx.transactions.pendingTx {
 		//marshal the pending transaction state
 		data, _ := txn.Object.MarshalBinary()
@@ -402,6 +490,14 @@ func (tx *DBTransaction) writeTxs(mutex *sync.Mutex, group *sync.WaitGroup) erro
 		//store the pending transaction by the pending tx hash
 		tx.state.db.Key(bucketPendingTx, pendingHash[:]).PutBatch(data)
 		mutex.Unlock()
+
+		pendingRecords = append(pendingRecords, txn.TxId...)
+		pendingRecords = append(pendingRecords, pendingHash[:]...)
+	}
+
+	//remember which pending transactions were recorded in this block so they can be pruned
+	if len(pendingRecords) > 0 {
+		tx.state.db.Key(bucketPendingTx, "Height", blockHeight).PutBatch(pendingRecords)
 	}
 
 	//clear out the transactions after they have been processed
@@ -411,7 +507,7 @@ func (tx *DBTransaction) writeTxs(mutex *sync.Mutex, group *sync.WaitGroup) erro
 	return nil
 }
 
-func (tx *DBTransaction) writeChainState(group *sync.WaitGroup, mutex *sync.Mutex, mm *managed.MerkleManager, chainId types.Bytes32) error {
+func (tx *DBTransaction) writeChainState(group *sync.WaitGroup, mutex *sync.Mutex, mm *managed.MerkleManager, chainId types.Bytes32, blockHeight int64) error {
 	defer group.Done()
 
 	err := tx.state.mm.SetChainID(chainId[:])
@@ -459,7 +555,11 @@ func (tx *DBTransaction) writeChainState(group *sync.WaitGroup, mutex *sync.Mute
 		tx.GetDB().Key(bucketEntry, chainId.Bytes()).PutBatch(chainStateObject)
 		// The bpt stores the hash of the ChainState object hash.
 		tx.state.bpt.Bpt.Insert(chainId, sha256.Sum256(chainStateObject))
+		err = tx.writeStateVersion(chainId, blockHeight, chainStateObject)
 		mutex.Unlock()
+		if err != nil {
+			return err
+		}
 	}
 	//TODO: figure out how to do this with new way state is derived
 	//if len(currentState.pendingTx) != 0 {
@@ -496,6 +596,12 @@ func (tx *DBTransaction) writeAnchors(mutex *sync.Mutex, blockIndex int64, times
 	head.Timestamp = timestamp
 	head.Chains = make([][32]byte, len(chainsThatUpdated))
 
+	// Close the major block if the previous block was its last
+	err = tx.writeMajorAnchor(prevHead, blockIndex, timestamp)
+	if err != nil {
+		return fmt.Errorf("failed to make major anchor: %v", err)
+	}
+
 	// Add an anchor for each updated chain to the anchor chain
 	for i, chainId := range chainsThatUpdated {
 		head.Chains[i] = chainId
@@ -513,6 +619,14 @@ func (tx *DBTransaction) writeAnchors(mutex *sync.Mutex, blockIndex int64, times
 		tx.state.mm.AddHash(root)
 	}
 
+	// Record the root of the anchor chain so that the BPT, which holds the
+	// hash of the head, commits to the anchors of the block
+	err = tx.state.mm.SetChainID([]byte(bucketMinorAnchorChain))
+	if err != nil {
+		return err
+	}
+	copy(head.Root[:], tx.state.mm.MS.GetMDRoot())
+
 	data, err := head.MarshalBinary()
 	if err != nil {
 		return err
@@ -523,18 +637,29 @@ func (tx *DBTransaction) writeAnchors(mutex *sync.Mutex, blockIndex int64, times
 
 	// Index the anchor chain against the block index
 	tx.GetDB().Key(bucketMinorAnchorChain, "Index", blockIndex).PutBatch(common.Int64Bytes(tx.state.mm.MS.Count))
+	err = tx.writeStateVersion(MinorAnchorChainKey(), blockIndex, data)
+	if err != nil {
+		return err
+	}
 
 	// Update the Patricia tree
+	tx.state.bpt.Bpt.Insert(MinorAnchorChainKey(), sha256.Sum256(data))
+	return nil
+}
+
+// MinorAnchorChainKey returns the key of the minor anchor chain in the BPT.
+func MinorAnchorChainKey() [32]byte {
 	var id [32]byte
 	copy(id[:], []byte(bucketMinorAnchorChain.String()))
-	tx.state.bpt.Bpt.Insert(id, sha256.Sum256(data))
-	return nil
+	return id
 }
 
+// writeBatches writes the block to the database. The BPT shares the state's
+// database manager, so every key touched by the block is written in a single
+// batch.
 func (tx *DBTransaction) writeBatches() {
 	defer tx.state.sync.Done()
 	tx.state.db.EndBatch()
-	tx.state.bpt.DBManager.EndBatch()
 }
 
 func (s *StateDB) getAnchorHead() (*AnchorMetadata, error) {
@@ -581,7 +706,7 @@ func (s *StateDB) BlockIndex() (int64, error) {
 // Commit will push the data to the database and update the patricia trie
 func (tx *DBTransaction) Commit(blockHeight int64, timestamp time.Time) ([]byte, error) {
 	//build a list of keys from the map
-	currentStateCount := len(tx.updates)
+	currentStateCount := len(tx.updates) + len(tx.records)
 	if currentStateCount == 0 {
 		//only attempt to record the block if we have any data.
 		return tx.RootHash(), nil
@@ -593,11 +718,16 @@ func (tx *DBTransaction) Commit(blockHeight int64, timestamp time.Time) ([]byte,
 
 	mutex := new(sync.Mutex)
 	//to try the multi-threading add "go" in front of the next line
-	err := tx.writeTxs(mutex, group)
+	err := tx.writeTxs(mutex, group, blockHeight)
 	if err != nil {
 		return nil, err
 	}
 
+	err = tx.prunePending(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prune pending transactions: %v", err)
+	}
+
 	// Create an ordered list of chain IDs that need updating. The iteration
 	// order of maps in Go is random. Randomly ordering database writes is bad,
 	// because that leads to consensus errors between nodes, since each node
@@ -612,7 +742,7 @@ func (tx *DBTransaction) Commit(blockHeight int64, timestamp time.Time) ([]byte,
 	})
 
 	for _, chainId := range updateOrder {
-		err = tx.writeChainState(group, mutex, tx.state.mm, chainId)
+		err = tx.writeChainState(group, mutex, tx.state.mm, chainId, blockHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -645,16 +775,31 @@ func (tx *DBTransaction) Commit(blockHeight int64, timestamp time.Time) ([]byte,
 		delete(tx.writes, k)
 	}
 
+	tx.writeRecords(mutex)
+
 	// Don't write the anchor during the genesis TX
 	err = tx.writeAnchors(mutex, blockHeight, timestamp, updateOrder)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make anchor: %v", err)
 	}
 
+	err = tx.writeVersionList(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record state versions: %v", err)
+	}
+
 	group.Wait()
 
 	tx.state.bpt.Bpt.Update()
 
+	//record the block and root hash with the rest of the block
+	tx.state.db.Key(bucketCommit).PutBatch(append(common.Int64Bytes(blockHeight), tx.state.RootHash()...))
+
+	err = tx.writeUndo(blockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to record undo information: %v", err)
+	}
+
 	//reset out block update buffer to get ready for the next round
 	tx.state.sync.Add(1)
 	//to enable threaded batch writes, put go in front of next line.
@@ -0,0 +1,222 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/smt/storage/memory"
+	"github.com/AccumulateNetwork/accumulate/types"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommitMarker(t *testing.T) {
+	kvdb := new(memory.DB)
+	require.NoError(t, kvdb.InitDB(""))
+
+	db := new(StateDB)
+	require.NoError(t, db.Load(kvdb, false))
+	for height := int64(1); height <= 2; height++ {
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&types.Bytes32{1}, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+	root := db.RootHash()
+
+	// A consistent database loads
+	db = new(StateDB)
+	require.NoError(t, db.Load(kvdb, false))
+	require.Equal(t, root, db.RootHash())
+
+	// A database whose BPT does not match the last commit is refused
+	require.NoError(t, kvdb.Put(storage.ComputeKey("Commit"), append(common.Int64Bytes(2), make([]byte, 32)...)))
+	db = new(StateDB)
+	require.Error(t, db.Load(kvdb, false))
+
+	// As is a database whose last block does not match the last commit
+	require.NoError(t, kvdb.Put(storage.ComputeKey("Commit"), append(common.Int64Bytes(3), root...)))
+	db = new(StateDB)
+	require.Error(t, db.Load(kvdb, false))
+}
+
+func TestBPTCacheSize(t *testing.T) {
+	bounded, unbounded := new(StateDB), new(StateDB)
+	require.NoError(t, bounded.Open("memory", "", storage.Options{}, false))
+	require.NoError(t, unbounded.Open("memory", "", storage.Options{}, false))
+	bounded.SetBPTCacheSize(2)
+
+	for height := int64(1); height <= 20; height++ {
+		for _, db := range []*StateDB{bounded, unbounded} {
+			dbTx := db.Begin()
+			for i := int64(0); i < 50; i++ {
+				chainId := types.Bytes32(sha256.Sum256(common.Int64Bytes(height * i)))
+				dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+			}
+			_, err := dbTx.Commit(height, time.Unix(height, 0))
+			require.NoError(t, err)
+		}
+		require.Equal(t, unbounded.RootHash(), bounded.RootHash())
+		require.LessOrEqual(t, bounded.BPTCacheStats().Loaded, int64(2))
+	}
+	require.NotZero(t, bounded.BPTCacheStats().Evictions)
+
+	var problems []error
+	require.NoError(t, bounded.Verify(func(err error) { problems = append(problems, err) }))
+	require.Empty(t, problems)
+}
+
+func TestStreamTxRange(t *testing.T) {
+	const count = 700
+
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+	chainId := types.Bytes32{1}
+	var txids []types.Bytes32
+	for height := int64(1); len(txids) < count; height++ {
+		dbTx := db.Begin()
+		for i := 0; i < 100; i++ {
+			txid := types.Bytes32(sha256.Sum256(common.Int64Bytes(int64(len(txids)))))
+			dbTx.AddStateEntry(&chainId, &txid, &Object{Entry: []byte{byte(height)}})
+			txids = append(txids, txid)
+		}
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	var got []types.Bytes32
+	total, err := db.StreamTxRange(&chainId, 10, count+10, func(start int64, ids []types.Bytes32) error {
+		require.Equal(t, int64(10+len(got)), start)
+		got = append(got, ids...)
+
+		// The StateDB is not locked while fn runs
+		_, _, err := db.GetTxRange(&chainId, start, start+1)
+		return err
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(count), total)
+	require.Equal(t, txids[10:], got)
+
+	hashes, _, err := db.GetTxRange(&chainId, 10, count)
+	require.NoError(t, err)
+	require.Equal(t, txids[10:], hashes)
+}
+
+func TestVerifyChains(t *testing.T) {
+	kvdb := new(memory.DB)
+	require.NoError(t, kvdb.InitDB(""))
+	db := new(StateDB)
+	require.NoError(t, db.Load(kvdb, false))
+
+	chainId := types.Bytes32{1}
+	for height := int64(1); height <= 3; height++ {
+		dbTx := db.Begin()
+		for i := int64(0); i < 100; i++ {
+			txid := types.Bytes32(sha256.Sum256(common.Int64Bytes(height*100 + i)))
+			dbTx.AddStateEntry(&chainId, &txid, &Object{Entry: []byte{byte(height)}})
+		}
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	var problems []error
+	db.VerifyChains(func(err error) { problems = append(problems, err) })
+	require.Empty(t, problems)
+
+	// Lose an element, as a failed write might
+	require.NoError(t, kvdb.Delete(storage.ComputeKey(chainId[:], "Element", int64(123))))
+	db.VerifyChains(func(err error) { problems = append(problems, err) })
+	require.Len(t, problems, 1)
+	var cerr *managed.ChainError
+	require.True(t, errors.As(problems[0], &cerr))
+	require.Equal(t, chainId[:], cerr.ChainID)
+	require.Equal(t, int64(123), cerr.Index)
+}
+
+func TestIndexNamespace(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	dbTx := db.Begin()
+	dbTx.AddStateEntry(&types.Bytes32{1}, &types.Bytes32{1}, &Object{Entry: []byte{1}})
+	dbTx.WriteIndex(DirectoryIndex, []byte("foo"), "Metadata", []byte{1})
+	dbTx.WriteIndex(DirectoryIndex, []byte("foo"), uint64(0), []byte("foo/bar"))
+	dbTx.WriteIndex(KeyIndex, []byte("foo"), "Metadata", []byte{2})
+	_, err := dbTx.Commit(1, time.Unix(1, 0))
+	require.NoError(t, err)
+	db.Sync()
+
+	// Each index can be enumerated by its namespace
+	count := map[Index]int{}
+	for _, index := range Indexes {
+		err := db.GetDB().Iterate(storage.Namespace(index.Namespace()), func(key storage.Key, value []byte) error {
+			count[index]++
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	require.Equal(t, map[Index]int{DirectoryIndex: 2, KeyIndex: 1}, count)
+
+	b, err := db.GetIndex(DirectoryIndex, []byte("foo"), uint64(0))
+	require.NoError(t, err)
+	require.Equal(t, "foo/bar", string(b))
+}
+
+func TestIndexNotInBPT(t *testing.T) {
+	plain, indexed := new(StateDB), new(StateDB)
+	require.NoError(t, plain.Open("memory", "", storage.Options{}, false))
+	require.NoError(t, indexed.Open("memory", "", storage.Options{}, false))
+
+	for _, db := range []*StateDB{plain, indexed} {
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&types.Bytes32{1}, &types.Bytes32{1}, &Object{Entry: []byte{1}})
+		if db == indexed {
+			dbTx.WriteIndex(DirectoryIndex, []byte("foo"), "Metadata", []byte{1})
+		}
+		_, err := dbTx.Commit(1, time.Unix(1, 0))
+		require.NoError(t, err)
+	}
+
+	// Indexes do not change the app hash
+	require.Equal(t, plain.RootHash(), indexed.RootHash())
+}
+
+func TestLegacyIndexKey(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	// Records written by earlier versions are found under their legacy keys
+	require.NoError(t, db.GetDB().Key("Directory", []byte("foo"), "Metadata").Put([]byte{1}))
+	require.NoError(t, db.GetDB().Key("Directory", []byte("foo"), uint64(0)).Put([]byte("foo/bar")))
+
+	b, err := db.GetIndex(DirectoryIndex, []byte("foo"), uint64(0))
+	require.NoError(t, err)
+	require.Equal(t, "foo/bar", string(b))
+
+	// Updates shadow the legacy records
+	dbTx := db.Begin()
+	b, err = dbTx.GetIndex(DirectoryIndex, []byte("foo"), "Metadata")
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, b)
+	dbTx.AddStateEntry(&types.Bytes32{1}, &types.Bytes32{1}, &Object{Entry: []byte{1}})
+	dbTx.WriteIndex(DirectoryIndex, []byte("foo"), "Metadata", []byte{2})
+	dbTx.WriteIndex(DirectoryIndex, []byte("foo"), uint64(1), []byte("foo/baz"))
+	_, err = dbTx.Commit(1, time.Unix(1, 0))
+	require.NoError(t, err)
+	db.Sync()
+
+	b, err = db.GetIndex(DirectoryIndex, []byte("foo"), "Metadata")
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, b)
+	b, err = db.GetIndex(DirectoryIndex, []byte("foo"), uint64(0))
+	require.NoError(t, err)
+	require.Equal(t, "foo/bar", string(b))
+	b, err = db.GetIndex(DirectoryIndex, []byte("foo"), uint64(1))
+	require.NoError(t, err)
+	require.Equal(t, "foo/baz", string(b))
+}
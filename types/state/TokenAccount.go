@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/url"
 	"github.com/AccumulateNetwork/accumulate/smt/common"
@@ -12,9 +13,10 @@ import (
 
 type TokenAccount struct {
 	ChainHeader
-	TokenUrl types.UrlChain `json:"tokenUrl"` //need to know who issued tokens, this can be condensed maybe back to adi chain path
-	Balance  big.Int        `json:"balance"`  //store the balance as a big int.
-	TxCount  uint64         `json:"txCount"`  //the number of transactions associated with this account (this is used to derive the txurl)
+	TokenUrl types.UrlChain   `json:"tokenUrl"`          //need to know who issued tokens, this can be condensed maybe back to adi chain path
+	Balance  big.Int          `json:"balance"`           //store the balance as a big int.
+	TxCount  uint64           `json:"txCount"`           //the number of transactions associated with this account (this is used to derive the txurl)
+	Vesting  *VestingSchedule `json:"vesting,omitempty"` //optional schedule that locks part of the balance
 }
 
 //NewTokenAccount create a new token account.  Requires the identity/chain id's and coinbase if applicable
@@ -36,6 +38,13 @@ func (app *TokenAccount) Set(accountState *TokenAccount) {
 	app.ChainUrl = accountState.ChainUrl
 	app.TokenUrl = accountState.TokenUrl
 	app.Type = accountState.Type
+	if accountState.Vesting != nil {
+		app.Vesting = new(VestingSchedule)
+		app.Vesting.Locked.Set(&accountState.Vesting.Locked)
+		app.Vesting.Start = accountState.Vesting.Start
+		app.Vesting.Cliff = accountState.Vesting.Cliff
+		app.Vesting.Duration = accountState.Vesting.Duration
+	}
 }
 
 // CanTransact returns true/false if there is a sufficient balance
@@ -98,6 +107,16 @@ func (app *TokenAccount) MarshalBinary() (ret []byte, err error) {
 	buffer.Write(common.SliceBytes(app.Balance.Bytes()))
 	buffer.Write(common.Uint64Bytes(app.TxCount))
 
+	// The vesting schedule is optional and is omitted entirely when absent,
+	// so accounts without one keep their original encoding
+	if app.Vesting != nil {
+		vesting, err := app.Vesting.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("cannot marshal binary for vesting schedule in TokenAccount, %v", err)
+		}
+		buffer.Write(vesting)
+	}
+
 	return buffer.Bytes(), nil
 }
 
@@ -130,7 +149,16 @@ func (app *TokenAccount) UnmarshalBinary(data []byte) (err error) {
 	bal, data := common.BytesSlice(data[i:])
 
 	app.Balance.SetBytes(bal)
-	app.TxCount, _ = common.BytesUint64(data)
+	app.TxCount, data = common.BytesUint64(data)
+
+	app.Vesting = nil
+	if len(data) > 0 {
+		app.Vesting = new(VestingSchedule)
+		err = app.Vesting.UnmarshalBinary(data)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshal vesting schedule for token account, %v", err)
+		}
+	}
 
 	return nil
 }
@@ -144,12 +172,33 @@ func (acct *TokenAccount) CreditTokens(amount *big.Int) bool {
 	return true
 }
 
-func (acct *TokenAccount) CanDebitTokens(amount *big.Int) bool {
-	return amount != nil && acct.Balance.Cmp(amount) >= 0
+// LockedBalance returns the portion of the balance that has not vested at the
+// given time.
+func (acct *TokenAccount) LockedBalance(now time.Time) *big.Int {
+	locked := new(big.Int)
+	if acct.Vesting != nil {
+		locked.Set(acct.Vesting.LockedAt(now))
+	}
+	if locked.Cmp(&acct.Balance) > 0 {
+		locked.Set(&acct.Balance)
+	}
+	return locked
+}
+
+// SpendableBalance returns the portion of the balance that can be spent at
+// the given time.
+func (acct *TokenAccount) SpendableBalance(now time.Time) *big.Int {
+	return new(big.Int).Sub(&acct.Balance, acct.LockedBalance(now))
+}
+
+// CanDebitTokens returns true if the amount does not exceed the balance that
+// has vested at the given time.
+func (acct *TokenAccount) CanDebitTokens(amount *big.Int, now time.Time) bool {
+	return amount != nil && acct.SpendableBalance(now).Cmp(amount) >= 0
 }
 
-func (acct *TokenAccount) DebitTokens(amount *big.Int) bool {
-	if !acct.CanDebitTokens(amount) {
+func (acct *TokenAccount) DebitTokens(amount *big.Int, now time.Time) bool {
+	if !acct.CanDebitTokens(amount, now) {
 		return false
 	}
 
@@ -166,3 +215,19 @@ func (acct *TokenAccount) NextTx() uint64 {
 func (acct *TokenAccount) ParseTokenUrl() (*url.URL, error) {
 	return url.Parse(*acct.TokenUrl.AsString())
 }
+
+// LockedAt returns the amount that is still locked at the given time.
+func (v *VestingSchedule) LockedAt(now time.Time) *big.Int {
+	if now.Before(v.Start.Add(v.Cliff)) {
+		return new(big.Int).Set(&v.Locked)
+	}
+
+	elapsed := now.Sub(v.Start)
+	if v.Duration <= 0 || elapsed >= v.Duration {
+		return new(big.Int)
+	}
+
+	// locked * (duration - elapsed) / duration
+	locked := new(big.Int).Mul(&v.Locked, big.NewInt(int64(v.Duration-elapsed)))
+	return locked.Div(locked, big.NewInt(int64(v.Duration)))
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestTokenBalanceState(t *testing.T) {
@@ -103,3 +106,52 @@ func TestTokenBalanceState(t *testing.T) {
 		t.Fatalf("Unmarshal error, Expected a balance of %d, but got %d", expectedBalance, token.GetBalance())
 	}
 }
+
+func TestTokenAccountVesting(t *testing.T) {
+	start := time.Unix(1000, 0)
+	token := NewTokenAccount("MyADI/MyTokens", "MyADI/MyTokenType")
+	token.Balance.SetInt64(1500)
+	token.Vesting = new(VestingSchedule)
+	token.Vesting.Locked.SetInt64(1000)
+	token.Vesting.Start = start
+	token.Vesting.Cliff = 10 * time.Second
+	token.Vesting.Duration = 100 * time.Second
+
+	cases := []struct {
+		at     time.Duration
+		locked int64
+	}{
+		{0, 1000},               // Before the cliff
+		{9 * time.Second, 1000}, // Still before the cliff
+		{10 * time.Second, 900}, // At the cliff, 10% has vested
+		{50 * time.Second, 500}, // Half way
+		{100 * time.Second, 0},  // Fully vested
+		{200 * time.Second, 0},  // Well past the end
+	}
+	for _, c := range cases {
+		now := start.Add(c.at)
+		require.Equal(t, c.locked, token.LockedBalance(now).Int64(), "locked at %v", c.at)
+		require.Equal(t, 1500-c.locked, token.SpendableBalance(now).Int64(), "spendable at %v", c.at)
+	}
+
+	require.True(t, token.CanDebitTokens(big.NewInt(500), start))
+	require.False(t, token.CanDebitTokens(big.NewInt(501), start))
+	require.True(t, token.CanDebitTokens(big.NewInt(1000), start.Add(50*time.Second)))
+
+	data, err := token.MarshalBinary()
+	require.NoError(t, err)
+	token2 := new(TokenAccount)
+	require.NoError(t, token2.UnmarshalBinary(data))
+	require.NotNil(t, token2.Vesting)
+	require.Equal(t, token.Vesting.Locked.Int64(), token2.Vesting.Locked.Int64())
+	require.Equal(t, token.Vesting.Start.Unix(), token2.Vesting.Start.Unix())
+	require.Equal(t, token.Vesting.Cliff, token2.Vesting.Cliff)
+	require.Equal(t, token.Vesting.Duration, token2.Vesting.Duration)
+
+	// Accounts without a schedule do not have one after a round trip
+	token.Vesting = nil
+	data, err = token.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, token2.UnmarshalBinary(data))
+	require.Nil(t, token2.Vesting)
+}
@@ -0,0 +1,403 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/pmt"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// restoreBatchSize is the number of records Restore writes per batch
+const restoreBatchSize = 10000
+
+// Dump calls fn for every key of the database. The records are found by
+// following the BPT, the Merkle chains, the indexes, and the records that
+// point to other records, and each is labelled with what it is, such as a
+// state entry, a transaction, or an element of a Merkle chain. Records are
+// passed to fn as they are found, so the dump does not hold the database in
+// memory.
+//
+// Once every record has been dumped, Dump counts the keys of the database. If
+// some keys could not be identified, for example because the database is
+// corrupt, Dump returns an error, since Restore could not rebuild the
+// database from the dump.
+func (s *StateDB) Dump(fn func(*DumpRecord) error) error {
+	s.Sync()
+
+	var dumped int64
+	err := s.dumpRecords(func(kind string, chain []byte, index int64, key storage.Key) error {
+		value, err := s.db.Key(key).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		dumped++
+		return fn(&DumpRecord{Kind: kind, Chain: chain, Index: index, Key: key, Value: value})
+	})
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	err = s.db.Iterate(nil, func(storage.Key, []byte) error {
+		total++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if total != dumped {
+		return fmt.Errorf("the database has %d keys but %d were identified", total, dumped)
+	}
+	return nil
+}
+
+// Restore writes the records of a dump to the database, until next returns
+// io.EOF. The database must be empty. Restore writes the records in several
+// batches, so a database that fails to restore must be discarded.
+func (s *StateDB) Restore(next func() (*DumpRecord, error)) error {
+	s.Sync()
+
+	errNotEmpty := errors.New("database is not empty")
+	err := s.db.Iterate(nil, func(storage.Key, []byte) error { return errNotEmpty })
+	if err != nil {
+		return err
+	}
+
+	s.db.BeginBatch()
+	for n := 1; ; n++ {
+		rec, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			s.db.ClearCache()
+			return err
+		}
+
+		// An empty value decodes as nil, which would delete the key
+		value := rec.Value
+		if value == nil {
+			value = []byte{}
+		}
+		s.db.Key(storage.Key(rec.Key)).PutBatch(value)
+
+		if n%restoreBatchSize == 0 {
+			s.db.EndBatch()
+		}
+	}
+	s.db.EndBatch()
+
+	return s.init(s.debug)
+}
+
+// dumpFunc is called with the label and key of each record of a dump. The
+// record may not exist.
+type dumpFunc func(kind string, chain []byte, index int64, key storage.Key) error
+
+// dumpRecords identifies the keys of the database by following the BPT, the
+// Merkle chains, the indexes, and the records that point to other records, a
+// bucket at a time. Each key is passed to fn once.
+func (s *StateDB) dumpRecords(fn dumpFunc) error {
+	label := func(kind string, chain []byte, index int64, key ...interface{}) error {
+		return fn(kind, chain, index, storage.ComputeKey(key...))
+	}
+
+	for _, err := range []error{
+		label("subnet-id", nil, 0, "SubnetID"),
+		label("bpt-root", nil, 0, "BPT", "Root"),
+		label("commit", nil, 0, bucketCommit),
+		label("synthetic-transaction-count", nil, 0, SyntheticTransactionCountKey()),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+
+	// The BPT and the records behind it
+	err := s.bpt.Walk(func(e pmt.Entry) error {
+		switch e := e.(type) {
+		case *pmt.Node:
+			if s.bpt.IsByteBlock(e) {
+				return label("bpt-block", e.BBKey[:], 0, "BPT", e.BBKey[:])
+			}
+
+		case *pmt.Value:
+			key, _, err := s.leafRecord(e.Key, e.Hash)
+			if err != nil {
+				return nil
+			}
+
+			id := append([]byte{}, e.Key[:]...)
+			switch key {
+			case storage.ComputeKey(bucketEntry, id):
+				err = label("state", id, 0, key)
+				if err == nil {
+					err = s.dumpVersions(fn, id)
+				}
+				return err
+			case storage.ComputeKey(bucketTx, id):
+				return s.dumpTransaction(fn, id)
+			case storage.ComputeKey(bucketStagedSynthTx, "", id):
+				return label("staged-synthetic-transaction", id, 0, key)
+			case storage.ComputeKey(bucketRecord, id):
+				return label("record", id, 0, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The indexes, which are not part of the BPT
+	for _, index := range Indexes {
+		name := []byte(index)
+		err = s.db.Iterate(storage.Namespace(index.Namespace()), func(key storage.Key, _ []byte) error {
+			return fn("index", name, 0, key)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// The Merkle chains
+	for _, chainId := range s.chainIds() {
+		err = s.dumpChain(fn, chainId)
+		if err != nil {
+			return err
+		}
+	}
+	anchorId := MinorAnchorChainKey()
+	err = s.dumpVersions(fn, anchorId[:])
+	if err != nil {
+		return err
+	}
+	err = s.walkAnchors(func(meta *AnchorMetadata) error {
+		return label("anchor-index", bucketMinorAnchorChain.Bytes(), meta.Index, bucketMinorAnchorChain, "Index", meta.Index)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The per-block lists that have not been pruned
+	height, _ := s.BlockIndex()
+	for _, list := range []struct {
+		bucket       bucket
+		kind, pruned string
+		floor        func() (int64, error)
+	}{
+		{bucketPendingTx, "pending-transactions", "pending-pruned", func() (int64, error) {
+			b, err := s.db.Key(bucketPendingTx, "Pruned").Get()
+			if err != nil {
+				return 0, err
+			}
+			v, _ := common.BytesInt64(b)
+			return v, nil
+		}},
+		{bucketEntry, "state-versions-block", "state-pruned", s.historyFloor},
+	} {
+		start := int64(0) // Genesis is block 0
+		floor, err := list.floor()
+		if err == nil {
+			err = label(list.pruned, nil, 0, list.bucket, "Pruned")
+			if err != nil {
+				return err
+			}
+			start = floor + 1
+		}
+		for h := start; h <= height; h++ {
+			err = label(list.kind, nil, h, list.bucket, "Height", h)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	// Undo records
+	floor, err := s.undoFloor()
+	if err == nil {
+		err = label("undo-floor", nil, 0, bucketUndo, "Floor")
+		if err != nil {
+			return err
+		}
+		for h := floor + 1; h <= height; h++ {
+			err = label("undo", nil, h, bucketUndo, h)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// dumpTransaction dumps a transaction and the records that point to it
+func (s *StateDB) dumpTransaction(fn dumpFunc, txId []byte) error {
+	err := fn("transaction", txId, 0, storage.ComputeKey(bucketTx, txId))
+	if err != nil {
+		return err
+	}
+	err = fn("pending-transaction-id", txId, 0, storage.ComputeKey(bucketMainToPending, txId))
+	if err != nil {
+		return err
+	}
+	pendingId, err := s.db.Key(bucketMainToPending, txId).Get()
+	if err == nil {
+		err = fn("pending-transaction", txId, 0, storage.ComputeKey(bucketPendingTx, pendingId))
+		if err != nil {
+			return err
+		}
+	}
+	return fn("synthetic-transaction-ids", txId, 0, storage.ComputeKey(bucketTxToSynthTx, txId))
+}
+
+// dumpVersions dumps the history of a state entry
+func (s *StateDB) dumpVersions(fn dumpFunc, chainId []byte) error {
+	vi, err := s.getVersionIndex(chainId)
+	if err != nil {
+		return nil
+	}
+	err = fn("state-versions", chainId, 0, storage.ComputeKey(bucketEntry, chainId, "Versions"))
+	if err != nil {
+		return err
+	}
+
+	for i := vi.First; i < vi.Count; i++ {
+		err = fn("state-version-height", chainId, i, storage.ComputeKey(bucketEntry, chainId, "Versions", i))
+		if err != nil {
+			return err
+		}
+		height, err := s.versionHeight(chainId, i)
+		if err != nil {
+			continue
+		}
+		err = fn("state-version", chainId, height, storage.ComputeKey(bucketEntry, chainId, "Version", height))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dumpChain dumps the head, elements, and mark states of a Merkle chain. The
+// element index of a hash that was added more than once is dumped with the
+// element it points to.
+func (s *StateDB) dumpChain(fn dumpFunc, chainId []byte) error {
+	head, err := s.mm.ReadChainHead(chainId)
+	if err != nil {
+		return nil
+	}
+	err = fn("chain-head", chainId, 0, storage.ComputeKey(chainId, "Head"))
+	if err != nil {
+		return err
+	}
+
+	mask := int64(1)<<markPower - 1
+	for i := int64(0); i < head.Count; i++ {
+		err = fn("chain-element", chainId, i, storage.ComputeKey(chainId, "Element", i))
+		if err != nil {
+			return err
+		}
+		element, err := s.db.Key(chainId, "Element", i).Get()
+		if err == nil {
+			index, err := s.db.Key(chainId, "ElementIndex", element).Get()
+			if err == nil && bytes.Equal(index, common.Int64Bytes(i)) {
+				err = fn("chain-element-index", chainId, i, storage.ComputeKey(chainId, "ElementIndex", element))
+				if err != nil {
+					return err
+				}
+			}
+		}
+		if i&mask == mask {
+			err = fn("chain-state", chainId, i, storage.ComputeKey(chainId, "States", i))
+			if err != nil {
+				return err
+			}
+			err = fn("chain-next-element", chainId, i, storage.ComputeKey(chainId, "NextElement", i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chainIds returns the ID of every Merkle chain: the chains that have a state
+// entry, the chains listed by the anchors, and the anchor chains themselves.
+func (s *StateDB) chainIds() [][]byte {
+	ids := map[[32]byte]bool{}
+	_ = s.bpt.Walk(func(e pmt.Entry) error {
+		v, ok := e.(*pmt.Value)
+		if !ok {
+			return nil
+		}
+		_, err := s.db.Key(bucketEntry, v.Key[:]).Get()
+		if err == nil {
+			ids[v.Key] = true
+		}
+		return nil
+	})
+	_ = s.walkAnchors(func(meta *AnchorMetadata) error {
+		for _, id := range meta.Chains {
+			ids[id] = true
+		}
+		return nil
+	})
+
+	list := make([][]byte, 0, len(ids)+1)
+	for id := range ids {
+		id := id
+		list = append(list, id[:])
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i], list[j]) < 0
+	})
+	list = append(list, bucketMinorAnchorChain.Bytes())
+
+	major, err := s.mm.ReadChainHead(bucketMajorAnchorChain.Bytes())
+	if err == nil && major.Count > 0 {
+		list = append(list, bucketMajorAnchorChain.Bytes())
+	}
+	return list
+}
+
+// walkAnchors calls fn for the metadata of every anchor, latest first. An
+// anchor adds the root of each chain it lists to the anchor chain, followed by
+// its metadata, so the previous anchor's metadata precedes those roots. The
+// walk stops at the first anchor that is not in the database, for example
+// because the database was restored from a snapshot.
+func (s *StateDB) walkAnchors(fn func(*AnchorMetadata) error) error {
+	head, err := s.mm.ReadChainHead(bucketMinorAnchorChain.Bytes())
+	if err != nil {
+		return err
+	}
+
+	for n := head.Count - 1; n >= 0; {
+		data, err := s.db.Key(bucketMinorAnchorChain.Bytes(), "Element", n).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		meta := new(AnchorMetadata)
+		err = meta.UnmarshalBinary(data)
+		if err != nil {
+			return fmt.Errorf("invalid anchor at %d: %v", n, err)
+		}
+
+		err = fn(meta)
+		if err != nil {
+			return err
+		}
+		n -= int64(len(meta.Chains)) + 1
+	}
+	return nil
+}
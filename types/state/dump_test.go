@@ -0,0 +1,121 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpRestoreVerify(t *testing.T) {
+	src := new(StateDB)
+	require.NoError(t, src.Open("memory", "", storage.Options{}, false))
+	src.SetUndoRetention(2)
+
+	gtx := new(transactions.GenTransaction)
+	gtx.SigInfo = new(transactions.SignatureInfo)
+	gtx.SigInfo.URL = "RedWagon/myAccount"
+	gtx.Transaction = []byte("transaction")
+	txId := gtx.TransactionHash()
+	accepted, pending := NewTransaction(NewPendingTransaction(gtx))
+	pendingData, err := pending.MarshalBinary()
+	require.NoError(t, err)
+	acceptedData, err := accepted.MarshalBinary()
+	require.NoError(t, err)
+
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	for height := int64(1); height <= 300; height++ {
+		dbTx := src.Begin()
+		if height == 2 {
+			require.NoError(t, dbTx.AddTransaction(&chainId, txId, &Object{Entry: pendingData}, &Object{Entry: acceptedData}))
+			dbTx.WriteIndex(DirectoryIndex, chainId[:], "Metadata", []byte("index"))
+		}
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height), byte(height >> 8)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	var records []*DumpRecord
+	kinds := map[string]int{}
+	require.NoError(t, src.Dump(func(rec *DumpRecord) error {
+		records = append(records, rec)
+		kinds[rec.Kind]++
+		return nil
+	}))
+	for _, kind := range []string{"state", "transaction", "index", "chain-head", "chain-element", "chain-state", "bpt-root", "bpt-block", "commit", "undo"} {
+		require.NotZero(t, kinds[kind], "no %s records", kind)
+	}
+
+	var problems []error
+	require.NoError(t, src.Verify(func(err error) { problems = append(problems, err) }))
+	require.Empty(t, problems)
+
+	// Restore the dump
+	restore := func() *StateDB {
+		dst := new(StateDB)
+		require.NoError(t, dst.Open("memory", "", storage.Options{}, false))
+		i := 0
+		require.NoError(t, dst.Restore(func() (*DumpRecord, error) {
+			if i >= len(records) {
+				return nil, io.EOF
+			}
+			i++
+			return records[i-1], nil
+		}))
+		return dst
+	}
+	dst := restore()
+	require.Equal(t, src.RootHash(), dst.RootHash())
+	require.NoError(t, dst.Verify(func(err error) { problems = append(problems, err) }))
+	require.Empty(t, problems)
+
+	// A database can only be restored once
+	require.Error(t, dst.Restore(func() (*DumpRecord, error) { return nil, io.EOF }))
+
+	// Verify finds a corrupted chain element
+	for _, rec := range records {
+		if rec.Kind == "chain-element" && rec.Index == 100 {
+			dst.GetDB().Key(storage.Key(rec.Key)).PutBatch([]byte("corrupt"))
+			dst.GetDB().EndBatch()
+			break
+		}
+	}
+	require.NoError(t, dst.Verify(func(err error) { problems = append(problems, err) }))
+	require.Len(t, problems, 1)
+
+	// Verify finds a corrupted state entry
+	problems = nil
+	dst = restore()
+	for _, rec := range records {
+		if rec.Kind == "state" {
+			dst.GetDB().Key(storage.Key(rec.Key)).PutBatch([]byte("corrupt"))
+			dst.GetDB().EndBatch()
+		}
+	}
+	require.NoError(t, dst.Verify(func(err error) { problems = append(problems, err) }))
+	require.NotEmpty(t, problems)
+}
+
+func TestDumpUnidentified(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	chainId := types.Bytes32{1}
+	dbTx := db.Begin()
+	dbTx.AddStateEntry(&chainId, &types.Bytes32{1}, &Object{Entry: []byte{1}})
+	_, err := dbTx.Commit(1, time.Unix(1, 0))
+	require.NoError(t, err)
+	db.Sync()
+	require.NoError(t, db.Dump(func(*DumpRecord) error { return nil }))
+
+	// A key that is not part of any structure cannot be dumped
+	db.GetDB().Key("stray").PutBatch([]byte{1})
+	db.GetDB().EndBatch()
+	require.Error(t, db.Dump(func(*DumpRecord) error { return nil }))
+}
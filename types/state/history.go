@@ -0,0 +1,318 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+)
+
+// SetHistoryRetention sets the number of blocks the versions of state entries
+// are kept for. Zero disables pruning.
+//
+// Versions are not part of the BPT, so the retention does not affect consensus
+// and nodes may use different values.
+func (s *StateDB) SetHistoryRetention(blocks int64) {
+	s.historyRetention = blocks
+}
+
+// versionIndex locates the versions of a chain. The heights of the versions are
+// stored in order, from First up to but not including Count, so the version of
+// a chain at a height can be found with a binary search.
+type versionIndex struct {
+	First int64 // index of the oldest version that has not been pruned
+	Count int64 // number of versions recorded
+}
+
+func (s *StateDB) getVersionIndex(chainId []byte) (*versionIndex, error) {
+	b, err := s.db.Key(bucketEntry, chainId, "Versions").Get()
+	if err != nil {
+		return nil, err
+	}
+
+	vi := new(versionIndex)
+	vi.First, b = common.BytesInt64(b)
+	vi.Count, _ = common.BytesInt64(b)
+	return vi, nil
+}
+
+func (s *StateDB) putVersionIndex(chainId []byte, vi *versionIndex) {
+	s.db.Key(bucketEntry, chainId, "Versions").PutBatch(append(common.Int64Bytes(vi.First), common.Int64Bytes(vi.Count)...))
+}
+
+// versionHeight returns the height of the i-th version of the chain
+func (s *StateDB) versionHeight(chainId []byte, i int64) (int64, error) {
+	b, err := s.db.Key(bucketEntry, chainId, "Versions", i).Get()
+	if err != nil {
+		return 0, fmt.Errorf("failed to load version %d of %X: %v", i, chainId, err)
+	}
+	height, _ := common.BytesInt64(b)
+	return height, nil
+}
+
+// writeStateVersion records the state entry of a chain as of the block and
+// adds the block to the chain's version index. The anchor metadata of each
+// block is recorded as a version of the minor anchor chain, so the block at a
+// height can be found the same way.
+func (tx *DBTransaction) writeStateVersion(chainId types.Bytes32, blockHeight int64, entry []byte) error {
+	vi, err := tx.state.getVersionIndex(chainId[:])
+	switch {
+	case err == nil:
+		// Ok
+	case errors.Is(err, storage.ErrNotFound):
+		vi = new(versionIndex)
+	default:
+		return fmt.Errorf("failed to load the version index of %X: %v", chainId, err)
+	}
+
+	version := new(StateVersion)
+	version.Entry = entry
+	data, err := version.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("failed to marshal the version of %X: %v", chainId, err)
+	}
+
+	db := tx.GetDB()
+	db.Key(bucketEntry, chainId.Bytes(), "Version", blockHeight).PutBatch(data)
+	db.Key(bucketEntry, chainId.Bytes(), "Versions", vi.Count).PutBatch(common.Int64Bytes(blockHeight))
+	vi.Count++
+	tx.state.putVersionIndex(chainId[:], vi)
+	tx.versions = append(tx.versions, chainId)
+	return nil
+}
+
+// writeVersionList records which chains were updated by the block, so their
+// old versions can be pruned, and prunes the versions of blocks that have
+// fallen out of the retention window.
+func (tx *DBTransaction) writeVersionList(blockHeight int64) error {
+	if len(tx.versions) > 0 {
+		var list []byte
+		for _, id := range tx.versions {
+			list = append(list, id[:]...)
+		}
+		tx.state.db.Key(bucketEntry, "Height", blockHeight).PutBatch(list)
+		tx.versions = nil
+	}
+
+	return tx.pruneHistory(blockHeight)
+}
+
+// pruneHistory deletes the versions that are no longer needed to answer a
+// query at a height within the retention window. A version is needed until the
+// chain has a newer version at or before the start of the window, so each
+// chain updated by a pruned block drops the versions it has superseded.
+// Pruning proceeds in block order from the last pruned height, like
+// prunePending.
+func (tx *DBTransaction) pruneHistory(blockHeight int64) error {
+	if tx.state.historyRetention <= 0 {
+		return nil
+	}
+
+	end := blockHeight - tx.state.historyRetention
+	if end <= 0 {
+		return nil
+	}
+
+	db := tx.state.db
+	start, err := tx.state.historyFloor()
+	switch {
+	case err == nil:
+		start++
+	case errors.Is(err, storage.ErrNotFound):
+		start = 1
+	default:
+		return fmt.Errorf("failed to load the last pruned height: %v", err)
+	}
+
+	if end-start >= maxPrunedHeights {
+		end = start + maxPrunedHeights - 1
+	}
+
+	for height := start; height <= end; height++ {
+		list, err := db.Key(bucketEntry, "Height", height).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to load the chains updated by block %d: %v", height, err)
+		}
+
+		if len(list)%32 != 0 {
+			return fmt.Errorf("invalid list of chains updated by block %d", height)
+		}
+
+		for ; len(list) > 0; list = list[32:] {
+			err = tx.state.pruneVersions(list[:32], end)
+			if err != nil {
+				return err
+			}
+		}
+
+		db.Key(bucketEntry, "Height", height).DeleteBatch()
+	}
+
+	if end >= start {
+		db.Key(bucketEntry, "Pruned").PutBatch(common.Int64Bytes(end))
+	}
+	return nil
+}
+
+// pruneVersions deletes the versions of the chain that are superseded by a
+// version at or before the given height.
+func (s *StateDB) pruneVersions(chainId []byte, height int64) error {
+	vi, err := s.getVersionIndex(chainId)
+	if err != nil {
+		return fmt.Errorf("failed to load the version index of %X: %v", chainId, err)
+	}
+
+	first := vi.First
+	for ; first+1 < vi.Count; first++ {
+		next, err := s.versionHeight(chainId, first+1)
+		if err != nil {
+			return err
+		}
+		if next > height {
+			break
+		}
+
+		old, err := s.versionHeight(chainId, first)
+		if err != nil {
+			return err
+		}
+		s.db.Key(bucketEntry, chainId, "Version", old).DeleteBatch()
+		s.db.Key(bucketEntry, chainId, "Versions", first).DeleteBatch()
+	}
+
+	if first != vi.First {
+		vi.First = first
+		s.putVersionIndex(chainId, vi)
+	}
+	return nil
+}
+
+// historyFloor returns the last height whose versions have been pruned
+func (s *StateDB) historyFloor() (int64, error) {
+	b, err := s.db.Key(bucketEntry, "Pruned").Get()
+	if err != nil {
+		return 0, err
+	}
+	height, _ := common.BytesInt64(b)
+	return height, nil
+}
+
+func (s *StateDB) getStateVersion(chainId []byte, blockHeight int64) (*StateVersion, error) {
+	data, err := s.db.Key(bucketEntry, chainId, "Version", blockHeight).Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the version of %X at height %d: %v", chainId, blockHeight, err)
+	}
+
+	version := new(StateVersion)
+	err = version.UnmarshalBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version of %X at height %d: %v", chainId, blockHeight, err)
+	}
+	return version, nil
+}
+
+// GetPersistentEntryAt loads the state entry of a chain as it was after the
+// given block was committed. The height must not be greater than the index of
+// the last block recorded in the minor anchor chain, and must be after the
+// last block whose versions have been pruned.
+func (s *StateDB) GetPersistentEntryAt(chainId []byte, height int64) (*Object, error) {
+	s.Sync()
+
+	version, err := s.versionAt(chainId, height)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := new(Object)
+	err = ret.UnmarshalBinary(version.Entry)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state for %x", chainId)
+	}
+	return ret, nil
+}
+
+// BlockTime returns the time of the last block recorded at or before the given
+// height, which is the time the state at that height was last updated. Zero
+// returns the time of the latest block.
+func (s *StateDB) BlockTime(height int64) (time.Time, error) {
+	s.Sync()
+
+	if height == 0 {
+		head, err := s.getAnchorHead()
+		if err != nil {
+			return time.Time{}, err
+		}
+		return head.Timestamp, nil
+	}
+
+	anchorId := MinorAnchorChainKey()
+	version, err := s.versionAt(anchorId[:], height)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	meta := new(AnchorMetadata)
+	err = meta.UnmarshalBinary(version.Entry)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid anchor at height %d: %v", height, err)
+	}
+	return meta.Timestamp, nil
+}
+
+// versionAt finds the latest version of the chain at or before the height
+func (s *StateDB) versionAt(chainId []byte, height int64) (*StateVersion, error) {
+	if s.db == nil {
+		return nil, fmt.Errorf("database has not been initialized")
+	}
+
+	current, err := s.BlockIndex()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the block index: %v", err)
+	}
+	if height < 0 || height > current {
+		return nil, fmt.Errorf("height %d is not in [0, %d]", height, current)
+	}
+
+	floor, err := s.historyFloor()
+	if err == nil && height <= floor {
+		return nil, fmt.Errorf("the state at height %d has been pruned", height)
+	} else if err != nil && !errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load the last pruned height: %v", err)
+	}
+
+	vi, err := s.getVersionIndex(chainId)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, fmt.Errorf("%w: no state history for %X", storage.ErrNotFound, chainId)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load the version index of %X: %v", chainId, err)
+	}
+
+	// Find the first version after the height. The one before it is the
+	// latest version at or before the height.
+	var searchErr error
+	n := sort.Search(int(vi.Count-vi.First), func(i int) bool {
+		h, err := s.versionHeight(chainId, vi.First+int64(i))
+		if err != nil && searchErr == nil {
+			searchErr = err
+		}
+		return h > height
+	})
+	if searchErr != nil {
+		return nil, searchErr
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: no state defined for %X at height %d", storage.ErrNotFound, chainId, height)
+	}
+
+	versionHeight, err := s.versionHeight(chainId, vi.First+int64(n-1))
+	if err != nil {
+		return nil, err
+	}
+	return s.getStateVersion(chainId, versionHeight)
+}
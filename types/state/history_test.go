@@ -0,0 +1,114 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPersistentEntryAt(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	otherId := types.Bytes32(sha256.Sum256([]byte("RedWagon/other")))
+
+	// The account is updated at heights 2 and 4, and another chain at 3 and 5
+	commit := func(height int64, id types.Bytes32) {
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&id, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+	commit(2, chainId)
+	commit(3, otherId)
+	commit(4, chainId)
+	commit(5, otherId)
+
+	for height, want := range map[int64]byte{2: 2, 3: 2, 4: 4, 5: 4} {
+		obj, err := db.GetPersistentEntryAt(chainId[:], height)
+		require.NoError(t, err)
+		require.Equal(t, []byte{want}, obj.Entry, "height %d", height)
+		require.Equal(t, uint64(want/2), obj.Height, "height %d", height)
+	}
+
+	// The account does not exist before it is first updated
+	_, err := db.GetPersistentEntryAt(chainId[:], 1)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+
+	// Heights after the last block are rejected
+	_, err = db.GetPersistentEntryAt(chainId[:], 6)
+	require.Error(t, err)
+}
+
+func TestHistoryRetention(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+	db.SetHistoryRetention(3)
+
+	// The account is updated at heights 1, 2, and 5, and another chain at
+	// every height
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	otherId := types.Bytes32(sha256.Sum256([]byte("RedWagon/other")))
+	for height := int64(1); height <= 10; height++ {
+		dbTx := db.Begin()
+		if height == 1 || height == 2 || height == 5 {
+			dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		}
+		dbTx.AddStateEntry(&otherId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	// Heights before the retention window are rejected
+	for height := int64(1); height <= 7; height++ {
+		_, err := db.GetPersistentEntryAt(chainId[:], height)
+		require.Error(t, err, "height %d", height)
+	}
+
+	// The version of the account from before the window is kept, since it is
+	// the state of the account throughout the window
+	for height := int64(8); height <= 10; height++ {
+		obj, err := db.GetPersistentEntryAt(chainId[:], height)
+		require.NoError(t, err)
+		require.Equal(t, []byte{5}, obj.Entry, "height %d", height)
+
+		obj, err = db.GetPersistentEntryAt(otherId[:], height)
+		require.NoError(t, err)
+		require.Equal(t, []byte{byte(height)}, obj.Entry, "height %d", height)
+	}
+
+	// Superseded versions are deleted
+	_, err := db.GetDB().Key("StateEntries", chainId[:], "Version", int64(2)).Get()
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = db.GetDB().Key("StateEntries", otherId[:], "Version", int64(6)).Get()
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestBlockTime(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	// Blocks 2 and 4 are recorded, block 3 has no updates
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	for _, height := range []int64{2, 4} {
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height*10, 0))
+		require.NoError(t, err)
+	}
+
+	for height, want := range map[int64]int64{0: 40, 2: 20, 3: 20, 4: 40} {
+		blockTime, err := db.BlockTime(height)
+		require.NoError(t, err)
+		require.Equal(t, want, blockTime.Unix(), "height %d", height)
+	}
+
+	_, err := db.BlockTime(1)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -2,6 +2,7 @@ package state
 
 import (
 	"encoding/hex"
+	"errors"
 
 	"github.com/AccumulateNetwork/accumulate/smt/storage"
 )
@@ -10,8 +11,45 @@ type Index string
 
 const (
 	DirectoryIndex Index = "Directory"
+	MetadataIndex  Index = "Metadata"
+	KeyIndex       Index = "Key"
+	TransferIndex  Index = "Transfer"
+	DataIndex      Index = "Data"
 )
 
+// Indexes lists every index, so the records of each can be enumerated
+var Indexes = []Index{DirectoryIndex, MetadataIndex, KeyIndex, TransferIndex, DataIndex}
+
+// Namespace returns the storage namespace that holds the records of the index
+func (index Index) Namespace() string {
+	return "Index." + string(index)
+}
+
+// IndexKey returns the storage key of an index record. The records of an index
+// share the namespace of the index, so they can be enumerated with
+// storage.Namespace.
+func IndexKey(index Index, chain []byte, key interface{}) storage.Key {
+	k := storage.ComputeKey(chain, key)
+	return storage.NamespacedKey(index.Namespace(), k[:])
+}
+
+// legacyIndexKey returns the key an index record was stored under before each
+// index had a namespace. Databases created by earlier versions keep their
+// records under these keys, which cannot be enumerated and so are not
+// migrated. Instead, a record that is not found under its key is read from its
+// legacy key. Updates are written under the current key, which then shadows
+// the legacy record.
+func legacyIndexKey(index Index, chain []byte, key interface{}) storage.Key {
+	return storage.ComputeKey(string(index), chain, key)
+}
+
+// SyntheticTransactionCountKey returns the key of the record of the number of
+// synthetic transactions produced by the node. Earlier versions stored the
+// count outside of the BPT under the same key.
+func SyntheticTransactionCountKey() storage.Key {
+	return storage.ComputeKey("SyntheticTransactionCount")
+}
+
 func (tx *DBTransaction) Write(key storage.Key, value []byte) {
 	tx.state.mutex.Lock()
 	tx.state.mutex.Unlock()
@@ -33,19 +71,27 @@ func (db *StateDB) Read(key storage.Key) ([]byte, error) {
 }
 
 func (tx *DBTransaction) WriteIndex(index Index, chain []byte, key interface{}, value []byte) {
-	k := storage.ComputeKey(string(index), chain, key)
+	k := IndexKey(index, chain, key)
 	tx.state.logInfo("WriteIndex", "index", string(index), "chain", hex.EncodeToString(chain), "key", key, "value", hex.EncodeToString(value), "computed", hex.EncodeToString(k[:]))
 	tx.Write(k, value)
 }
 
 func (tx *DBTransaction) GetIndex(index Index, chain []byte, key interface{}) ([]byte, error) {
-	k := storage.ComputeKey(string(index), chain, key)
+	k := IndexKey(index, chain, key)
 	tx.state.logInfo("GetIndex", "index", string(index), "chain", hex.EncodeToString(chain), "key", key, "computed", hex.EncodeToString(k[:]))
-	return tx.Read(k)
+	b, err := tx.Read(k)
+	if errors.Is(err, storage.ErrNotFound) {
+		return tx.Read(legacyIndexKey(index, chain, key))
+	}
+	return b, err
 }
 
 func (db *StateDB) GetIndex(index Index, chain []byte, key interface{}) ([]byte, error) {
-	k := storage.ComputeKey(string(index), chain, key)
+	k := IndexKey(index, chain, key)
 	db.logInfo("GetIndex", "index", string(index), "chain", hex.EncodeToString(chain), "key", key, "computed", hex.EncodeToString(k[:]))
-	return db.Read(k)
+	b, err := db.Read(k)
+	if errors.Is(err, storage.ErrNotFound) {
+		return db.Read(legacyIndexKey(index, chain, key))
+	}
+	return b, err
 }
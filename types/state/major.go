@@ -0,0 +1,160 @@
+package state
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// MajorBlockScheduleKey returns the key of the record of the major block
+// schedule of the network. The major anchor chain is part of the BPT, so every
+// node must close major blocks at the same blocks. The schedule is therefore
+// set by the genesis state of the network rather than by the configuration of
+// each node. A network without the record has no major blocks.
+func MajorBlockScheduleKey() [32]byte {
+	return storage.ComputeKey("MajorBlockSchedule")
+}
+
+// MajorAnchorChainKey returns the key of the major anchor chain in the BPT.
+func MajorAnchorChainKey() [32]byte {
+	var id [32]byte
+	copy(id[:], []byte(bucketMajorAnchorChain.String()))
+	return id
+}
+
+// majorBlockSchedule returns the major block schedule of the network.
+func (s *StateDB) majorBlockSchedule() (*MajorBlockSchedule, error) {
+	schedule := new(MajorBlockSchedule)
+	b, err := s.ReadRecord(MajorBlockScheduleKey())
+	if errors.Is(err, storage.ErrNotFound) {
+		return schedule, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to load the major block schedule: %v", err)
+	}
+
+	err = schedule.UnmarshalBinary(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid major block schedule: %v", err)
+	}
+	return schedule, nil
+}
+
+// ends returns true if the previous block is the last block of a major block,
+// given the index and timestamp of the next block.
+func (s *MajorBlockSchedule) ends(prev *AnchorMetadata, blockIndex int64, timestamp time.Time) bool {
+	if s.Blocks > 0 && prev.Index/s.Blocks != blockIndex/s.Blocks {
+		return true
+	}
+	if s.Period > 0 && !prev.Timestamp.Truncate(s.Period).Equal(timestamp.Truncate(s.Period)) {
+		return true
+	}
+	return false
+}
+
+// writeMajorAnchor closes the major block if the previous block ended it. The
+// major block covers every element added to the minor anchor chain since the
+// last major block, up to and including the anchor of the previous block. It
+// must be called before the anchors of the next block are added.
+func (tx *DBTransaction) writeMajorAnchor(prev *AnchorMetadata, blockIndex int64, timestamp time.Time) error {
+	if prev.Index < 0 {
+		return nil
+	}
+
+	schedule, err := tx.state.majorBlockSchedule()
+	if err != nil {
+		return err
+	}
+	if !schedule.ends(prev, blockIndex, timestamp) {
+		return nil
+	}
+
+	last, err := tx.state.getMajorAnchorHead()
+	if errors.Is(err, storage.ErrNotFound) {
+		last = &MajorAnchorMetadata{Index: -1}
+	} else if err != nil {
+		return err
+	}
+
+	err = tx.state.mm.SetChainID([]byte(bucketMinorAnchorChain))
+	if err != nil {
+		return err
+	}
+
+	head := new(MajorAnchorMetadata)
+	head.Index = last.Index + 1
+	head.LastBlock = prev.Index
+	head.Timestamp = prev.Timestamp
+	head.MinorStart = last.MinorEnd
+	head.MinorEnd = tx.state.mm.MS.Count
+	copy(head.MinorRoot[:], tx.state.mm.MS.GetMDRoot())
+	if head.MinorStart >= head.MinorEnd {
+		return nil
+	}
+
+	data, err := head.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	err = tx.state.mm.SetChainID([]byte(bucketMajorAnchorChain))
+	if err != nil {
+		return err
+	}
+	tx.state.mm.AddHash(data)
+
+	tx.state.bpt.Bpt.Insert(MajorAnchorChainKey(), sha256.Sum256(data))
+	tx.majorAnchor = head
+	return nil
+}
+
+// MajorAnchor returns the major block closed when the transaction was
+// committed, or nil if the commit did not close one.
+func (tx *DBTransaction) MajorAnchor() *MajorAnchorMetadata {
+	return tx.majorAnchor
+}
+
+// GetMajorAnchor returns the major block with the given index. The first major
+// block has index zero.
+func (s *StateDB) GetMajorAnchor(index int64) (*MajorAnchorMetadata, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.db.Key(bucketMajorAnchorChain.Bytes(), "Element", index).Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load major block %d: %w", index, err)
+	}
+
+	head := new(MajorAnchorMetadata)
+	err = head.UnmarshalBinary(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid major block %d: %v", index, err)
+	}
+	return head, nil
+}
+
+func (s *StateDB) getMajorAnchorHead() (*MajorAnchorMetadata, error) {
+	err := s.mm.SetChainID([]byte(bucketMajorAnchorChain))
+	if err != nil {
+		return nil, err
+	}
+
+	if s.mm.MS.Count == 0 {
+		return nil, storage.ErrNotFound
+	}
+
+	data, err := s.mm.Get(s.mm.MS.Count - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read major anchor chain element %d", s.mm.MS.Count-1)
+	}
+
+	head := new(MajorAnchorMetadata)
+	err = head.UnmarshalBinary(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return head, nil
+}
@@ -0,0 +1,99 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMajorAnchor(t *testing.T) {
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	commit := func(db *StateDB, height int64, timestamp time.Time) {
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, timestamp)
+		require.NoError(t, err)
+	}
+
+	// The schedule is set by the genesis block
+	open := func(blocks int64, period time.Duration) *StateDB {
+		db := new(StateDB)
+		require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+		b, err := (&MajorBlockSchedule{Blocks: blocks, Period: period}).MarshalBinary()
+		require.NoError(t, err)
+		dbTx := db.Begin()
+		dbTx.WriteRecord(MajorBlockScheduleKey(), b)
+		_, err = dbTx.Commit(0, time.Unix(0, 0))
+		require.NoError(t, err)
+		return db
+	}
+
+	t.Run("Blocks", func(t *testing.T) {
+		db := open(3, 0)
+		for height := int64(1); height <= 10; height++ {
+			commit(db, height, time.Unix(height, 0))
+		}
+
+		// The genesis block adds the anchor metadata to the minor anchor
+		// chain, and every other block adds the root of the chain and the
+		// anchor metadata
+		var minorEnd int64
+		for i, lastBlock := range []int64{2, 5, 8} {
+			major, err := db.GetMajorAnchor(int64(i))
+			require.NoError(t, err)
+			require.Equal(t, int64(i), major.Index)
+			require.Equal(t, lastBlock, major.LastBlock)
+			require.Equal(t, time.Unix(lastBlock, 0).UTC(), major.Timestamp.UTC())
+			require.Equal(t, minorEnd, major.MinorStart)
+			require.Equal(t, lastBlock*2+1, major.MinorEnd)
+			minorEnd = major.MinorEnd
+		}
+		_, err := db.GetMajorAnchor(3)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+
+		var problems []error
+		require.NoError(t, db.Verify(func(err error) { problems = append(problems, err) }))
+		require.Empty(t, problems)
+
+		// The major anchor chain is part of a snapshot
+		data, err := db.Snapshot()
+		require.NoError(t, err)
+		dst := new(StateDB)
+		require.NoError(t, dst.Open("memory", "", storage.Options{}, false))
+		require.NoError(t, dst.RestoreSnapshot(data, db.RootHash()))
+		major, err := dst.GetMajorAnchor(2)
+		require.NoError(t, err)
+		require.Equal(t, int64(8), major.LastBlock)
+	})
+
+	t.Run("Period", func(t *testing.T) {
+		db := open(0, 10*time.Second)
+		for height := int64(1); height <= 6; height++ {
+			commit(db, height, time.Unix(height*4, 0))
+		}
+
+		// Blocks 1 and 2 fall in the first period, 3 and 4 in the second
+		for i, lastBlock := range []int64{2, 4} {
+			major, err := db.GetMajorAnchor(int64(i))
+			require.NoError(t, err)
+			require.Equal(t, lastBlock, major.LastBlock)
+		}
+		_, err := db.GetMajorAnchor(2)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		db := new(StateDB)
+		require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+		for height := int64(1); height <= 5; height++ {
+			commit(db, height, time.Unix(height*3600, 0))
+		}
+		_, err := db.GetMajorAnchor(0)
+		require.ErrorIs(t, err, storage.ErrNotFound)
+	})
+}
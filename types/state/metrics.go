@@ -0,0 +1,49 @@
+package state
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// RegisterMetrics registers metrics for the BPT cache with the registerer.
+func (s *StateDB) RegisterMetrics(reg prometheus.Registerer, namespace string) error {
+	collectors := []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "bpt",
+			Name:      "cache_size",
+			Help:      "Number of BPT byte blocks kept in memory, or zero if unbounded.",
+		}, func() float64 { return float64(s.bptCacheSize) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "bpt",
+			Name:      "cache_loaded",
+			Help:      "Number of BPT byte blocks held in memory.",
+		}, func() float64 { return float64(s.BPTCacheStats().Loaded) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "bpt",
+			Name:      "cache_hits_total",
+			Help:      "Number of BPT byte block loads satisfied from memory.",
+		}, func() float64 { return float64(s.BPTCacheStats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "bpt",
+			Name:      "cache_misses_total",
+			Help:      "Number of BPT byte block loads that read the database.",
+		}, func() float64 { return float64(s.BPTCacheStats().Misses) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "bpt",
+			Name:      "cache_evictions_total",
+			Help:      "Number of BPT byte blocks unloaded to bound memory.",
+		}, func() float64 { return float64(s.BPTCacheStats().Evictions) }),
+	}
+
+	for _, c := range collectors {
+		err := reg.Register(c)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
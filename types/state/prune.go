@@ -0,0 +1,87 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// maxPrunedHeights limits how many blocks are pruned by a single commit, so
+// enabling pruning on a node with a long history does not stall a block.
+const maxPrunedHeights = 1000
+
+// SetPendingRetention sets the number of blocks pending transactions are kept
+// for. Zero disables pruning.
+//
+// Pending transactions are not part of the BPT, so the retention does not
+// affect consensus and nodes may use different values.
+func (s *StateDB) SetPendingRetention(blocks int64) {
+	s.pendingRetention = blocks
+}
+
+// prunePending deletes the pending transactions recorded in blocks that have
+// fallen out of the retention window. Pruning proceeds in block order from the
+// last pruned height, so every node that uses the same retention deletes the
+// same records at the same height.
+func (tx *DBTransaction) prunePending(blockHeight int64) error {
+	if tx.state.pendingRetention <= 0 {
+		return nil
+	}
+
+	end := blockHeight - tx.state.pendingRetention
+	if end <= 0 {
+		return nil
+	}
+
+	db := tx.state.db
+	start := int64(1)
+	b, err := db.Key(bucketPendingTx, "Pruned").Get()
+	switch {
+	case err == nil:
+		start, _ = common.BytesInt64(b)
+		start++
+	case !errors.Is(err, storage.ErrNotFound):
+		return fmt.Errorf("failed to load the last pruned height: %v", err)
+	}
+
+	if end-start >= maxPrunedHeights {
+		end = start + maxPrunedHeights - 1
+	}
+
+	for height := start; height <= end; height++ {
+		records, err := db.Key(bucketPendingTx, "Height", height).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return fmt.Errorf("failed to load the pending transactions of block %d: %v", height, err)
+		}
+
+		if len(records)%64 != 0 {
+			return fmt.Errorf("invalid pending transaction list for block %d", height)
+		}
+
+		for ; len(records) > 0; records = records[64:] {
+			txId, pendingHash := records[:32], records[32:64]
+
+			// If the transaction was recorded again in a later block, the
+			// newer record must be kept
+			current, err := db.Key(bucketMainToPending, txId).Get()
+			if err == nil && !bytes.Equal(current, pendingHash) {
+				continue
+			}
+
+			db.Key(bucketMainToPending, txId).DeleteBatch()
+			db.Key(bucketPendingTx, pendingHash).DeleteBatch()
+		}
+
+		db.Key(bucketPendingTx, "Height", height).DeleteBatch()
+	}
+
+	if end >= start {
+		db.Key(bucketPendingTx, "Pruned").PutBatch(common.Int64Bytes(end))
+	}
+	return nil
+}
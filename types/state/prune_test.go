@@ -0,0 +1,55 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrunePending(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+	db.SetPendingRetention(2)
+
+	gtx := new(transactions.GenTransaction)
+	gtx.SigInfo = new(transactions.SignatureInfo)
+	gtx.SigInfo.URL = "RedWagon/myAccount"
+	gtx.Transaction = []byte("transaction")
+	txId := gtx.TransactionHash()
+
+	accepted, pending := NewTransaction(NewPendingTransaction(gtx))
+	pendingData, err := pending.MarshalBinary()
+	require.NoError(t, err)
+	acceptedData, err := accepted.MarshalBinary()
+	require.NoError(t, err)
+
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	commit := func(height int64, withTx bool) {
+		dbTx := db.Begin()
+		if withTx {
+			require.NoError(t, dbTx.AddTransaction(&chainId, txId, &Object{Entry: pendingData}, &Object{Entry: acceptedData}))
+		}
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Time{})
+		require.NoError(t, err)
+	}
+
+	// The pending transaction is kept for the retention window
+	commit(1, true)
+	commit(2, false)
+	_, err = db.GetPendingTx(txId)
+	require.NoError(t, err)
+
+	// Once the window has passed it is pruned, but the transaction is not
+	commit(3, false)
+	_, err = db.GetPendingTx(txId)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = db.GetTx(txId)
+	require.NoError(t, err)
+}
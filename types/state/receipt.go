@@ -0,0 +1,131 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// GetReceipt builds a receipt that proves that the element is in the Merkle
+// chain. The receipt leads from the element to the root of the chain as of
+// when the anchor was added to it, or to the current root of the chain if the
+// anchor is nil. The anchor must be an element of the chain that was added no
+// earlier than the element. GetReceipt also returns the indices of the element
+// and the anchor.
+func (s *StateDB) GetReceipt(chainId, element, anchor []byte) (receipt *managed.Receipt, elementIndex, anchorIndex int64, err error) {
+	s.Sync()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.getReceipt(chainId, element, anchor)
+}
+
+func (s *StateDB) getReceipt(chainId, element, anchor []byte) (receipt *managed.Receipt, elementIndex, anchorIndex int64, err error) {
+	err = s.mm.SetChainID(chainId)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	elementIndex, err = s.mm.GetElementIndex(element)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, 0, 0, fmt.Errorf("%w: %X is not an element of chain %X", storage.ErrNotFound, element, chainId)
+	} else if err != nil {
+		return nil, 0, 0, err
+	}
+
+	if anchor == nil {
+		anchorIndex = s.mm.GetElementCount() - 1
+		anchor, err = s.mm.Get(anchorIndex)
+		if err != nil {
+			return nil, 0, 0, fmt.Errorf("failed to load element %d of chain %X: %v", anchorIndex, chainId, err)
+		}
+	} else {
+		anchorIndex, err = s.mm.GetElementIndex(anchor)
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, 0, 0, fmt.Errorf("%w: anchor %X is not an element of chain %X", storage.ErrNotFound, anchor, chainId)
+		} else if err != nil {
+			return nil, 0, 0, err
+		}
+	}
+
+	if anchorIndex < elementIndex {
+		return nil, 0, 0, fmt.Errorf("anchor %d precedes element %d", anchorIndex, elementIndex)
+	}
+
+	receipt = managed.GetReceipt(s.mm, element, anchor)
+	if receipt == nil {
+		return nil, 0, 0, fmt.Errorf("failed to build a receipt for element %d of chain %X", elementIndex, chainId)
+	}
+	return receipt, elementIndex, anchorIndex, nil
+}
+
+// Proof is the parts of a proof that an entry of a chain is part of the state
+// as of the last block. Entry leads from the entry to the current root of the
+// chain. Anchor leads from that root, which was added to the minor anchor chain
+// by the last block that updated the chain, to the root of the minor anchor
+// chain recorded by AnchorHead. State leads from the value of the minor anchor
+// chain in the BPT, which is the hash of AnchorHead, to the root of the BPT.
+type Proof struct {
+	Height     int64
+	Entry      *managed.Receipt
+	Anchor     *managed.Receipt
+	AnchorHead []byte
+	State      *managed.Receipt
+}
+
+// GetProof builds a proof that the element is in the Merkle chain and that the
+// chain is part of the state as of the last block.
+func (s *StateDB) GetProof(chainId, element []byte) (*Proof, error) {
+	s.Sync()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	proof := new(Proof)
+	var err error
+	proof.Entry, _, _, err = s.getReceipt(chainId, element, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	head, err := s.getAnchorHead()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the anchor head: %v", err)
+	}
+	proof.Height = head.Index
+
+	// The head is the last element of the anchor chain and the root it records
+	// is the root of the chain as of the element before it
+	count := s.mm.MS.Count
+	proof.AnchorHead, err = s.mm.Get(count - 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load element %d of the anchor chain: %v", count-1, err)
+	}
+	anchor, err := s.mm.Get(count - 2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load element %d of the anchor chain: %v", count-2, err)
+	}
+
+	proof.Anchor, _, _, err = s.getReceipt(bucketMinorAnchorChain.Bytes(), proof.Entry.Anchor, anchor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove the root of chain %X: %v", chainId, err)
+	}
+	if !bytes.Equal(proof.Anchor.Anchor, head.Root[:]) {
+		return nil, fmt.Errorf("anchor head %d does not record the root of the anchor chain", head.Index)
+	}
+
+	proof.State, err = s.bpt.GetReceipt(MinorAnchorChainKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to prove the anchor head: %v", err)
+	}
+	hash := sha256.Sum256(proof.AnchorHead)
+	key := MinorAnchorChainKey()
+	leaf := sha256.Sum256(append(key[:], hash[:]...))
+	if !bytes.Equal(proof.State.Element, leaf[:]) {
+		return nil, fmt.Errorf("the BPT does not hold anchor head %d", head.Index)
+	}
+
+	return proof, nil
+}
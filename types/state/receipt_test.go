@@ -0,0 +1,61 @@
+package state_test
+
+import (
+	"crypto/sha256"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/managed"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetReceipt(t *testing.T) {
+	db := new(StateDB)
+	require.NoError(t, db.Open("memory", "", storage.Options{}, false))
+
+	// Add 300 transactions to the chain, so the receipts cross mark points
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	var txids []types.Bytes32
+	for i := 0; i < 300; i++ {
+		txid := types.Bytes32(sha256.Sum256([]byte{byte(i), byte(i >> 8)}))
+		txids = append(txids, txid)
+
+		dbTx := db.Begin()
+		dbTx.AddStateEntry(&chainId, &txid, &Object{Entry: []byte{byte(i)}})
+		_, err := dbTx.Commit(int64(i+1), time.Unix(int64(i), 0))
+		require.NoError(t, err)
+	}
+
+	head, err := db.GetDB().Key(chainId[:], "Head").Get()
+	require.NoError(t, err)
+	ms := new(managed.MerkleState)
+	require.NoError(t, ms.UnMarshal(head))
+	ms.InitSha256()
+
+	for _, i := range []int{0, 1, 127, 255, 256, 299} {
+		// To the current root of the chain
+		receipt, elementIndex, anchorIndex, err := db.GetReceipt(chainId[:], txids[i][:], nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(i), elementIndex)
+		require.Equal(t, int64(299), anchorIndex)
+		require.True(t, receipt.Validate(), "element %d", i)
+		require.Equal(t, ms.GetMDRoot(), receipt.Anchor, "element %d", i)
+
+		// To a later element
+		receipt, _, anchorIndex, err = db.GetReceipt(chainId[:], txids[i][:], txids[(i+299)/2][:])
+		require.NoError(t, err)
+		require.Equal(t, int64((i+299)/2), anchorIndex)
+		require.True(t, receipt.Validate(), "element %d", i)
+	}
+
+	// The anchor cannot precede the element
+	_, _, _, err = db.GetReceipt(chainId[:], txids[10][:], txids[5][:])
+	require.Error(t, err)
+
+	// The element must be in the chain
+	_, _, _, err = db.GetReceipt(chainId[:], make([]byte, 32), nil)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
@@ -0,0 +1,66 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"sort"
+	"sync"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// Records hold state that does not belong to any chain, such as the open swap
+// offers of the BVC. Unlike the indexes, each record is added to the BPT
+// under its key, so records are covered by the app hash and carried by
+// snapshots.
+
+// WriteRecord queues a record to be written and added to the BPT when the
+// transaction is committed.
+func (tx *DBTransaction) WriteRecord(key [32]byte, value []byte) {
+	tx.state.mutex.Lock()
+	defer tx.state.mutex.Unlock()
+	tx.records[key] = value
+}
+
+// ReadRecord returns a record, including one queued by the transaction.
+func (tx *DBTransaction) ReadRecord(key [32]byte) ([]byte, error) {
+	tx.state.mutex.Lock()
+	value, ok := tx.records[key]
+	tx.state.mutex.Unlock()
+	if ok {
+		return value, nil
+	}
+	return tx.state.ReadRecord(key)
+}
+
+// RecordStorageKey returns the database key a record is stored under, for
+// records that are written to the database directly, such as those of the
+// genesis app state.
+func RecordStorageKey(key [32]byte) storage.Key {
+	return storage.ComputeKey(bucketRecord, key[:])
+}
+
+// ReadRecord returns a record written by WriteRecord.
+func (s *StateDB) ReadRecord(key [32]byte) ([]byte, error) {
+	return s.db.Key(bucketRecord, key[:]).Get()
+}
+
+// writeRecords writes the queued records in order and adds them to the BPT.
+func (tx *DBTransaction) writeRecords(mutex *sync.Mutex) {
+	order := make([][32]byte, 0, len(tx.records))
+	for k := range tx.records {
+		order = append(order, k)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return bytes.Compare(order[i][:], order[j][:]) < 0
+	})
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, k := range order {
+		value := tx.records[k]
+		tx.state.db.Key(bucketRecord, k[:]).PutBatch(value)
+		tx.state.bpt.Bpt.Insert(k, sha256.Sum256(value))
+		delete(tx.records, k)
+	}
+}
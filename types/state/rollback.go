@@ -0,0 +1,120 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// SetUndoRetention sets the number of blocks that can be rolled back. Zero
+// disables recording undo information.
+func (s *StateDB) SetUndoRetention(blocks int64) {
+	s.undoRetention = blocks
+}
+
+// writeUndo records a batch that reverts the block: the previous value of
+// every key the block writes, including the chain heads and the BPT byte
+// blocks. It must be called after everything else in the block has been added
+// to the batch.
+//
+// writeUndo also deletes the undo records of blocks that have fallen out of
+// the retention window and advances the floor, the lowest height the state can
+// be rolled back to. The floor is written after the undo record is computed,
+// so rolling back does not lower it.
+func (tx *DBTransaction) writeUndo(blockHeight int64) error {
+	if tx.state.undoRetention <= 0 {
+		return nil
+	}
+
+	db := tx.state.db
+	undo, err := db.UndoBatch()
+	if err != nil {
+		return err
+	}
+	db.Key(bucketUndo, blockHeight).PutBatch(storage.MarshalBatch(undo))
+
+	floor, err := tx.state.undoFloor()
+	if errors.Is(err, storage.ErrNotFound) {
+		// Nothing before this block can be undone
+		floor = blockHeight - 1
+	} else if err != nil {
+		return err
+	}
+
+	end := blockHeight - tx.state.undoRetention
+	if end-floor > maxPrunedHeights {
+		end = floor + maxPrunedHeights
+	}
+	for height := floor + 1; height <= end; height++ {
+		db.Key(bucketUndo, height).DeleteBatch()
+	}
+	if end > floor {
+		floor = end
+	}
+
+	db.Key(bucketUndo, "Floor").PutBatch(common.Int64Bytes(floor))
+	return nil
+}
+
+func (s *StateDB) undoFloor() (int64, error) {
+	b, err := s.db.Key(bucketUndo, "Floor").Get()
+	if err != nil {
+		return 0, err
+	}
+	floor, _ := common.BytesInt64(b)
+	return floor, nil
+}
+
+// Rollback rewinds the state to the given height by applying the undo records
+// of the later blocks, latest first. Each block is rolled back in a single
+// batch, so if Rollback is interrupted the state is left at an intermediate
+// block and Rollback can be run again.
+func (s *StateDB) Rollback(height int64) error {
+	s.Sync()
+
+	floor, err := s.undoFloor()
+	if errors.Is(err, storage.ErrNotFound) {
+		return errors.New("the database has no undo information")
+	} else if err != nil {
+		return fmt.Errorf("failed to load the undo floor: %v", err)
+	}
+	if height < floor {
+		return fmt.Errorf("cannot roll back to %d, the lowest height that can be rolled back to is %d", height, floor)
+	}
+
+	for {
+		current, err := s.BlockIndex()
+		if errors.Is(err, storage.ErrNotFound) {
+			break
+		} else if err != nil {
+			return err
+		}
+		if current <= height {
+			break
+		}
+
+		data, err := s.db.Key(bucketUndo, current).Get()
+		if err != nil {
+			return fmt.Errorf("failed to load the undo record of block %d: %v", current, err)
+		}
+		undo, err := storage.UnmarshalBatch(data)
+		if err != nil {
+			return fmt.Errorf("invalid undo record for block %d: %v", current, err)
+		}
+
+		for k, v := range undo {
+			if v == nil {
+				s.db.Key(k).DeleteBatch()
+			} else {
+				s.db.Key(k).PutBatch(v)
+			}
+		}
+		s.db.Key(bucketUndo, current).DeleteBatch()
+		s.db.EndBatch()
+	}
+
+	s.loadBPT()
+	return nil
+}
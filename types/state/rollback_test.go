@@ -0,0 +1,65 @@
+package state_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/smt/storage/memory"
+	"github.com/AccumulateNetwork/accumulate/types"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRollback(t *testing.T) {
+	kvdb := new(memory.DB)
+	require.NoError(t, kvdb.InitDB(""))
+	db := new(StateDB)
+	require.NoError(t, db.Load(kvdb, false))
+	db.SetUndoRetention(2)
+
+	chainId := types.Bytes32{1}
+	commit := func(height int64, entry byte) {
+		dbTx := db.Begin()
+		if height == 3 {
+			dbTx.WriteIndex(DirectoryIndex, chainId[:], "Metadata", []byte{entry})
+		}
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{entry}, &Object{Entry: []byte{entry}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	roots := map[int64][]byte{}
+	for height := int64(1); height <= 4; height++ {
+		commit(height, byte(height))
+		roots[height] = db.RootHash()
+	}
+
+	// Blocks beyond the retention window cannot be undone
+	require.Error(t, db.Rollback(1))
+
+	require.NoError(t, db.Rollback(2))
+	require.Equal(t, roots[2], db.RootHash())
+	height, err := db.BlockIndex()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+
+	entry, err := db.GetPersistentEntry(chainId[:], false)
+	require.NoError(t, err)
+	require.Equal(t, []byte{2}, entry.Entry)
+	_, err = db.GetIndex(DirectoryIndex, chainId[:], "Metadata")
+	require.True(t, errors.Is(err, storage.ErrNotFound), "expected not found, got %v", err)
+
+	// The rolled back database is consistent when it is loaded
+	db = new(StateDB)
+	require.NoError(t, db.Load(kvdb, false))
+	db.SetUndoRetention(2)
+	require.Equal(t, roots[2], db.RootHash())
+
+	// And the chain can continue from there
+	commit(3, 3)
+	require.Equal(t, roots[3], db.RootHash())
+	commit(4, 5)
+	require.NotEqual(t, roots[4], db.RootHash())
+}
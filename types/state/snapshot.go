@@ -0,0 +1,365 @@
+package state
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/AccumulateNetwork/accumulate/smt/common"
+	"github.com/AccumulateNetwork/accumulate/smt/pmt"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// Snapshot serializes the current state as a sequence of snapshot records: the
+// genesis records, the BPT byte blocks, the record behind every value of the
+// BPT and the heads of the Merkle chains. Chain entries other than the heads,
+// pending transactions, and the indexes are not part of a snapshot.
+func (s *StateDB) Snapshot() ([]byte, error) {
+	s.Sync()
+
+	buf := new(bytes.Buffer)
+	err := s.writeSnapshot(buf)
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// SnapshotView is a read-only view of the state as of the block it was taken
+// at. A snapshot can be written from a view while blocks are committed to the
+// StateDB the view was taken from.
+type SnapshotView struct {
+	state *StateDB
+}
+
+// SnapshotView takes a view of the committed state, from which a snapshot can
+// be written. The view must be closed once the snapshot is written. Taking a
+// view does not read the state, so it can be done between blocks without
+// holding up the next block.
+func (s *StateDB) SnapshotView() (*SnapshotView, error) {
+	s.Sync()
+
+	viewer, ok := s.db.DB.(storage.Viewer)
+	if !ok {
+		return nil, errors.New("the database does not support views")
+	}
+
+	db, err := viewer.View()
+	if err != nil {
+		return nil, fmt.Errorf("failed to take a view of the database: %v", err)
+	}
+
+	view := new(StateDB)
+	err = view.Load(db, false)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SnapshotView{view}, nil
+}
+
+// BlockIndex returns the height of the block the view was taken at.
+func (v *SnapshotView) BlockIndex() (int64, error) {
+	return v.state.BlockIndex()
+}
+
+// WriteTo writes a snapshot of the view, in the format of Snapshot, a record
+// at a time.
+func (v *SnapshotView) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	err := v.state.writeSnapshot(cw)
+	return cw.n, err
+}
+
+// Close releases the view.
+func (v *SnapshotView) Close() error {
+	return v.state.db.Close()
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.w.Write(b)
+	w.n += int64(n)
+	return n, err
+}
+
+// writeSnapshot writes the snapshot records to w as they are read.
+func (s *StateDB) writeSnapshot(w io.Writer) error {
+	return s.walkSnapshot(func(key storage.Key, optional bool) error {
+		value, err := s.db.Key(key).Get()
+		switch {
+		case err == nil:
+			rec := &SnapshotRecord{Key: key, Value: value}
+			b, err := rec.MarshalBinary()
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(b)
+			return err
+		case optional && errors.Is(err, storage.ErrNotFound):
+			return nil
+		default:
+			return fmt.Errorf("failed to load %X: %v", key, err)
+		}
+	})
+}
+
+// walkSnapshot calls fn with the key of each record of a snapshot, in order.
+// Optional records may not exist.
+func (s *StateDB) walkSnapshot(fn func(key storage.Key, optional bool) error) error {
+	err := fn(storage.ComputeKey("SubnetID"), true)
+	if err != nil {
+		return err
+	}
+
+	err = fn(storage.ComputeKey("BPT", "Root"), false)
+	if err != nil {
+		return err
+	}
+
+	return s.bpt.Walk(func(e pmt.Entry) error {
+		switch e := e.(type) {
+		case *pmt.Node:
+			if !s.bpt.IsByteBlock(e) {
+				return nil
+			}
+			return fn(storage.ComputeKey("BPT", e.BBKey[:]), false)
+
+		case *pmt.Value:
+			key, value, err := s.leafRecord(e.Key, e.Hash)
+			if err != nil {
+				return err
+			}
+			err = fn(key, false)
+			if err != nil {
+				return err
+			}
+
+			switch {
+			case key == storage.ComputeKey(bucketEntry, e.Key[:]):
+				return fn(storage.ComputeKey(e.Key[:], "Head"), true)
+
+			case e.Key == MinorAnchorChainKey():
+				head := new(AnchorMetadata)
+				err = head.UnmarshalBinary(value)
+				if err != nil {
+					return fmt.Errorf("invalid anchor: %v", err)
+				}
+				err = fn(storage.ComputeKey(bucketMinorAnchorChain.Bytes(), "Head"), false)
+				if err != nil {
+					return err
+				}
+				return fn(storage.ComputeKey(bucketMinorAnchorChain, "Index", head.Index), false)
+
+			case e.Key == MajorAnchorChainKey():
+				return fn(storage.ComputeKey(bucketMajorAnchorChain.Bytes(), "Head"), false)
+			}
+		}
+		return nil
+	})
+}
+
+// RestoreSnapshot loads a snapshot created by Snapshot. The snapshot is
+// verified before it is committed to the database: the BPT must hash to the
+// given app hash, every value of the BPT must be backed by a record with that
+// hash, the head of every chain must match the chain's state entry, and the
+// snapshot must not contain any record that is not reached from the BPT.
+// Snapshots are served by untrusted peers, so a snapshot is only accepted if
+// all of its records are covered by the app hash.
+//
+// The indexes are not part of a snapshot, since they are not covered by the
+// app hash. None of them are needed to execute transactions, but a restored
+// node only indexes the directories, transfers, key pages and data entries
+// written after the snapshot was taken. Likewise the elements of the chains
+// are not part of a snapshot, so the head of each chain is recorded as the
+// base its history is verified from.
+//
+// RestoreSnapshot must only be used to initialize an empty database.
+func (s *StateDB) RestoreSnapshot(data, appHash []byte) error {
+	s.Sync()
+	s.db.BeginBatch()
+
+	keys := map[storage.Key]bool{}
+	for len(data) > 0 {
+		rec := new(SnapshotRecord)
+		err := rec.UnmarshalBinary(data)
+		if err != nil {
+			s.db.ClearCache()
+			return fmt.Errorf("invalid snapshot record: %v", err)
+		}
+		data = data[rec.BinarySize():]
+
+		// An empty value decodes as nil, which would delete the key
+		value := rec.Value
+		if value == nil {
+			value = []byte{}
+		}
+		s.db.Key(storage.Key(rec.Key)).PutBatch(value)
+		keys[rec.Key] = true
+	}
+
+	err := s.verifySnapshot(appHash)
+	if err == nil {
+		err = s.walkSnapshot(func(key storage.Key, _ bool) error {
+			delete(keys, key)
+			return nil
+		})
+	}
+	if err == nil && len(keys) > 0 {
+		err = fmt.Errorf("snapshot has %d records that are not part of the state", len(keys))
+	}
+
+	// Only the heads of the chains are restored, so the history of each chain
+	// starts at its head
+	if err == nil {
+		for _, chainId := range s.chainIds() {
+			err = s.mm.WriteChainBase(chainId)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		s.db.ClearCache()
+		s.loadBPT()
+		return err
+	}
+
+	s.db.EndBatch()
+	return nil
+}
+
+func (s *StateDB) verifySnapshot(appHash []byte) error {
+	s.loadBPT()
+	if !bytes.Equal(s.RootHash(), appHash) {
+		return fmt.Errorf("snapshot root hash %X does not match the app hash %X", s.RootHash(), appHash)
+	}
+
+	err := s.bpt.Verify()
+	if err != nil {
+		return fmt.Errorf("invalid snapshot BPT: %v", err)
+	}
+
+	return s.bpt.Walk(func(e pmt.Entry) error {
+		v, ok := e.(*pmt.Value)
+		if !ok {
+			return nil
+		}
+
+		key, value, err := s.leafRecord(v.Key, v.Hash)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case key == storage.ComputeKey(bucketEntry, v.Key[:]):
+			return s.verifyChainHead(v.Key, value)
+		case v.Key == MinorAnchorChainKey():
+			return s.verifyAnchorIndex(value)
+		}
+		return nil
+	})
+}
+
+// leafRecord finds the record a value of the BPT was computed from. A BPT key
+// can be a chain ID, a transaction ID, a synthetic transaction ID, the key of
+// a record, or the key of one of the anchor chains, so each of those is tried
+// in turn until a record with the expected hash is found.
+func (s *StateDB) leafRecord(key, hash [32]byte) (storage.Key, []byte, error) {
+	candidates := []storage.Key{
+		storage.ComputeKey(bucketEntry, key[:]),
+		storage.ComputeKey(bucketTx, key[:]),
+		storage.ComputeKey(bucketStagedSynthTx, "", key[:]),
+		storage.ComputeKey(bucketRecord, key[:]),
+	}
+
+	if key == MinorAnchorChainKey() {
+		head, err := s.mm.ReadChainHead(bucketMinorAnchorChain.Bytes())
+		if err != nil {
+			return storage.Key{}, nil, err
+		}
+		candidates = append(candidates, storage.ComputeKey(bucketMinorAnchorChain.Bytes(), "Element", head.Count-1))
+	}
+	if key == MajorAnchorChainKey() {
+		head, err := s.mm.ReadChainHead(bucketMajorAnchorChain.Bytes())
+		if err != nil {
+			return storage.Key{}, nil, err
+		}
+		candidates = append(candidates, storage.ComputeKey(bucketMajorAnchorChain.Bytes(), "Element", head.Count-1))
+	}
+
+	for _, k := range candidates {
+		value, err := s.db.Key(k).Get()
+		if errors.Is(err, storage.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return storage.Key{}, nil, err
+		}
+
+		if sha256.Sum256(value) == hash {
+			return k, value, nil
+		}
+	}
+
+	return storage.Key{}, nil, fmt.Errorf("no record found for BPT key %X", key)
+}
+
+// verifyChainHead checks that the head of the chain has the height and roots
+// recorded in the chain's state entry.
+func (s *StateDB) verifyChainHead(chainId [32]byte, entry []byte) error {
+	obj := new(Object)
+	err := obj.UnmarshalBinary(entry)
+	if err != nil {
+		return fmt.Errorf("invalid state entry for %X: %v", chainId, err)
+	}
+
+	head, err := s.mm.ReadChainHead(chainId[:])
+	if err != nil {
+		return err
+	}
+
+	if uint64(head.Count) != obj.Height {
+		return fmt.Errorf("chain %X has height %d but its state entry has height %d", chainId, head.Count, obj.Height)
+	}
+
+	for i, root := range obj.Roots {
+		if obj.Height&(1<<i) == 0 {
+			continue
+		}
+		if i >= len(head.Pending) || !bytes.Equal(root, head.Pending[i]) {
+			return fmt.Errorf("chain %X does not match the roots of its state entry", chainId)
+		}
+	}
+
+	return nil
+}
+
+// verifyAnchorIndex checks that the block index of the latest anchor points to
+// the head of the anchor chain.
+func (s *StateDB) verifyAnchorIndex(anchor []byte) error {
+	meta := new(AnchorMetadata)
+	err := meta.UnmarshalBinary(anchor)
+	if err != nil {
+		return fmt.Errorf("invalid anchor: %v", err)
+	}
+
+	head, err := s.mm.ReadChainHead(bucketMinorAnchorChain.Bytes())
+	if err != nil {
+		return err
+	}
+
+	index, err := s.db.Key(bucketMinorAnchorChain, "Index", meta.Index).Get()
+	if err != nil {
+		return fmt.Errorf("failed to load the anchor index of block %d: %v", meta.Index, err)
+	}
+	if !bytes.Equal(index, common.Int64Bytes(head.Count)) {
+		return fmt.Errorf("the anchor index of block %d does not match the anchor chain", meta.Index)
+	}
+	return nil
+}
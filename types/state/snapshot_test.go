@@ -0,0 +1,146 @@
+package state_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+	"github.com/AccumulateNetwork/accumulate/types"
+	"github.com/AccumulateNetwork/accumulate/types/api/transactions"
+	. "github.com/AccumulateNetwork/accumulate/types/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	src := new(StateDB)
+	require.NoError(t, src.Open("memory", "", storage.Options{}, false))
+
+	gtx := new(transactions.GenTransaction)
+	gtx.SigInfo = new(transactions.SignatureInfo)
+	gtx.SigInfo.URL = "RedWagon/myAccount"
+	gtx.Transaction = []byte("transaction")
+	txId := gtx.TransactionHash()
+	accepted, pending := NewTransaction(NewPendingTransaction(gtx))
+	pendingData, err := pending.MarshalBinary()
+	require.NoError(t, err)
+	acceptedData, err := accepted.MarshalBinary()
+	require.NoError(t, err)
+
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	for height := int64(1); height <= 3; height++ {
+		dbTx := src.Begin()
+		if height == 2 {
+			require.NoError(t, dbTx.AddTransaction(&chainId, txId, &Object{Entry: pendingData}, &Object{Entry: acceptedData}))
+			dbTx.WriteIndex(DirectoryIndex, chainId[:], "Metadata", []byte("index"))
+			dbTx.WriteRecord(SyntheticTransactionCountKey(), []byte{1})
+		}
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	data, err := src.Snapshot()
+	require.NoError(t, err)
+
+	// A snapshot that does not match the app hash is rejected and nothing is
+	// written
+	dst := new(StateDB)
+	require.NoError(t, dst.Open("memory", "", storage.Options{}, false))
+	require.Error(t, dst.RestoreSnapshot(data, make([]byte, 32)))
+	_, err = dst.BlockIndex()
+	require.True(t, errors.Is(err, storage.ErrNotFound), "expected not found, got %v", err)
+
+	// A corrupted snapshot is rejected
+	bad := append([]byte{}, data...)
+	bad[len(bad)-1]++
+	require.Error(t, dst.RestoreSnapshot(bad, src.RootHash()))
+
+	// A snapshot with a record that is not part of the state is rejected
+	rec := &SnapshotRecord{Key: IndexKey(DirectoryIndex, chainId[:], "Metadata"), Value: []byte("forged")}
+	b, err := rec.MarshalBinary()
+	require.NoError(t, err)
+	require.Error(t, dst.RestoreSnapshot(append(append([]byte{}, data...), b...), src.RootHash()))
+	_, err = dst.GetIndex(DirectoryIndex, chainId[:], "Metadata")
+	require.True(t, errors.Is(err, storage.ErrNotFound), "expected not found, got %v", err)
+
+	require.NoError(t, dst.RestoreSnapshot(data, src.RootHash()))
+	require.Equal(t, src.RootHash(), dst.RootHash())
+
+	height, err := dst.BlockIndex()
+	require.NoError(t, err)
+	require.Equal(t, int64(3), height)
+
+	srcEntry, err := src.GetPersistentEntry(chainId[:], false)
+	require.NoError(t, err)
+	dstEntry, err := dst.GetPersistentEntry(chainId[:], false)
+	require.NoError(t, err)
+	require.Equal(t, srcEntry, dstEntry)
+
+	_, err = dst.GetTx(txId)
+	require.NoError(t, err)
+
+	count, err := dst.ReadRecord(SyntheticTransactionCountKey())
+	require.NoError(t, err)
+	require.Equal(t, []byte{1}, count)
+
+	// Indexes are not part of a snapshot
+	_, err = dst.GetIndex(DirectoryIndex, chainId[:], "Metadata")
+	require.True(t, errors.Is(err, storage.ErrNotFound), "expected not found, got %v", err)
+
+	// The restored state can be built upon
+	dbTx := dst.Begin()
+	dbTx.AddStateEntry(&chainId, &types.Bytes32{4}, &Object{Entry: []byte{4}})
+	_, err = dbTx.Commit(4, time.Unix(4, 0))
+	require.NoError(t, err)
+	dbTx = src.Begin()
+	dbTx.AddStateEntry(&chainId, &types.Bytes32{4}, &Object{Entry: []byte{4}})
+	_, err = dbTx.Commit(4, time.Unix(4, 0))
+	require.NoError(t, err)
+	require.Equal(t, src.RootHash(), dst.RootHash())
+
+	// The chains of the restored state are verified from the snapshot
+	dst.VerifyChains(func(problem error) { t.Error(problem) })
+}
+
+func TestSnapshotView(t *testing.T) {
+	src := new(StateDB)
+	require.NoError(t, src.Open("memory", "", storage.Options{}, false))
+
+	chainId := types.Bytes32(sha256.Sum256([]byte("RedWagon/myAccount")))
+	commit := func(height int64) {
+		dbTx := src.Begin()
+		dbTx.AddStateEntry(&chainId, &types.Bytes32{byte(height)}, &Object{Entry: []byte{byte(height)}})
+		_, err := dbTx.Commit(height, time.Unix(height, 0))
+		require.NoError(t, err)
+	}
+
+	commit(1)
+	commit(2)
+	rootHash := src.RootHash()
+	view, err := src.SnapshotView()
+	require.NoError(t, err)
+	defer view.Close()
+
+	// Blocks committed after the view is taken are not part of the snapshot
+	commit(3)
+
+	height, err := view.BlockIndex()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+
+	buf := new(bytes.Buffer)
+	n, err := view.WriteTo(buf)
+	require.NoError(t, err)
+	require.Equal(t, int64(buf.Len()), n)
+
+	dst := new(StateDB)
+	require.NoError(t, dst.Open("memory", "", storage.Options{}, false))
+	require.NoError(t, dst.RestoreSnapshot(buf.Bytes(), rootHash))
+
+	height, err = dst.BlockIndex()
+	require.NoError(t, err)
+	require.Equal(t, int64(2), height)
+}
@@ -10,7 +10,10 @@ type DBTransaction struct {
 	state        *StateDB
 	updates      map[types.Bytes32]*blockUpdates
 	writes       map[storage.Key][]byte
+	records      map[[32]byte][]byte
 	transactions transactionLists
+	majorAnchor  *MajorAnchorMetadata // the major block closed by the commit, if any
+	versions     []types.Bytes32      // the chains whose versions were written by the commit
 }
 
 func (s *StateDB) Begin() *DBTransaction {
@@ -19,6 +22,7 @@ func (s *StateDB) Begin() *DBTransaction {
 	}
 	dbTx.updates = make(map[types.Bytes32]*blockUpdates)
 	dbTx.writes = map[storage.Key][]byte{}
+	dbTx.records = map[[32]byte][]byte{}
 	dbTx.transactions.reset()
 	return dbTx
 }
@@ -6,6 +6,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/AccumulateNetwork/accumulate/internal/encoding"
@@ -16,6 +17,29 @@ type AnchorMetadata struct {
 	PreviousHeight int64      `json:"previousHeight,omitempty" form:"previousHeight" query:"previousHeight" validate:"required"`
 	Timestamp      time.Time  `json:"timestamp,omitempty" form:"timestamp" query:"timestamp" validate:"required"`
 	Chains         [][32]byte `json:"chains,omitempty" form:"chains" query:"chains" validate:"required"`
+	Root           [32]byte   `json:"root,omitempty" form:"root" query:"root" validate:"required"`
+}
+
+type DumpRecord struct {
+	Kind  string   `json:"kind,omitempty" form:"kind" query:"kind" validate:"required"`
+	Chain []byte   `json:"chain,omitempty" form:"chain" query:"chain" validate:"required"`
+	Index int64    `json:"index,omitempty" form:"index" query:"index" validate:"required"`
+	Key   [32]byte `json:"key,omitempty" form:"key" query:"key" validate:"required"`
+	Value []byte   `json:"value,omitempty" form:"value" query:"value" validate:"required"`
+}
+
+type MajorAnchorMetadata struct {
+	Index      int64     `json:"index,omitempty" form:"index" query:"index" validate:"required"`
+	LastBlock  int64     `json:"lastBlock,omitempty" form:"lastBlock" query:"lastBlock" validate:"required"`
+	Timestamp  time.Time `json:"timestamp,omitempty" form:"timestamp" query:"timestamp" validate:"required"`
+	MinorStart int64     `json:"minorStart,omitempty" form:"minorStart" query:"minorStart" validate:"required"`
+	MinorEnd   int64     `json:"minorEnd,omitempty" form:"minorEnd" query:"minorEnd" validate:"required"`
+	MinorRoot  [32]byte  `json:"minorRoot,omitempty" form:"minorRoot" query:"minorRoot" validate:"required"`
+}
+
+type MajorBlockSchedule struct {
+	Blocks int64         `json:"blocks,omitempty" form:"blocks" query:"blocks" validate:"required"`
+	Period time.Duration `json:"period,omitempty" form:"period" query:"period" validate:"required"`
 }
 
 type Object struct {
@@ -24,6 +48,22 @@ type Object struct {
 	Roots  [][]byte `json:"roots,omitempty" form:"roots" query:"roots" validate:"required"`
 }
 
+type SnapshotRecord struct {
+	Key   [32]byte `json:"key,omitempty" form:"key" query:"key" validate:"required"`
+	Value []byte   `json:"value,omitempty" form:"value" query:"value" validate:"required"`
+}
+
+type StateVersion struct {
+	Entry []byte `json:"entry,omitempty" form:"entry" query:"entry" validate:"required"`
+}
+
+type VestingSchedule struct {
+	Locked   big.Int       `json:"locked,omitempty" form:"locked" query:"locked" validate:"required"`
+	Start    time.Time     `json:"start,omitempty" form:"start" query:"start" validate:"required"`
+	Cliff    time.Duration `json:"cliff,omitempty" form:"cliff" query:"cliff" validate:"required"`
+	Duration time.Duration `json:"duration,omitempty" form:"duration" query:"duration" validate:"required"`
+}
+
 func (v *AnchorMetadata) BinarySize() int {
 	var n int
 
@@ -35,6 +75,52 @@ func (v *AnchorMetadata) BinarySize() int {
 
 	n += encoding.ChainSetBinarySize(v.Chains)
 
+	n += encoding.ChainBinarySize(&v.Root)
+
+	return n
+}
+
+func (v *DumpRecord) BinarySize() int {
+	var n int
+
+	n += encoding.StringBinarySize(v.Kind)
+
+	n += encoding.BytesBinarySize(v.Chain)
+
+	n += encoding.VarintBinarySize(v.Index)
+
+	n += encoding.ChainBinarySize(&v.Key)
+
+	n += encoding.BytesBinarySize(v.Value)
+
+	return n
+}
+
+func (v *MajorAnchorMetadata) BinarySize() int {
+	var n int
+
+	n += encoding.VarintBinarySize(v.Index)
+
+	n += encoding.VarintBinarySize(v.LastBlock)
+
+	n += encoding.TimeBinarySize(v.Timestamp)
+
+	n += encoding.VarintBinarySize(v.MinorStart)
+
+	n += encoding.VarintBinarySize(v.MinorEnd)
+
+	n += encoding.ChainBinarySize(&v.MinorRoot)
+
+	return n
+}
+
+func (v *MajorBlockSchedule) BinarySize() int {
+	var n int
+
+	n += encoding.VarintBinarySize(v.Blocks)
+
+	n += encoding.DurationBinarySize(v.Period)
+
 	return n
 }
 
@@ -55,6 +141,38 @@ func (v *Object) BinarySize() int {
 	return n
 }
 
+func (v *SnapshotRecord) BinarySize() int {
+	var n int
+
+	n += encoding.ChainBinarySize(&v.Key)
+
+	n += encoding.BytesBinarySize(v.Value)
+
+	return n
+}
+
+func (v *StateVersion) BinarySize() int {
+	var n int
+
+	n += encoding.BytesBinarySize(v.Entry)
+
+	return n
+}
+
+func (v *VestingSchedule) BinarySize() int {
+	var n int
+
+	n += encoding.BigintBinarySize(&v.Locked)
+
+	n += encoding.TimeBinarySize(v.Start)
+
+	n += encoding.DurationBinarySize(v.Cliff)
+
+	n += encoding.DurationBinarySize(v.Duration)
+
+	return n
+}
+
 func (v *AnchorMetadata) MarshalBinary() ([]byte, error) {
 	var buffer bytes.Buffer
 
@@ -66,6 +184,52 @@ func (v *AnchorMetadata) MarshalBinary() ([]byte, error) {
 
 	buffer.Write(encoding.ChainSetMarshalBinary(v.Chains))
 
+	buffer.Write(encoding.ChainMarshalBinary(&v.Root))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *DumpRecord) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.StringMarshalBinary(v.Kind))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Chain))
+
+	buffer.Write(encoding.VarintMarshalBinary(v.Index))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Key))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Value))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *MajorAnchorMetadata) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.VarintMarshalBinary(v.Index))
+
+	buffer.Write(encoding.VarintMarshalBinary(v.LastBlock))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Timestamp))
+
+	buffer.Write(encoding.VarintMarshalBinary(v.MinorStart))
+
+	buffer.Write(encoding.VarintMarshalBinary(v.MinorEnd))
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.MinorRoot))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *MajorBlockSchedule) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.VarintMarshalBinary(v.Blocks))
+
+	buffer.Write(encoding.DurationMarshalBinary(v.Period))
+
 	return buffer.Bytes(), nil
 }
 
@@ -86,6 +250,38 @@ func (v *Object) MarshalBinary() ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
+func (v *SnapshotRecord) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.ChainMarshalBinary(&v.Key))
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Value))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *StateVersion) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.BytesMarshalBinary(v.Entry))
+
+	return buffer.Bytes(), nil
+}
+
+func (v *VestingSchedule) MarshalBinary() ([]byte, error) {
+	var buffer bytes.Buffer
+
+	buffer.Write(encoding.BigintMarshalBinary(&v.Locked))
+
+	buffer.Write(encoding.TimeMarshalBinary(v.Start))
+
+	buffer.Write(encoding.DurationMarshalBinary(v.Cliff))
+
+	buffer.Write(encoding.DurationMarshalBinary(v.Duration))
+
+	return buffer.Bytes(), nil
+}
+
 func (v *AnchorMetadata) UnmarshalBinary(data []byte) error {
 	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
 		return fmt.Errorf("error decoding Index: %w", err)
@@ -115,6 +311,116 @@ func (v *AnchorMetadata) UnmarshalBinary(data []byte) error {
 	}
 	data = data[encoding.ChainSetBinarySize(v.Chains):]
 
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Root: %w", err)
+	} else {
+		v.Root = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Root):]
+
+	return nil
+}
+
+func (v *DumpRecord) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.StringUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Kind: %w", err)
+	} else {
+		v.Kind = x
+	}
+	data = data[encoding.StringBinarySize(v.Kind):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Chain: %w", err)
+	} else {
+		v.Chain = x
+	}
+	data = data[encoding.BytesBinarySize(v.Chain):]
+
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Index: %w", err)
+	} else {
+		v.Index = x
+	}
+	data = data[encoding.VarintBinarySize(v.Index):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Key: %w", err)
+	} else {
+		v.Key = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Key):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Value: %w", err)
+	} else {
+		v.Value = x
+	}
+	data = data[encoding.BytesBinarySize(v.Value):]
+
+	return nil
+}
+
+func (v *MajorAnchorMetadata) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Index: %w", err)
+	} else {
+		v.Index = x
+	}
+	data = data[encoding.VarintBinarySize(v.Index):]
+
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding LastBlock: %w", err)
+	} else {
+		v.LastBlock = x
+	}
+	data = data[encoding.VarintBinarySize(v.LastBlock):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Timestamp: %w", err)
+	} else {
+		v.Timestamp = x
+	}
+	data = data[encoding.TimeBinarySize(v.Timestamp):]
+
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding MinorStart: %w", err)
+	} else {
+		v.MinorStart = x
+	}
+	data = data[encoding.VarintBinarySize(v.MinorStart):]
+
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding MinorEnd: %w", err)
+	} else {
+		v.MinorEnd = x
+	}
+	data = data[encoding.VarintBinarySize(v.MinorEnd):]
+
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding MinorRoot: %w", err)
+	} else {
+		v.MinorRoot = x
+	}
+	data = data[encoding.ChainBinarySize(&v.MinorRoot):]
+
+	return nil
+}
+
+func (v *MajorBlockSchedule) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.VarintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Blocks: %w", err)
+	} else {
+		v.Blocks = x
+	}
+	data = data[encoding.VarintBinarySize(v.Blocks):]
+
+	if x, err := encoding.DurationUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Period: %w", err)
+	} else {
+		v.Period = x
+	}
+	data = data[encoding.DurationBinarySize(v.Period):]
+
 	return nil
 }
 
@@ -155,17 +461,124 @@ func (v *Object) UnmarshalBinary(data []byte) error {
 	return nil
 }
 
+func (v *SnapshotRecord) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.ChainUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Key: %w", err)
+	} else {
+		v.Key = x
+	}
+	data = data[encoding.ChainBinarySize(&v.Key):]
+
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Value: %w", err)
+	} else {
+		v.Value = x
+	}
+	data = data[encoding.BytesBinarySize(v.Value):]
+
+	return nil
+}
+
+func (v *StateVersion) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.BytesUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Entry: %w", err)
+	} else {
+		v.Entry = x
+	}
+	data = data[encoding.BytesBinarySize(v.Entry):]
+
+	return nil
+}
+
+func (v *VestingSchedule) UnmarshalBinary(data []byte) error {
+	if x, err := encoding.BigintUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Locked: %w", err)
+	} else {
+		v.Locked.Set(x)
+	}
+	data = data[encoding.BigintBinarySize(&v.Locked):]
+
+	if x, err := encoding.TimeUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Start: %w", err)
+	} else {
+		v.Start = x
+	}
+	data = data[encoding.TimeBinarySize(v.Start):]
+
+	if x, err := encoding.DurationUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Cliff: %w", err)
+	} else {
+		v.Cliff = x
+	}
+	data = data[encoding.DurationBinarySize(v.Cliff):]
+
+	if x, err := encoding.DurationUnmarshalBinary(data); err != nil {
+		return fmt.Errorf("error decoding Duration: %w", err)
+	} else {
+		v.Duration = x
+	}
+	data = data[encoding.DurationBinarySize(v.Duration):]
+
+	return nil
+}
+
 func (v *AnchorMetadata) MarshalJSON() ([]byte, error) {
 	u := struct {
 		Index          int64     `json:"index,omitempty"`
 		PreviousHeight int64     `json:"previousHeight,omitempty"`
 		Timestamp      time.Time `json:"timestamp,omitempty"`
 		Chains         []string  `json:"chains,omitempty"`
+		Root           string    `json:"root,omitempty"`
 	}{}
 	u.Index = v.Index
 	u.PreviousHeight = v.PreviousHeight
 	u.Timestamp = v.Timestamp
 	u.Chains = encoding.ChainSetToJSON(v.Chains)
+	u.Root = encoding.ChainToJSON(v.Root)
+	return json.Marshal(&u)
+}
+
+func (v *DumpRecord) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Kind  string  `json:"kind,omitempty"`
+		Chain *string `json:"chain,omitempty"`
+		Index int64   `json:"index,omitempty"`
+		Key   string  `json:"key,omitempty"`
+		Value *string `json:"value,omitempty"`
+	}{}
+	u.Kind = v.Kind
+	u.Chain = encoding.BytesToJSON(v.Chain)
+	u.Index = v.Index
+	u.Key = encoding.ChainToJSON(v.Key)
+	u.Value = encoding.BytesToJSON(v.Value)
+	return json.Marshal(&u)
+}
+
+func (v *MajorAnchorMetadata) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Index      int64     `json:"index,omitempty"`
+		LastBlock  int64     `json:"lastBlock,omitempty"`
+		Timestamp  time.Time `json:"timestamp,omitempty"`
+		MinorStart int64     `json:"minorStart,omitempty"`
+		MinorEnd   int64     `json:"minorEnd,omitempty"`
+		MinorRoot  string    `json:"minorRoot,omitempty"`
+	}{}
+	u.Index = v.Index
+	u.LastBlock = v.LastBlock
+	u.Timestamp = v.Timestamp
+	u.MinorStart = v.MinorStart
+	u.MinorEnd = v.MinorEnd
+	u.MinorRoot = encoding.ChainToJSON(v.MinorRoot)
+	return json.Marshal(&u)
+}
+
+func (v *MajorBlockSchedule) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Blocks int64       `json:"blocks,omitempty"`
+		Period interface{} `json:"period,omitempty"`
+	}{}
+	u.Blocks = v.Blocks
+	u.Period = encoding.DurationToJSON(v.Period)
 	return json.Marshal(&u)
 }
 
@@ -184,17 +597,51 @@ func (v *Object) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&u)
 }
 
+func (v *SnapshotRecord) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Key   string  `json:"key,omitempty"`
+		Value *string `json:"value,omitempty"`
+	}{}
+	u.Key = encoding.ChainToJSON(v.Key)
+	u.Value = encoding.BytesToJSON(v.Value)
+	return json.Marshal(&u)
+}
+
+func (v *StateVersion) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Entry *string `json:"entry,omitempty"`
+	}{}
+	u.Entry = encoding.BytesToJSON(v.Entry)
+	return json.Marshal(&u)
+}
+
+func (v *VestingSchedule) MarshalJSON() ([]byte, error) {
+	u := struct {
+		Locked   big.Int     `json:"locked,omitempty"`
+		Start    time.Time   `json:"start,omitempty"`
+		Cliff    interface{} `json:"cliff,omitempty"`
+		Duration interface{} `json:"duration,omitempty"`
+	}{}
+	u.Locked = v.Locked
+	u.Start = v.Start
+	u.Cliff = encoding.DurationToJSON(v.Cliff)
+	u.Duration = encoding.DurationToJSON(v.Duration)
+	return json.Marshal(&u)
+}
+
 func (v *AnchorMetadata) UnmarshalJSON(data []byte) error {
 	u := struct {
 		Index          int64     `json:"index,omitempty"`
 		PreviousHeight int64     `json:"previousHeight,omitempty"`
 		Timestamp      time.Time `json:"timestamp,omitempty"`
 		Chains         []string  `json:"chains,omitempty"`
+		Root           string    `json:"root,omitempty"`
 	}{}
 	u.Index = v.Index
 	u.PreviousHeight = v.PreviousHeight
 	u.Timestamp = v.Timestamp
 	u.Chains = encoding.ChainSetToJSON(v.Chains)
+	u.Root = encoding.ChainToJSON(v.Root)
 	if err := json.Unmarshal(data, &u); err != nil {
 		return err
 	}
@@ -206,6 +653,97 @@ func (v *AnchorMetadata) UnmarshalJSON(data []byte) error {
 	} else {
 		v.Chains = x
 	}
+	if x, err := encoding.ChainFromJSON(u.Root); err != nil {
+		return fmt.Errorf("error decoding Root: %w", err)
+	} else {
+		v.Root = x
+	}
+	return nil
+}
+
+func (v *DumpRecord) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Kind  string  `json:"kind,omitempty"`
+		Chain *string `json:"chain,omitempty"`
+		Index int64   `json:"index,omitempty"`
+		Key   string  `json:"key,omitempty"`
+		Value *string `json:"value,omitempty"`
+	}{}
+	u.Kind = v.Kind
+	u.Chain = encoding.BytesToJSON(v.Chain)
+	u.Index = v.Index
+	u.Key = encoding.ChainToJSON(v.Key)
+	u.Value = encoding.BytesToJSON(v.Value)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Kind = u.Kind
+	if x, err := encoding.BytesFromJSON(u.Chain); err != nil {
+		return fmt.Errorf("error decoding Chain: %w", err)
+	} else {
+		v.Chain = x
+	}
+	v.Index = u.Index
+	if x, err := encoding.ChainFromJSON(u.Key); err != nil {
+		return fmt.Errorf("error decoding Key: %w", err)
+	} else {
+		v.Key = x
+	}
+	if x, err := encoding.BytesFromJSON(u.Value); err != nil {
+		return fmt.Errorf("error decoding Value: %w", err)
+	} else {
+		v.Value = x
+	}
+	return nil
+}
+
+func (v *MajorAnchorMetadata) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Index      int64     `json:"index,omitempty"`
+		LastBlock  int64     `json:"lastBlock,omitempty"`
+		Timestamp  time.Time `json:"timestamp,omitempty"`
+		MinorStart int64     `json:"minorStart,omitempty"`
+		MinorEnd   int64     `json:"minorEnd,omitempty"`
+		MinorRoot  string    `json:"minorRoot,omitempty"`
+	}{}
+	u.Index = v.Index
+	u.LastBlock = v.LastBlock
+	u.Timestamp = v.Timestamp
+	u.MinorStart = v.MinorStart
+	u.MinorEnd = v.MinorEnd
+	u.MinorRoot = encoding.ChainToJSON(v.MinorRoot)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Index = u.Index
+	v.LastBlock = u.LastBlock
+	v.Timestamp = u.Timestamp
+	v.MinorStart = u.MinorStart
+	v.MinorEnd = u.MinorEnd
+	if x, err := encoding.ChainFromJSON(u.MinorRoot); err != nil {
+		return fmt.Errorf("error decoding MinorRoot: %w", err)
+	} else {
+		v.MinorRoot = x
+	}
+	return nil
+}
+
+func (v *MajorBlockSchedule) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Blocks int64       `json:"blocks,omitempty"`
+		Period interface{} `json:"period,omitempty"`
+	}{}
+	u.Blocks = v.Blocks
+	u.Period = encoding.DurationToJSON(v.Period)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Blocks = u.Blocks
+	if x, err := encoding.DurationFromJSON(u.Period); err != nil {
+		return fmt.Errorf("error decoding Period: %w", err)
+	} else {
+		v.Period = x
+	}
 	return nil
 }
 
@@ -240,3 +778,71 @@ func (v *Object) UnmarshalJSON(data []byte) error {
 	}
 	return nil
 }
+
+func (v *SnapshotRecord) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Key   string  `json:"key,omitempty"`
+		Value *string `json:"value,omitempty"`
+	}{}
+	u.Key = encoding.ChainToJSON(v.Key)
+	u.Value = encoding.BytesToJSON(v.Value)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.ChainFromJSON(u.Key); err != nil {
+		return fmt.Errorf("error decoding Key: %w", err)
+	} else {
+		v.Key = x
+	}
+	if x, err := encoding.BytesFromJSON(u.Value); err != nil {
+		return fmt.Errorf("error decoding Value: %w", err)
+	} else {
+		v.Value = x
+	}
+	return nil
+}
+
+func (v *StateVersion) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Entry *string `json:"entry,omitempty"`
+	}{}
+	u.Entry = encoding.BytesToJSON(v.Entry)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	if x, err := encoding.BytesFromJSON(u.Entry); err != nil {
+		return fmt.Errorf("error decoding Entry: %w", err)
+	} else {
+		v.Entry = x
+	}
+	return nil
+}
+
+func (v *VestingSchedule) UnmarshalJSON(data []byte) error {
+	u := struct {
+		Locked   big.Int     `json:"locked,omitempty"`
+		Start    time.Time   `json:"start,omitempty"`
+		Cliff    interface{} `json:"cliff,omitempty"`
+		Duration interface{} `json:"duration,omitempty"`
+	}{}
+	u.Locked = v.Locked
+	u.Start = v.Start
+	u.Cliff = encoding.DurationToJSON(v.Cliff)
+	u.Duration = encoding.DurationToJSON(v.Duration)
+	if err := json.Unmarshal(data, &u); err != nil {
+		return err
+	}
+	v.Locked = u.Locked
+	v.Start = u.Start
+	if x, err := encoding.DurationFromJSON(u.Cliff); err != nil {
+		return fmt.Errorf("error decoding Cliff: %w", err)
+	} else {
+		v.Cliff = x
+	}
+	if x, err := encoding.DurationFromJSON(u.Duration); err != nil {
+		return fmt.Errorf("error decoding Duration: %w", err)
+	} else {
+		v.Duration = x
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/AccumulateNetwork/accumulate/smt/pmt"
+	"github.com/AccumulateNetwork/accumulate/smt/storage"
+)
+
+// Verify checks the consistency of the database and calls fn for each problem
+// it finds. Verify checks every Merkle chain with VerifyChains, compares the
+// head of each chain with the chain's state entry, checks that every value of
+// the BPT is the hash of a record, recomputes the hashes of the BPT, and
+// compares the root of the BPT with the root hash
+// recorded by the last commit. Verify only returns an error if it cannot
+// continue.
+func (s *StateDB) Verify(fn func(problem error)) error {
+	s.VerifyChains(fn)
+
+	err := s.bpt.Walk(func(e pmt.Entry) error {
+		v, ok := e.(*pmt.Value)
+		if !ok {
+			return nil
+		}
+
+		key, value, err := s.leafRecord(v.Key, v.Hash)
+		if err != nil {
+			fn(err)
+			return nil
+		}
+
+		if key == storage.ComputeKey(bucketEntry, v.Key[:]) {
+			err = s.verifyChainHead(v.Key, value)
+			if err != nil {
+				fn(err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk the BPT: %v", err)
+	}
+
+	err = s.bpt.Verify()
+	if err != nil {
+		fn(fmt.Errorf("invalid BPT: %v", err))
+	}
+
+	err = s.checkCommit()
+	if err != nil {
+		fn(err)
+	}
+	return nil
+}
+
+// VerifyChains replays the elements of every Merkle chain and checks them
+// against the element index, the mark states, and the head of the chain. fn
+// is called once for each chain that is not consistent, with a
+// *managed.ChainError that holds the first inconsistent index, or with the
+// error that stopped the chain from being read.
+func (s *StateDB) VerifyChains(fn func(problem error)) {
+	s.Sync()
+
+	for _, chainId := range s.chainIds() {
+		err := s.mm.VerifyChain(chainId)
+		if err != nil {
+			fn(err)
+		}
+	}
+}
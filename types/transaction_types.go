@@ -81,6 +81,18 @@ const (
 	// TxTypeUpdateKeyPage adds, removes, or updates keys in a key page, which
 	// *does not* produce a synthetic transaction.
 	TxTypeUpdateKeyPage TransactionType = 0x0F
+
+	// TxTypeSwapOffer escrows tokens in an offer to swap them for another
+	// token, which *does not* produce a synthetic transaction.
+	TxTypeSwapOffer TransactionType = 0x10
+
+	// TxTypeSwapAccept accepts a swap offer, exchanging the tokens of both
+	// parties, which *does not* produce a synthetic transaction.
+	TxTypeSwapAccept TransactionType = 0x11
+
+	// TxTypeSwapCancel cancels a swap offer and releases the escrowed tokens,
+	// which *does not* produce a synthetic transaction.
+	TxTypeSwapCancel TransactionType = 0x12
 )
 
 // System transactions
@@ -132,6 +144,12 @@ func (t TransactionType) String() string {
 		return "addCredits"
 	case TxTypeUpdateKeyPage:
 		return "updateKeyPage"
+	case TxTypeSwapOffer:
+		return "swapOffer"
+	case TxTypeSwapAccept:
+		return "swapAccept"
+	case TxTypeSwapCancel:
+		return "swapCancel"
 	case TxTypeSyntheticCreateChain:
 		return "syntheticCreateChain"
 	case TxTypeSyntheticDepositTokens: